@@ -0,0 +1,176 @@
+// Command migrate runs the SQL migrations of a service using the same DBConfig
+// env variables (and .env files) as udatabase.NewDBConfigFromEnv.
+//
+// Usage:
+//
+//	migrate [-dir migrationsDir] <command> [arg]
+//
+// Commands:
+//
+//	up [N]        apply all or N up migrations
+//	down [N]      apply all or N down migrations
+//	goto V        migrate to version V
+//	version       print the current migration version
+//	force V       set version V without running migrations (dirty state is cleared)
+//	create NAME   create a timestamped pair of up/down migration files
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/usql"
+	"github.com/golang-migrate/migrate/v4"
+)
+
+const usage = `Usage: migrate [-dir migrationsDir] <command> [arg]
+
+Commands:
+  up [N]        apply all or N up migrations
+  down [N]      apply all or N down migrations
+  goto V        migrate to version V
+  version       print the current migration version
+  force V       set version V without running migrations (dirty state is cleared)
+  create NAME   create a timestamped pair of up/down migration files
+
+The database is configured with the POSTGRES_* env variables, which can also
+be defined in .env files.
+`
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	dir := flag.String("dir", "", "migrations directory (overrides POSTGRES_MIGRATIONS_DIR)")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := udatabase.NewDBConfigFromEnv()
+	if *dir != "" {
+		cfg.MigrationsDir = *dir
+	}
+
+	if err := run(cfg, flag.Arg(0), flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg *udatabase.DBConfig, cmd string, args []string) error {
+	if cmd == "create" {
+		if len(args) != 1 {
+			return errors.New("create requires the name of the migration")
+		}
+
+		up, down, err := udatabase.CreateMigration(cfg.MigrationsDir, args[0], time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Println(up)
+		fmt.Println(down)
+		return nil
+	}
+
+	dbHolder := usql.NewDBHolder(cfg)
+	defer dbHolder.GetDBInstance().Close()
+
+	m, err := udatabase.NewMigrate(dbHolder.GetDBInstance().DB, cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = runMigrate(m, cmd, args)
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		return nil
+	}
+	return err
+}
+
+func runMigrate(m *migrate.Migrate, cmd string, args []string) error {
+	switch cmd {
+	case "up":
+		n, err := optionalNumArg(args)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return m.Up()
+		}
+		return m.Steps(n)
+	case "down":
+		n, err := optionalNumArg(args)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return m.Down()
+		}
+		return m.Steps(-n)
+	case "goto":
+		v, err := requiredNumArg(cmd, args)
+		if err != nil {
+			return err
+		}
+		return m.Migrate(uint(v))
+	case "force":
+		v, err := requiredNumArg(cmd, args)
+		if err != nil {
+			return err
+		}
+		return m.Force(v)
+	case "version":
+		v, dirty, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			fmt.Println("no migrations applied")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if dirty {
+			fmt.Printf("%d (dirty)\n", v)
+		} else {
+			fmt.Println(v)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func optionalNumArg(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number of migrations %q", args[0])
+	}
+	return n, nil
+}
+
+func requiredNumArg(cmd string, args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%s requires a version", cmd)
+	}
+
+	v, err := strconv.Atoi(args[0])
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid version %q", args[0])
+	}
+	return v, nil
+}
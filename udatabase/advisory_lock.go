@@ -0,0 +1,77 @@
+package udatabase
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"time"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+const advisoryLockRetryInterval = 100 * time.Millisecond
+
+// AdvisoryLockKey returns a key for Postgres advisory locks derived from name.
+func AdvisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	// nolint:gosec // overflow is expected, the key only needs to be stable.
+	return int64(h.Sum64())
+}
+
+// MigrationsLockKey returns the advisory lock key used by RunMigrations for the given schema.
+func MigrationsLockKey(schemaName string) int64 {
+	return AdvisoryLockKey("udatabase_migrations:" + schemaName)
+}
+
+// WithAdvisoryLock runs fn while holding the Postgres session advisory lock identified by key.
+// It waits until the lock is acquired or ctx is done, in which case fn is not run and
+// an error is returned. The lock is released when fn returns, even if it panics.
+func WithAdvisoryLock(ctx context.Context, db *sql.DB, key int64, fn func(ctx context.Context) error) (rErr error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return uerr.NewError(uerr.GenericError, "Error acquiring advisory lock.").WithCause(err)
+	}
+	defer conn.Close()
+
+	err = acquireAdvisoryLock(ctx, conn, key)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		// The lock must be released even if ctx is already done.
+		_, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key)
+		if err != nil && rErr == nil {
+			rErr = uerr.NewError(uerr.GenericError, "Error releasing advisory lock.").WithCause(err)
+		}
+	}()
+
+	return fn(ctx)
+}
+
+func acquireAdvisoryLock(ctx context.Context, conn *sql.Conn, key int64) error {
+	ticker := time.NewTicker(advisoryLockRetryInterval)
+	defer ticker.Stop()
+
+	for {
+		var acquired bool
+		err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			return uerr.NewError(uerr.GenericError, "Error acquiring advisory lock.").WithCause(err)
+		}
+
+		if acquired {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return uerr.NewError(uerr.GenericError, "Timeout acquiring advisory lock.").WithCause(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package udatabase
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// CursorPage is a page of a keyset pagination. NextCursor and PrevCursor are passed as the cursor
+// parameter of the search to get the next and previous pages, and are empty when there are none.
+type CursorPage[T any] struct {
+	Limit      int64  `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	Resources  []T    `json:"resources"`
+}
+
+// Cursor is the position of a row in a keyset pagination: the values of the columns of
+// Sort, in the format of the sort parameter, of the row. Backward cursors select the rows
+// before the row instead of after it.
+type Cursor struct {
+	Sort     []string `json:"s"`
+	Values   []any    `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// randomCursorKey signs the cursors when DBConfig.CursorSecret is empty. It warns when it is
+// generated, since cursors signed with it are rejected by every other instance.
+var randomCursorKey = sync.OnceValue(func() []byte {
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	slog.Default().Warn("DBConfig.CursorSecret is empty, so cursors are signed with a random key " +
+		"and are only valid in this process")
+	return key
+})
+
+// CursorKey returns the key that signs the cursors of the repositories: CursorSecret, or a
+// random key generated, with a warning, the first time it is needed if it is empty, with which
+// cursors are only valid in the process that returned them.
+func (c *DBConfig) CursorKey() []byte {
+	if c.CursorSecret == "" {
+		return randomCursorKey()
+	}
+	return []byte(c.CursorSecret)
+}
+
+// EncodeCursor returns c encoded and signed with key. The values of c must be encodable as JSON.
+func EncodeCursor(key []byte, c *Cursor) (string, error) {
+	values := make([]any, len(c.Values))
+	for i, v := range c.Values {
+		if valuer, ok := v.(driver.Valuer); ok {
+			var err error
+			if v, err = valuer.Value(); err != nil {
+				return "", err
+			}
+		}
+		values[i] = v
+	}
+
+	payload, err := json.Marshal(Cursor{Sort: c.Sort, Values: values, Backward: c.Backward})
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(signCursor(key, payload)), nil
+}
+
+// DecodeCursor returns the cursor encoded in s by EncodeCursor with key. It returns nil if s is
+// empty, and a WrongInputParameterError if s is not a cursor signed with key or its sort is not sort.
+func DecodeCursor(key []byte, s string, sort []string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	invalidCursor := uerr.NewError(uerr.WrongInputParameterError, `Invalid value for "cursor".`)
+	encodedPayload, encodedSignature, ok := strings.Cut(s, ".")
+	if !ok {
+		return nil, invalidCursor
+	}
+
+	encoding := base64.RawURLEncoding
+	payload, err := encoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, invalidCursor.WithCause(err)
+	}
+	signature, err := encoding.DecodeString(encodedSignature)
+	if err != nil {
+		return nil, invalidCursor.WithCause(err)
+	}
+	if !hmac.Equal(signature, signCursor(key, payload)) {
+		return nil, invalidCursor
+	}
+
+	var c Cursor
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err = decoder.Decode(&c); err != nil {
+		return nil, invalidCursor.WithCause(err)
+	}
+
+	if !slices.Equal(c.Sort, sort) || len(c.Values) != len(sort) {
+		return nil, uerr.NewError(uerr.WrongInputParameterError, `The "cursor" does not match the sort of the search.`)
+	}
+
+	for i, v := range c.Values {
+		if n, ok := v.(json.Number); ok {
+			c.Values[i] = cursorNumber(n)
+		}
+	}
+	return &c, nil
+}
+
+func signCursor(key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// cursorNumber returns n as an int64 if it is an integer, otherwise as a float64.
+func cursorNumber(n json.Number) any {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, _ := n.Float64()
+	return f
+}
+
+// SetResources sets the page from resources, which are the rows found after cursor, or before it
+// when it is backward, in the order of the search and with up to Limit+1 rows to know if there
+// are more. cursor is nil for the first page. values returns the values of the columns of sort
+// of a resource, which are encoded in the cursors of the page signed with key.
+func (cp *CursorPage[T]) SetResources(key []byte, resources []T, cursor *Cursor, sort []string,
+	values func(T) ([]any, error)) error {
+	backward := cursor != nil && cursor.Backward
+	hasMore := cp.Limit > 0 && int64(len(resources)) > cp.Limit
+	if hasMore {
+		resources = resources[:cp.Limit]
+	}
+	if backward {
+		// Backward searches are sorted in the reverse order.
+		slices.Reverse(resources)
+	}
+
+	cp.Resources = resources
+	cp.NextCursor, cp.PrevCursor = "", ""
+	if len(resources) == 0 {
+		return nil
+	}
+
+	encode := func(resource T, backward bool) (string, error) {
+		v, err := values(resource)
+		if err != nil {
+			return "", err
+		}
+		return EncodeCursor(key, &Cursor{Sort: sort, Values: v, Backward: backward})
+	}
+
+	var err error
+	// Forward pages have a next page if there are more rows, and a previous one unless they are
+	// the first page. Backward pages come from a cursor after them, so the other way around.
+	if hasMore || backward {
+		if cp.NextCursor, err = encode(resources[len(resources)-1], false); err != nil {
+			return fmt.Errorf("encoding next cursor: %w", err)
+		}
+	}
+	if backward && hasMore || !backward && cursor != nil {
+		if cp.PrevCursor, err = encode(resources[0], true); err != nil {
+			return fmt.Errorf("encoding prev cursor: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package udatabase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	key := []byte("secret")
+	sort := []string{"-created_at", "id"}
+
+	t.Run("EncodedCursor_isDecoded", func(t *testing.T) {
+		// ARRANGE
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+		cursor := &Cursor{Sort: sort, Values: []any{createdAt, 42}, Backward: true}
+
+		// ACT
+		encoded, err := EncodeCursor(key, cursor)
+		require.NoError(t, err)
+		actual, err := DecodeCursor(key, encoded, sort)
+
+		// ASSERT
+		require.NoError(t, err)
+		expected := &Cursor{Sort: sort, Values: []any{createdAt.Format(time.RFC3339Nano), int64(42)}, Backward: true}
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("EmptyCursor_returnsNil", func(t *testing.T) {
+		// ACT
+		actual, err := DecodeCursor(key, "", sort)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("CursorSignedWithAnotherKey_returnsWrongInputParameterError", func(t *testing.T) {
+		// ARRANGE
+		encoded, err := EncodeCursor([]byte("another secret"), &Cursor{Sort: sort, Values: []any{"a", 1}})
+		require.NoError(t, err)
+
+		// ACT
+		_, err = DecodeCursor(key, encoded, sort)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("CursorOfAnotherSort_returnsWrongInputParameterError", func(t *testing.T) {
+		// ARRANGE
+		encoded, err := EncodeCursor(key, &Cursor{Sort: []string{"id"}, Values: []any{1}})
+		require.NoError(t, err)
+
+		// ACT
+		_, err = DecodeCursor(key, encoded, sort)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("MalformedCursor_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, err := DecodeCursor(key, "not a cursor", sort)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
+
+func TestCursorPageSetResources(t *testing.T) {
+	key := []byte("secret")
+	sort := []string{"id"}
+	values := func(id int) ([]any, error) { return []any{id}, nil }
+
+	decode := func(t *testing.T, encoded string) *Cursor {
+		t.Helper()
+		cursor, err := DecodeCursor(key, encoded, sort)
+		require.NoError(t, err)
+		return cursor
+	}
+
+	t.Run("FirstPageWithMoreResources_hasOnlyNextCursor", func(t *testing.T) {
+		// ARRANGE
+		cp := CursorPage[int]{Limit: 2}
+
+		// ACT
+		err := cp.SetResources(key, []int{1, 2, 3}, nil, sort, values)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2}, cp.Resources)
+		require.Empty(t, cp.PrevCursor)
+		require.Equal(t, &Cursor{Sort: sort, Values: []any{int64(2)}}, decode(t, cp.NextCursor))
+	})
+
+	t.Run("LastPage_hasOnlyPrevCursor", func(t *testing.T) {
+		// ARRANGE
+		cp := CursorPage[int]{Limit: 2}
+		cursor := &Cursor{Sort: sort, Values: []any{int64(2)}}
+
+		// ACT
+		err := cp.SetResources(key, []int{3}, cursor, sort, values)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []int{3}, cp.Resources)
+		require.Empty(t, cp.NextCursor)
+		require.Equal(t, &Cursor{Sort: sort, Values: []any{int64(3)}, Backward: true}, decode(t, cp.PrevCursor))
+	})
+
+	t.Run("BackwardPage_isReversed", func(t *testing.T) {
+		// ARRANGE
+		cp := CursorPage[int]{Limit: 2}
+		cursor := &Cursor{Sort: sort, Values: []any{int64(4)}, Backward: true}
+
+		// ACT
+		err := cp.SetResources(key, []int{3, 2, 1}, cursor, sort, values)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []int{2, 3}, cp.Resources)
+		require.Equal(t, &Cursor{Sort: sort, Values: []any{int64(3)}}, decode(t, cp.NextCursor))
+		require.Equal(t, &Cursor{Sort: sort, Values: []any{int64(2)}, Backward: true}, decode(t, cp.PrevCursor))
+	})
+}
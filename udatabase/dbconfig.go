@@ -3,9 +3,13 @@ package udatabase
 import (
 	"database/sql"
 	"fmt"
+	"net"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env"
 	"github.com/carlosarismendi/utils/dotenv"
+	"github.com/carlosarismendi/utils/udatabase/ident"
 )
 
 type DBConfig struct {
@@ -17,6 +21,47 @@ type DBConfig struct {
 	SchemaName           string `env:"POSTGRES_SCHEMA" envDefault:"public"`
 	MigrationsDir        string `env:"POSTGRES_MIGRATIONS_DIR" envDefault:"./migrations"`
 	RunMigrationsOnReset bool   `env:"POSTGRES_RUN_MIGRATIONS" envDefault:"false"`
+
+	// MigrationsLock makes RunMigrations hold an advisory lock while migrating, so
+	// only one of several instances starting at the same time runs the migrations.
+	MigrationsLock        bool          `env:"POSTGRES_MIGRATIONS_LOCK" envDefault:"false"`
+	MigrationsLockTimeout time.Duration `env:"POSTGRES_MIGRATIONS_LOCK_TIMEOUT" envDefault:"1m"`
+
+	// ReplicaHosts is a list of read replicas in the form host or host:port. When the
+	// port is omitted, Port is used. DBHolders route read queries to them in round-robin.
+	ReplicaHosts []string `env:"POSTGRES_REPLICA_HOSTS" envSeparator:","`
+
+	// TenantSchemaPrefix is prepended to tenant IDs to build the name of their schemas.
+	TenantSchemaPrefix string `env:"POSTGRES_TENANT_SCHEMA_PREFIX" envDefault:"tenant_"`
+
+	// LogQueries makes DBHolders log every query at debug level with slog.Default().
+	LogQueries bool `env:"POSTGRES_LOG_QUERIES" envDefault:"false"`
+
+	// SlowQueryThreshold makes DBHolders log with slog.Default(), at warn level, the queries
+	// that take longer than it. 0 disables it.
+	SlowQueryThreshold time.Duration `env:"POSTGRES_SLOW_QUERY_THRESHOLD" envDefault:"0s"`
+
+	// LogQueryArgs makes the logs of LogQueries and SlowQueryThreshold include the arguments
+	// of the queries, which are redacted otherwise.
+	LogQueryArgs bool `env:"POSTGRES_LOG_QUERY_ARGS" envDefault:"false"`
+
+	// CursorSecret signs the cursors of keyset paginations, see CursorKey. It must be set, and
+	// shared by every instance that serves the same clients, when there is more than one
+	// instance or cursors must survive restarts: when it is empty, each process signs the
+	// cursors with its own random key and rejects those of the others.
+	CursorSecret string `env:"POSTGRES_CURSOR_SECRET"`
+
+	// QueryObserver is notified of every query run by DBHolders, e.g. a TracingObserver.
+	// See GetQueryObserver.
+	QueryObserver QueryObserver
+
+	// Metrics collects the stats of the connection pools of DBHolders and the duration of
+	// the operations of their repositories. When nil, no metrics are collected.
+	Metrics *Metrics
+
+	// Dialect is the database engine to connect to. When nil, Postgres is used.
+	// Advisory locks, read replicas and tenants are only supported by Postgres.
+	Dialect Dialect
 }
 
 // NewDBConfigFromEnv returns a *DBConfig initialized by env variables
@@ -58,29 +103,95 @@ func (c *DBConfig) SetEmptyValuesToDefaults() {
 	if c.MigrationsDir == "" {
 		c.MigrationsDir = "./migrations"
 	}
+
+	if c.MigrationsLockTimeout == 0 {
+		c.MigrationsLockTimeout = time.Minute
+	}
+
+	if c.TenantSchemaPrefix == "" {
+		c.TenantSchemaPrefix = "tenant_"
+	}
 }
 
+// GetConnectionString returns the connection string built from the config by its Dialect.
 func (c *DBConfig) GetConnectionString() string {
-	host := fmt.Sprintf("host=%s", c.Host)
-	port := fmt.Sprintf("port=%s", c.Port)
-	user := fmt.Sprintf("user=%s", c.User)
-	pass := fmt.Sprintf("password=%s", c.Password)
-	dbname := fmt.Sprintf("dbname=%s", c.DatabaseName)
-	searchPath := fmt.Sprintf("search_path=%s", c.SchemaName)
-
-	conn := fmt.Sprintf("%s %s %s %s %s %s sslmode=disable TimeZone=UTC", host, port, user, pass, dbname, searchPath)
-	return conn
+	return c.GetDialect().ConnectionString(c)
+}
+
+// GetQueryObserver returns the QueryObserver of the DBHolders: a SlogObserver configured by
+// LogQueries, SlowQueryThreshold and LogQueryArgs, followed by QueryObserver. It returns nil
+// when queries are neither logged nor observed.
+func (c *DBConfig) GetQueryObserver() QueryObserver {
+	var observers []QueryObserver
+	if c.LogQueries || c.SlowQueryThreshold > 0 {
+		observers = append(observers, &SlogObserver{
+			SlowThreshold: c.SlowQueryThreshold,
+			LogAll:        c.LogQueries,
+			LogArgs:       c.LogQueryArgs,
+		})
+	}
+	if c.QueryObserver != nil {
+		observers = append(observers, c.QueryObserver)
+	}
+
+	switch len(observers) {
+	case 0:
+		return nil
+	case 1:
+		return observers[0]
+	default:
+		return ChainObservers(observers...)
+	}
+}
+
+// quoteConnValue returns v between single quotes, escaping backslashes and single quotes.
+func quoteConnValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
 }
 
+// ReplicaConfigs returns a copy of the config for every host in ReplicaHosts.
+func (c *DBConfig) ReplicaConfigs() []*DBConfig {
+	configs := make([]*DBConfig, 0, len(c.ReplicaHosts))
+	for _, replica := range c.ReplicaHosts {
+		replica = strings.TrimSpace(replica)
+		if replica == "" {
+			continue
+		}
+
+		cfg := *c
+		cfg.ReplicaHosts = nil
+		cfg.Host = replica
+		if host, port, err := net.SplitHostPort(replica); err == nil {
+			cfg.Host = host
+			cfg.Port = port
+		}
+		configs = append(configs, &cfg)
+	}
+	return configs
+}
+
+// CreateSchema creates SchemaName if it does not exist.
 func (c *DBConfig) CreateSchema(db *sql.DB) {
-	_, err := db.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", c.SchemaName))
+	err := c.GetDialect().CreateSchema(db, c)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DropSchema drops SchemaName and everything in it.
+func (c *DBConfig) DropSchema(db *sql.DB) {
+	err := c.GetDialect().DropSchema(db, c)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// SetSearchPath sets the search_path of one of the connections of db. Every connection
+// opened with GetConnectionString already uses SchemaName as search_path. Postgres only.
 func (c *DBConfig) SetSearchPath(db *sql.DB) {
-	_, err := db.Exec(fmt.Sprintf("SET search_path TO %s;", c.SchemaName))
+	_, err := db.Exec(fmt.Sprintf("SET search_path TO %s;", ident.MustQuote(ident.Fold(c.SchemaName))))
 	if err != nil {
 		panic(err)
 	}
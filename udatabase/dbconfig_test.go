@@ -0,0 +1,41 @@
+package udatabase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetConnectionString(t *testing.T) {
+	t.Run("PlainSchemaName_isCaseInsensitive", func(t *testing.T) {
+		// ARRANGE
+		cfg := &DBConfig{SchemaName: "MySchema"}
+
+		// ACT
+		actual := cfg.GetConnectionString()
+
+		// ASSERT
+		require.Contains(t, actual, `search_path='"myschema"'`)
+	})
+
+	t.Run("ValuesWithSpacesAndQuotes_cannotAddConnectionParameters", func(t *testing.T) {
+		// ARRANGE
+		cfg := &DBConfig{
+			Host:         "localhost",
+			Port:         "5432",
+			User:         "postgres",
+			Password:     `it's a \secret sslmode=require`,
+			DatabaseName: "postgres",
+			SchemaName:   `my schema"; DROP SCHEMA public; --`,
+		}
+
+		// ACT
+		actual := cfg.GetConnectionString()
+
+		// ASSERT
+		require.Equal(t,
+			`host='localhost' port='5432' user='postgres' password='it\'s a \\secret sslmode=require' `+
+				`dbname='postgres' search_path='"my schema""; DROP SCHEMA public; --"' sslmode=disable TimeZone=UTC`,
+			actual)
+	})
+}
@@ -0,0 +1,117 @@
+package udatabase
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/golang-migrate/migrate/v4/database"
+	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/jmoiron/sqlx"
+)
+
+// Dialect contains the behavior that depends on the database engine: how to connect to it,
+// how schemas are created and dropped, how migrations are applied, how errors are mapped to
+// UError and which placeholders queries use. DBConfig.Dialect selects the dialect used by
+// DBHolders, TestDBHolders and migrations, which is Postgres when it is nil.
+type Dialect interface {
+	// DriverName returns the name of the database/sql driver.
+	DriverName() string
+
+	// ConnectionString returns the data source name of the database described by cfg.
+	ConnectionString(cfg *DBConfig) string
+
+	// CreateSchema creates cfg.SchemaName if it does not exist.
+	CreateSchema(db *sql.DB, cfg *DBConfig) error
+
+	// DropSchema drops cfg.SchemaName and everything in it.
+	DropSchema(db *sql.DB, cfg *DBConfig) error
+
+	// MigrationDriver returns the golang-migrate driver that applies migrations to cfg.SchemaName.
+	MigrationDriver(db *sql.DB, cfg *DBConfig) (database.Driver, error)
+
+	// MapError returns the UError for err, or nil if err is not mapped.
+	MapError(err error) *uerr.UError
+
+	// BindType returns the placeholder style of the queries as a sqlx bind type
+	// (sqlx.DOLLAR, sqlx.QUESTION, ...).
+	BindType() int
+}
+
+// Postgres is the dialect used when DBConfig.Dialect is nil.
+var Postgres Dialect = PostgresDialect{}
+
+// PostgresDialect is the Dialect of Postgres, used through the lib/pq driver.
+type PostgresDialect struct{}
+
+func (PostgresDialect) DriverName() string {
+	return "postgres"
+}
+
+// ConnectionString returns a key/value connection string. Values are quoted so they cannot
+// add other connection parameters, and the search_path of every connection is set to SchemaName,
+// which is folded with ident.Fold, as every other use of SchemaName by the dialect.
+func (PostgresDialect) ConnectionString(cfg *DBConfig) string {
+	host := fmt.Sprintf("host=%s", quoteConnValue(cfg.Host))
+	port := fmt.Sprintf("port=%s", quoteConnValue(cfg.Port))
+	user := fmt.Sprintf("user=%s", quoteConnValue(cfg.User))
+	pass := fmt.Sprintf("password=%s", quoteConnValue(cfg.Password))
+	dbname := fmt.Sprintf("dbname=%s", quoteConnValue(cfg.DatabaseName))
+	searchPath := fmt.Sprintf("search_path=%s", quoteConnValue(ident.MustQuote(ident.Fold(cfg.SchemaName))))
+
+	conn := fmt.Sprintf("%s %s %s %s %s %s sslmode=disable TimeZone=UTC", host, port, user, pass, dbname, searchPath)
+	return conn
+}
+
+func (PostgresDialect) CreateSchema(db *sql.DB, cfg *DBConfig) error {
+	schema, err := ident.Quote(ident.Fold(cfg.SchemaName))
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", schema))
+	return err
+}
+
+func (PostgresDialect) DropSchema(db *sql.DB, cfg *DBConfig) error {
+	schema, err := ident.Quote(ident.Fold(cfg.SchemaName))
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", schema))
+	return err
+}
+
+func (PostgresDialect) MigrationDriver(db *sql.DB, cfg *DBConfig) (database.Driver, error) {
+	config := migratePostgres.Config{
+		SchemaName: ident.Fold(cfg.SchemaName),
+	}
+	return migratePostgres.WithInstance(db, &config)
+}
+
+// MapError maps Postgres errors with MapPgError.
+func (PostgresDialect) MapError(err error) *uerr.UError {
+	return MapPgError(err)
+}
+
+func (PostgresDialect) BindType() int {
+	return sqlx.DOLLAR
+}
+
+// ForeignKeysQuery returns the query of the foreign keys of the tables in the search_path,
+// see ForeignKeysDialect.
+func (PostgresDialect) ForeignKeysQuery() string {
+	return "SELECT t.relname, r.relname FROM pg_constraint c " +
+		"JOIN pg_class t ON t.oid = c.conrelid JOIN pg_class r ON r.oid = c.confrelid " +
+		"WHERE c.contype = 'f' AND pg_table_is_visible(c.conrelid)"
+}
+
+// GetDialect returns Dialect, or Postgres if it is nil.
+func (c *DBConfig) GetDialect() Dialect {
+	if c.Dialect == nil {
+		return Postgres
+	}
+	return c.Dialect
+}
@@ -0,0 +1,120 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+)
+
+// ArrayMode is how array filters match the values with Postgres array columns.
+type ArrayMode int
+
+const (
+	// ArrayAny matches the columns that have any of the values, one by one, e.g. 'a' = ANY(tags).
+	ArrayAny ArrayMode = iota
+	// ArrayOverlaps matches the columns that have any of the values, in a single array, e.g.
+	// tags && '{a,b}'.
+	ArrayOverlaps
+	// ArrayContains matches the columns that have all of the values, e.g. tags @> '{a,b}'.
+	ArrayContains
+)
+
+// DefaultArrayElementType is the type of the elements of array columns when no other is set.
+const DefaultArrayElementType = "text"
+
+// arrayElementEscaper escapes the quoted elements of array literals.
+var arrayElementEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// ArrayOptions configure ApplyArrayField.
+type ArrayOptions struct {
+	Mode ArrayMode
+
+	// ElementType is the type of the elements of the column, e.g. integer for integer[]. When
+	// empty, DefaultArrayElementType is used.
+	ElementType string
+}
+
+// ApplyArrayField applies the values of a filter of an array column in the mode of opts. Columns
+// matching the values without operator are selected, unless they have any of the ne values.
+// NullValue matches null columns, and not null ones with ne. Values are passed as text, and
+// arrays as array literals, and cast to the element type in the query.
+func ApplyArrayField(fieldName string, opts ArrayOptions, values ...string) (conds string, args []interface{},
+	rErr error) {
+	if len(values) == 0 {
+		return "", args, rErr
+	}
+
+	column, rErr := fieldExpression(fieldName)
+	if rErr != nil {
+		return "", nil, rErr
+	}
+
+	elementType := opts.ElementType
+	if elementType == "" {
+		elementType = DefaultArrayElementType
+	}
+	if err := ident.Validate(elementType); err != nil {
+		return "", nil, fmt.Errorf("invalid array element type %q", elementType)
+	}
+
+	var eqValues, neValues []string
+	var eqNull, neNull bool
+	for _, v := range values {
+		if op, ok := nullOperator(v); ok {
+			eqNull, neNull = eqNull || op == OpEq, neNull || op == OpNe
+			continue
+		}
+
+		if op, value := splitEqualityOperator(v); op == OpEq {
+			eqValues = append(eqValues, value)
+		} else {
+			neValues = append(neValues, value)
+		}
+	}
+
+	var eq, ne []string
+	array := "?::text::" + elementType + "[]"
+	switch opts.Mode {
+	case ArrayAny:
+		cond := "(?::text::" + elementType + " = ANY(" + column + "))"
+		for _, v := range eqValues {
+			eq = append(eq, cond)
+			args = append(args, v)
+		}
+	case ArrayOverlaps:
+		if len(eqValues) > 0 {
+			eq = append(eq, "("+column+" && "+array+")")
+			args = append(args, arrayLiteral(eqValues))
+		}
+	case ArrayContains:
+		if len(eqValues) > 0 {
+			eq = append(eq, "("+column+" @> "+array+")")
+			args = append(args, arrayLiteral(eqValues))
+		}
+	default:
+		return "", nil, fmt.Errorf("unknown array mode %d", opts.Mode)
+	}
+
+	if len(neValues) > 0 {
+		ne = append(ne, "("+column+" && "+array+")")
+		args = append(args, arrayLiteral(neValues))
+	}
+	return matchConditions(column, eq, ne, eqNull, neNull), args, nil
+}
+
+// arrayLiteral returns the array literal of values, e.g. {"a","b c"}.
+func arrayLiteral(values []string) string {
+	var sb strings.Builder
+	sb.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('"')
+		sb.WriteString(arrayElementEscaper.Replace(v))
+		sb.WriteByte('"')
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyArrayField(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          ArrayOptions
+		values        []string
+		expectedConds string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "Any_matchesAnyValue",
+			opts:          ArrayOptions{Mode: ArrayAny},
+			values:        []string{"a", "b"},
+			expectedConds: "((?::text::text = ANY(tags)) OR (?::text::text = ANY(tags)))",
+			expectedArgs:  []interface{}{"a", "b"},
+		},
+		{
+			name:          "Overlaps_isArrayLiteral",
+			opts:          ArrayOptions{Mode: ArrayOverlaps, ElementType: "integer"},
+			values:        []string{"1", "2"},
+			expectedConds: "(tags && ?::text::integer[])",
+			expectedArgs:  []interface{}{`{"1","2"}`},
+		},
+		{
+			name:          "ContainsAndNotValues_containsAllAndNoneOfNotValues",
+			opts:          ArrayOptions{Mode: ArrayContains},
+			values:        []string{`a "b"`, `c\d`, "ne:e,f", "ne:null"},
+			expectedConds: "((tags @> ?::text::text[]) AND (NOT (tags && ?::text::text[])) AND (tags IS NOT NULL))",
+			expectedArgs:  []interface{}{`{"a \"b\"","c\\d"}`, `{"e,f"}`},
+		},
+		{
+			name:          "Null_isNull",
+			opts:          ArrayOptions{Mode: ArrayContains},
+			values:        []string{"null"},
+			expectedConds: "(tags IS NULL)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			conds, args, err := ApplyArrayField("tags", tt.opts, tt.values...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedConds, conds)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	t.Run("InvalidElementType_returnsError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyArrayField("tags", ArrayOptions{ElementType: "text[]); DROP TABLE x; --"}, "a")
+
+		// ASSERT
+		require.Error(t, err)
+	})
+}
@@ -8,30 +8,40 @@ import (
 	"github.com/carlosarismendi/utils/uerr"
 )
 
-// ApplyBoolField only applies first value from values.
+// BoolOperators are the operators of bool filters.
+var BoolOperators = []Operator{OpEq, OpNe}
+
+// ApplyBoolField only applies first value from values, which may have any of BoolOperators.
+// NullValue matches null columns, and not null ones with ne.
 func ApplyBoolField(fieldName string, values ...string) (conds string, args []interface{}, rErr error) {
 	amountValues := len(values)
 	if amountValues == 0 {
 		return "", args, rErr
 	}
 
-	args = make([]interface{}, 0, 1)
-	var sb strings.Builder
+	column, rErr := fieldExpression(fieldName)
+	if rErr != nil {
+		return "", nil, rErr
+	}
 
-	var value bool
-	value, rErr = stob(fieldName, values[0])
+	if op, ok := nullOperator(values[0]); ok {
+		eqCond, neConds := equalityConditions(column, nil, nil, op == OpEq, op == OpNe)
+		return eqCond + strings.Join(neConds, ""), nil, nil
+	}
+
+	op, v, rErr := SplitOperator(fieldName, BoolOperators, values[0])
 	if rErr != nil {
 		return "", nil, rErr
 	}
-	args = append(args, value)
 
-	sb.Grow(len(fieldName) + 4)
-	sb.WriteByte('(')
-	sb.WriteString(fieldName)
-	sb.WriteString("=?")
-	sb.WriteByte(')')
+	var value bool
+	value, rErr = stob(fieldName, v)
+	if rErr != nil {
+		return "", nil, rErr
+	}
+	args = []interface{}{value}
 
-	return sb.String(), args, nil
+	return "(" + column + comparisonOperators[op] + "?)", args, nil
 }
 
 func stob(fieldName, s string) (bool, error) {
@@ -0,0 +1,61 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyBoolField(t *testing.T) {
+	tests := []struct {
+		name          string
+		values        []string
+		expectedConds string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "Value_isEqual",
+			values:        []string{"true"},
+			expectedConds: "(active=?)",
+			expectedArgs:  []interface{}{true},
+		},
+		{
+			name:          "NotEqual_isNotEqual",
+			values:        []string{"ne:true"},
+			expectedConds: "(active<>?)",
+			expectedArgs:  []interface{}{true},
+		},
+		{
+			name:          "Null_isNull",
+			values:        []string{"null"},
+			expectedConds: "(active IS NULL)",
+		},
+		{
+			name:          "NotNull_isNotNull",
+			values:        []string{"ne:null"},
+			expectedConds: "(active IS NOT NULL)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			conds, args, err := ApplyBoolField("active", tt.values...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedConds, conds)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	t.Run("ComparisonOperator_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyBoolField("active", "gt:true")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
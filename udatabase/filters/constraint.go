@@ -0,0 +1,151 @@
+package filters
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/carlosarismendi/utils/validate"
+)
+
+// DefaultMaxValues is the maximum number of values of a filter whose constraints do not set another.
+const DefaultMaxValues = 100
+
+// Constraints restrict the values of a filter before it is applied. Operators and NullValue are
+// not part of the values that are checked, e.g. 10 is checked for gte:10, and 1 and 5 for
+// between:1,5. The zero value only limits the number of values to DefaultMaxValues.
+type Constraints struct {
+	// Required makes the filter fail if it has no values.
+	Required bool
+
+	// MaxValues is the maximum number of values. When 0, DefaultMaxValues is used, and when
+	// negative, the number of values is not limited.
+	MaxValues int
+
+	// Min and Max are the minimum and maximum values, both included, of numeric filters.
+	Min, Max *float64
+
+	// Pattern is a regular expression that the values must match, e.g. ^[a-z]+$.
+	Pattern *regexp.Regexp
+
+	// Validator is a tag of github.com/go-playground/validator that the values must be valid
+	// for, e.g. email or max=50.
+	Validator string
+}
+
+// Validate returns an error if c is not valid, i.e. if Min is greater than Max or Validator is
+// not a valid tag.
+func (c *Constraints) Validate() (rErr error) {
+	if c.Min != nil && c.Max != nil && *c.Min > *c.Max {
+		return fmt.Errorf("min %v of constraints is greater than max %v", *c.Min, *c.Max)
+	}
+
+	if c.Validator != "" {
+		// The validator panics when tags are not valid.
+		defer func() {
+			if r := recover(); r != nil {
+				rErr = fmt.Errorf("invalid validator tag %q of constraints: %v", c.Validator, r)
+			}
+		}()
+		_ = validate.Var("", "", c.Validator)
+	}
+	return nil
+}
+
+// Check returns the violations of c by the values of the filter name, whose operators are
+// operators, or nil if there are none. Use ConstraintsError to return them as an error.
+func (c *Constraints) Check(name string, operators []Operator, values []string) []string {
+	if len(values) == 0 {
+		if c.Required {
+			return []string{fmt.Sprintf("Filter %q is required.", name)}
+		}
+		return nil
+	}
+
+	maxValues := c.MaxValues
+	if maxValues == 0 {
+		maxValues = DefaultMaxValues
+	}
+	if maxValues > 0 && len(values) > maxValues {
+		return []string{fmt.Sprintf("Filter %q has %d values, but it accepts at most %d.", name, len(values),
+			maxValues)}
+	}
+
+	var violations []string
+	for _, v := range values {
+		if _, ok := nullOperator(v); ok {
+			continue
+		}
+
+		op, value := constraintOperand(operators, v)
+
+		operands := []string{value}
+		if op == OpBetween {
+			operands = strings.Split(value, ",")
+		}
+		for _, operand := range operands {
+			if violation := c.checkValue(name, operand); violation != "" {
+				violations = append(violations, violation)
+			}
+		}
+	}
+	return violations
+}
+
+func (c *Constraints) checkValue(name, value string) string {
+	if c.Min != nil || c.Max != nil {
+		// Values that are not numbers are rejected by the filter.
+		num, err := strconv.ParseFloat(value, 64)
+		if err == nil && (c.Min != nil && num < *c.Min || c.Max != nil && num > *c.Max) {
+			return fmt.Sprintf("Invalid value for filter %q. It must be %s.", name, c.rangeDescription())
+		}
+	}
+
+	if c.Pattern != nil && !c.Pattern.MatchString(value) {
+		return fmt.Sprintf("Invalid value for filter %q. It must match %q.", name, c.Pattern.String())
+	}
+
+	if c.Validator != "" {
+		if err := validate.Var(name, value, c.Validator); err != nil {
+			return uerr.GetMessage(err)
+		}
+	}
+	return ""
+}
+
+func (c *Constraints) rangeDescription() string {
+	format := func(f float64) string {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	switch {
+	case c.Min != nil && c.Max != nil:
+		return "between " + format(*c.Min) + " and " + format(*c.Max)
+	case c.Min != nil:
+		return "at least " + format(*c.Min)
+	default:
+		return "at most " + format(*c.Max)
+	}
+}
+
+// constraintOperand returns the operator of value and the rest of it, if it starts with one of
+// operators followed by a colon, or OpEq and value otherwise.
+func constraintOperand(operators []Operator, value string) (Operator, string) {
+	prefix, rest, ok := strings.Cut(value, ":")
+	if ok && slices.Contains(operators, Operator(prefix)) {
+		return Operator(prefix), rest
+	}
+	return OpEq, value
+}
+
+// ConstraintsError returns a WrongInputParameterError with the violations of constraints, one
+// per line, or nil if there are none.
+func ConstraintsError(violations []string) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return uerr.NewError(uerr.WrongInputParameterError, strings.Join(violations, "\n"))
+}
@@ -0,0 +1,118 @@
+package filters
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraintsCheck(t *testing.T) {
+	minPrice, maxPrice := 1.0, 100.0
+	tests := []struct {
+		name        string
+		constraints Constraints
+		operators   []Operator
+		values      []string
+		expected    []string
+	}{
+		{
+			name:        "RequiredFilterWithoutValues_isViolation",
+			constraints: Constraints{Required: true},
+			expected:    []string{`Filter "price" is required.`},
+		},
+		{
+			name:        "TooManyValues_isViolation",
+			constraints: Constraints{MaxValues: 2},
+			values:      []string{"1", "2", "3"},
+			expected:    []string{`Filter "price" has 3 values, but it accepts at most 2.`},
+		},
+		{
+			name:     "MoreValuesThanDefaultMaxValues_isViolation",
+			values:   strings.Split(strings.Repeat("1,", DefaultMaxValues), ","),
+			expected: []string{`Filter "price" has 101 values, but it accepts at most 100.`},
+		},
+		{
+			name:        "NegativeMaxValues_doesNotLimitValues",
+			constraints: Constraints{MaxValues: -1},
+			values:      strings.Split(strings.Repeat("1,", DefaultMaxValues), ","),
+		},
+		{
+			name:        "ValuesOutOfRange_areViolations",
+			constraints: Constraints{Min: &minPrice, Max: &maxPrice},
+			operators:   NumOperators,
+			values:      []string{"0.5", "gte:50", "lt:101", "null", "ne:null"},
+			expected: []string{
+				`Invalid value for filter "price". It must be between 1 and 100.`,
+				`Invalid value for filter "price". It must be between 1 and 100.`,
+			},
+		},
+		{
+			name:        "BetweenOutOfRange_isViolation",
+			constraints: Constraints{Max: &maxPrice},
+			operators:   []Operator{OpBetween},
+			values:      []string{"between:10,200"},
+			expected:    []string{`Invalid value for filter "price". It must be at most 100.`},
+		},
+		{
+			name:        "ValueNotMatchingPattern_isViolation",
+			constraints: Constraints{Pattern: regexp.MustCompile("^[a-z]+$")},
+			operators:   TextOperators,
+			values:      []string{"ne:abc", "eq:ne:x", "gt:x"},
+			expected: []string{
+				`Invalid value for filter "price". It must match "^[a-z]+$".`,
+				`Invalid value for filter "price". It must match "^[a-z]+$".`,
+			},
+		},
+		{
+			name:        "ValueNotValidForValidator_isViolation",
+			constraints: Constraints{Validator: "email"},
+			operators:   TextOperators,
+			values:      []string{"user@example.com", "ne:user"},
+			expected:    []string{"Invalid field price: the value must be 'email'. The value received is 'user'."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			violations := tt.constraints.Check("price", tt.operators, tt.values)
+
+			// ASSERT
+			require.Equal(t, tt.expected, violations)
+		})
+	}
+}
+
+func TestConstraintsValidate(t *testing.T) {
+	minValue, maxValue := 10.0, 1.0
+	invalid := map[string]Constraints{
+		"MinGreaterThanMax": {Min: &minValue, Max: &maxValue},
+		"UnknownValidator":  {Validator: "unknown_tag"},
+	}
+	for name, c := range invalid {
+		t.Run(name+"_returnsError", func(t *testing.T) {
+			require.Error(t, c.Validate())
+		})
+	}
+
+	require.NoError(t, (&Constraints{Validator: "max=10", Min: &maxValue}).Validate())
+}
+
+func TestConstraintsError(t *testing.T) {
+	t.Run("NoViolations_returnsNil", func(t *testing.T) {
+		require.NoError(t, ConstraintsError(nil))
+	})
+
+	t.Run("Violations_returnsWrongInputParameterErrorWithEveryViolation", func(t *testing.T) {
+		// ACT
+		err := ConstraintsError([]string{"a.", "b."})
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		require.Equal(t, "a.\nb.", uerr.GetMessage(err))
+	})
+}
@@ -0,0 +1,115 @@
+package filters
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// ValueType is the type of the values of a query parameter of a Description.
+type ValueType string
+
+const (
+	TextValue       ValueType = "text"
+	IntegerValue    ValueType = "integer"
+	NumberValue     ValueType = "number"
+	BooleanValue    ValueType = "boolean"
+	UUIDValue       ValueType = "uuid"
+	EnumValue       ValueType = "enum"
+	TimeValue       ValueType = "time"
+	JSONValue       ValueType = "json"
+	ArrayValue      ValueType = "array"
+	SortValue       ValueType = "sort"
+	LimitValue      ValueType = "limit"
+	OffsetValue     ValueType = "offset"
+	CursorValue     ValueType = "cursor"
+	ExpressionValue ValueType = "expression"
+)
+
+// Description describes a query parameter of a repository, such as a filter or a sorter, so that
+// API documentation can be generated from the filters registered in the repository, see
+// OpenAPIParameters and ParametersJSONSchema.
+type Description struct {
+	// Name is the name of the query parameter. Filters do not know their names, so repositories
+	// set it to the key of the filter in their filters map.
+	Name string
+
+	// Type is the type of the values, or empty if it is unknown.
+	Type ValueType
+
+	// Operators are the operators allowed in the values, e.g. gte:10.
+	Operators []Operator
+
+	// Enum are the values allowed in enum filters.
+	Enum []string
+
+	// SortFields are the fields that sorters sort by.
+	SortFields []string
+
+	// Default is the value of the parameter when it is not set, if any.
+	Default string
+
+	// Constraints are the constraints of the values of the parameter.
+	Constraints Constraints
+}
+
+// Describe returns d, so that descriptions can be passed to DescribeParams.
+func (d Description) Describe() Description {
+	return d
+}
+
+// DescribeParams returns the descriptions of the query parameters of params, sorted by name, with
+// their names set to their keys. Parameters without a Describe() Description method only have a
+// name.
+func DescribeParams(params map[string]any) []Description {
+	descriptions := make([]Description, 0, len(params))
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		var d Description
+		if describer, ok := params[name].(interface{ Describe() Description }); ok {
+			d = describer.Describe()
+		}
+		d.Name = name
+		descriptions = append(descriptions, d)
+	}
+	return descriptions
+}
+
+// DescribeSortFields returns the description of a sorter of fields.
+func DescribeSortFields(fields SortFields) Description {
+	return Description{
+		Type:        SortValue,
+		SortFields:  slices.Sorted(maps.Keys(fields)),
+		Constraints: Constraints{MaxValues: -1},
+	}
+}
+
+// DescribeLimit returns the description of the limit of pages, whose default value is
+// defaultLimit and whose maximum is maxLimit.
+func DescribeLimit(defaultLimit, maxLimit int64) Description {
+	minLimit, maxValue := 1.0, float64(maxLimit)
+	return Description{
+		Name:        "limit",
+		Type:        LimitValue,
+		Default:     strconv.FormatInt(defaultLimit, 10),
+		Constraints: Constraints{MaxValues: 1, Min: &minLimit, Max: &maxValue},
+	}
+}
+
+// DescribeOffset returns the description of the offset of pages.
+func DescribeOffset() Description {
+	minOffset := 0.0
+	return Description{
+		Name:        "offset",
+		Type:        OffsetValue,
+		Constraints: Constraints{MaxValues: 1, Min: &minOffset},
+	}
+}
+
+// DescribeCursor returns the description of the cursor of pages paginated by keyset.
+func DescribeCursor() Description {
+	return Description{
+		Name:        "cursor",
+		Type:        CursorValue,
+		Constraints: Constraints{MaxValues: 1},
+	}
+}
@@ -0,0 +1,24 @@
+package filters
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// ApplyEnumField applies the values of an enum filter, which must be one of allowed. Values
+// without operator match any of them with = or IN, and ne values match none of them. NullValue
+// matches null columns, and not null ones with ne.
+func ApplyEnumField(fieldName string, allowed []string, values ...string) (conds string, args []interface{},
+	rErr error) {
+	return applyComparisonField(fieldName, EqualityOperators, func(value string) (interface{}, error) {
+		if !slices.Contains(allowed, value) {
+			errMsg := fmt.Sprintf("Invalid value %q for filter %q. It must be one of: %s.", value, fieldName,
+				strings.Join(allowed, ", "))
+			return nil, uerr.NewError(uerr.WrongInputParameterError, errMsg)
+		}
+		return value, nil
+	}, values...)
+}
@@ -0,0 +1,42 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEnumField(t *testing.T) {
+	allowed := []string{"draft", "published", "archived"}
+
+	t.Run("AllowedValues_areNotIn", func(t *testing.T) {
+		// ACT
+		conds, args, err := ApplyEnumField("status", allowed, "ne:draft", "ne:archived")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "status NOT IN ( ?,?)", conds)
+		require.Equal(t, []interface{}{"draft", "archived"}, args)
+	})
+
+	t.Run("EqualNull_isValueNull", func(t *testing.T) {
+		// ACT
+		conds, args, err := ApplyEnumField("status", []string{"null", "set"}, "eq:null", "null")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "((status=?) OR (status IS NULL))", conds)
+		require.Equal(t, []interface{}{"null"}, args)
+	})
+
+	t.Run("NotAllowedValue_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyEnumField("status", allowed, "deleted")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		require.Contains(t, err.Error(), "draft, published, archived")
+	})
+}
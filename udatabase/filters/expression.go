@@ -0,0 +1,346 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// ExpressionParam is the parameter of filter expressions, e.g.
+// ?filter=status=open OR (assignee=me AND priority=gte:3).
+const ExpressionParam = "filter"
+
+// maxExpressionDepth is the maximum nesting of groups and NOT in a filter expression.
+const maxExpressionDepth = 32
+
+// maxExpressionConditions is the maximum number of conditions of a filter expression.
+const maxExpressionConditions = 100
+
+// Expr is a node of a filter expression: a Condition, And, Or or Not.
+type Expr interface {
+	expr()
+}
+
+// Condition applies the filter Field to Value, which may have an operator, e.g. priority=gte:3.
+type Condition struct {
+	Field string
+	Value string
+}
+
+// And matches the rows that match all of its expressions.
+type And []Expr
+
+// Or matches the rows that match any of its expressions.
+type Or []Expr
+
+// Not matches the rows that do not match its expression.
+type Not struct {
+	Expr Expr
+}
+
+func (Condition) expr() {}
+func (And) expr()       {}
+func (Or) expr()        {}
+func (Not) expr()       {}
+
+// ParseExpression parses a filter expression. Expressions are conditions, field=value, combined
+// with AND, OR and NOT, case-insensitive, and grouped with parentheses. AND binds tighter than OR.
+// Values end at a space or a closing parenthesis, unless they are double-quoted, in which case \"
+// and \\ are a quote and a backslash, e.g. name="Resource (1)". Values may have the operators of
+// their filter, e.g. created_at=gte:now-7d. Expressions have at most 100 conditions and 32 levels
+// of nesting. Errors are WrongInputParameterErrors.
+func ParseExpression(s string) (Expr, error) {
+	p := &expressionParser{s: s}
+	e, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.s) {
+		return nil, p.error("unexpected %q", p.s[p.pos:p.pos+1])
+	}
+	return e, nil
+}
+
+// ParseExpressions parses the expressions of values, see ParseExpression, which must all match.
+func ParseExpressions(values []string) (And, error) {
+	exprs := make(And, 0, len(values))
+	for _, v := range values {
+		e, err := ParseExpression(v)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	return exprs, nil
+}
+
+// ConjunctFields returns the fields of the conditions that every match of e meets: the conditions
+// of e and of its nested And, but not those under an Or or a Not. Required filters are only met
+// by these conditions.
+func ConjunctFields(e Expr) map[string]bool {
+	fields := make(map[string]bool)
+	addConjunctFields(fields, e)
+	return fields
+}
+
+func addConjunctFields(fields map[string]bool, e Expr) {
+	switch e := e.(type) {
+	case Condition:
+		fields[e.Field] = true
+	case And:
+		for _, e := range e {
+			addConjunctFields(fields, e)
+		}
+	}
+}
+
+// ConditionValues returns the values of the conditions of e per field, in the order they appear.
+// Expression filters check the constraints of each filter with all of its values in e, so that
+// e cannot have more conditions of a filter than the MaxValues of its Constraints.
+func ConditionValues(e Expr) map[string][]string {
+	values := make(map[string][]string)
+	addConditionValues(values, e)
+	return values
+}
+
+func addConditionValues(values map[string][]string, e Expr) {
+	switch e := e.(type) {
+	case Condition:
+		values[e.Field] = append(values[e.Field], e.Value)
+	case Not:
+		addConditionValues(values, e.Expr)
+	case And:
+		for _, e := range e {
+			addConditionValues(values, e)
+		}
+	case Or:
+		for _, e := range e {
+			addConditionValues(values, e)
+		}
+	}
+}
+
+type expressionParser struct {
+	s          string
+	pos        int
+	conditions int
+}
+
+func (p *expressionParser) parseOr(depth int) (Expr, error) {
+	var or Or
+	for {
+		e, err := p.parseAnd(depth)
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, e)
+
+		if !p.keyword("OR") {
+			break
+		}
+	}
+
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *expressionParser) parseAnd(depth int) (Expr, error) {
+	var and And
+	for {
+		e, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, e)
+
+		if !p.keyword("AND") {
+			break
+		}
+	}
+
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *expressionParser) parseUnary(depth int) (Expr, error) {
+	if depth >= maxExpressionDepth {
+		return nil, p.error("it is nested more than %d levels", maxExpressionDepth)
+	}
+
+	p.skipSpaces()
+	if p.keyword("NOT") {
+		e, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return Not{Expr: e}, nil
+	}
+
+	if p.pos < len(p.s) && p.s[p.pos] == '(' {
+		p.pos++
+		e, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		p.skipSpaces()
+		if p.pos >= len(p.s) || p.s[p.pos] != ')' {
+			return nil, p.error("missing closing parenthesis")
+		}
+		p.pos++
+		return e, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *expressionParser) parseCondition() (Expr, error) {
+	if p.conditions == maxExpressionConditions {
+		return nil, p.error("it has more than %d conditions", maxExpressionConditions)
+	}
+	p.conditions++
+
+	start := p.pos
+	for p.pos < len(p.s) && isFieldChar(p.s[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		if p.pos >= len(p.s) {
+			return nil, p.error("missing condition")
+		}
+		return nil, p.error("unexpected %q", p.s[p.pos:p.pos+1])
+	}
+
+	field := p.s[start:p.pos]
+	if p.pos >= len(p.s) || p.s[p.pos] != '=' {
+		return nil, p.error("missing = after %q", field)
+	}
+	p.pos++
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	return Condition{Field: field, Value: value}, nil
+}
+
+func (p *expressionParser) parseValue() (string, error) {
+	if p.pos >= len(p.s) || p.s[p.pos] != '"' {
+		start := p.pos
+		for p.pos < len(p.s) && !isSpace(p.s[p.pos]) && p.s[p.pos] != ')' {
+			p.pos++
+		}
+		return p.s[start:p.pos], nil
+	}
+
+	var sb strings.Builder
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		switch c := p.s[p.pos]; c {
+		case '"':
+			p.pos++
+			return sb.String(), nil
+		case '\\':
+			if p.pos+1 < len(p.s) {
+				p.pos++
+				c = p.s[p.pos]
+			}
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", p.error("missing closing quote")
+}
+
+// keyword consumes the keyword kw, case-insensitive, if it is the next word of the expression.
+func (p *expressionParser) keyword(kw string) bool {
+	p.skipSpaces()
+	end := p.pos + len(kw)
+	if end > len(p.s) || !strings.EqualFold(p.s[p.pos:end], kw) {
+		return false
+	}
+	if end < len(p.s) && !isSpace(p.s[end]) && p.s[end] != '(' {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *expressionParser) skipSpaces() {
+	for p.pos < len(p.s) && isSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *expressionParser) error(format string, args ...any) error {
+	errMsg := fmt.Sprintf("Invalid %q expression at position %d: %s.", ExpressionParam, p.pos+1,
+		fmt.Sprintf(format, args...))
+	return uerr.NewError(uerr.WrongInputParameterError, errMsg)
+}
+
+func isFieldChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// CompileExpression returns the condition of e, with the conditions of its Condition nodes
+// returned by apply, which applies the filter named field to values. apply returns an error,
+// such as InvalidExpressionFilterError, when field is not a filter that can be used in expressions.
+func CompileExpression(e Expr, apply func(field string, values ...string) (string, []interface{}, error)) (
+	conds string, args []interface{}, rErr error) {
+	switch e := e.(type) {
+	case Condition:
+		conds, args, rErr = apply(e.Field, e.Value)
+		if rErr != nil {
+			return "", nil, rErr
+		}
+		if conds == "" {
+			return "", nil, InvalidExpressionFilterError(e.Field)
+		}
+		return conds, args, nil
+	case Not:
+		conds, args, rErr = CompileExpression(e.Expr, apply)
+		if rErr != nil {
+			return "", nil, rErr
+		}
+		return "(NOT " + conds + ")", args, nil
+	case And:
+		return compileExpressions(e, " AND ", apply)
+	case Or:
+		return compileExpressions(e, " OR ", apply)
+	default:
+		return "", nil, fmt.Errorf("unknown expression %T", e)
+	}
+}
+
+func compileExpressions(exprs []Expr, sep string,
+	apply func(field string, values ...string) (string, []interface{}, error)) (string, []interface{}, error) {
+	parts := make([]string, 0, len(exprs))
+	var args []interface{}
+	for _, e := range exprs {
+		cond, condArgs, err := CompileExpression(e, apply)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, cond)
+		args = append(args, condArgs...)
+	}
+	return joinConditions(parts, sep), args, nil
+}
+
+// InvalidExpressionFilterError returns the error of a condition of a filter expression whose
+// field is not a filter, or is one that cannot be used in expressions, such as sorters.
+func InvalidExpressionFilterError(field string) error {
+	errMsg := fmt.Sprintf("Invalid filter %q in %q expression.", field, ExpressionParam)
+	return uerr.NewError(uerr.WrongInputParameterError, errMsg)
+}
@@ -0,0 +1,181 @@
+package filters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        string
+		expected Expr
+	}{
+		{
+			name:     "Condition_isParsed",
+			s:        "status=open",
+			expected: Condition{Field: "status", Value: "open"},
+		},
+		{
+			name: "AndBindsTighterThanOr",
+			s:    "status=open OR assignee=me and priority=gte:3",
+			expected: Or{
+				Condition{Field: "status", Value: "open"},
+				And{Condition{Field: "assignee", Value: "me"}, Condition{Field: "priority", Value: "gte:3"}},
+			},
+		},
+		{
+			name: "Parentheses_groupExpressions",
+			s:    "(status=open OR status=new) AND NOT(assignee=null)",
+			expected: And{
+				Or{Condition{Field: "status", Value: "open"}, Condition{Field: "status", Value: "new"}},
+				Not{Expr: Condition{Field: "assignee", Value: "null"}},
+			},
+		},
+		{
+			name:     "QuotedValue_mayHaveSpacesParenthesesAndEscapedQuotes",
+			s:        ` name="a (\"b\") \\ c"`,
+			expected: Condition{Field: "name", Value: `a ("b") \ c`},
+		},
+		{
+			name:     "EmptyValue_isEmpty",
+			s:        "(name=)",
+			expected: Condition{Field: "name", Value: ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			e, err := ParseExpression(tt.s)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, e)
+		})
+	}
+
+	invalid := []string{
+		"",
+		"status",
+		"status=open OR",
+		"status=open assignee=me",
+		"(status=open",
+		"status=open)",
+		`name="open`,
+		"=open",
+		strings.Repeat("(", maxExpressionDepth+1) + "a=1" + strings.Repeat(")", maxExpressionDepth+1),
+		strings.Repeat("a=1 OR ", maxExpressionConditions) + "a=1",
+	}
+	for _, s := range invalid {
+		t.Run("Invalid_"+s, func(t *testing.T) {
+			// ACT
+			_, err := ParseExpression(s)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+}
+
+func TestParseExpressions(t *testing.T) {
+	t.Run("MaxConditions_isParsed", func(t *testing.T) {
+		// ARRANGE
+		s := strings.Repeat("a=1 OR ", maxExpressionConditions-1) + "a=1"
+
+		// ACT
+		exprs, err := ParseExpressions([]string{s, "b=2"})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Len(t, exprs, 2)
+		require.Len(t, exprs[0], maxExpressionConditions)
+	})
+
+	t.Run("InvalidExpression_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, err := ParseExpressions([]string{"a=1", "b"})
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
+
+func TestConditionValues(t *testing.T) {
+	// ARRANGE
+	e, err := ParseExpressions([]string{"status=open OR NOT (priority=gte:3 AND status=ne:null)", "priority=1"})
+	require.NoError(t, err)
+
+	// ACT
+	values := ConditionValues(e)
+
+	// ASSERT
+	require.Equal(t, map[string][]string{
+		"status":   {"open", "ne:null"},
+		"priority": {"gte:3", "1"},
+	}, values)
+}
+
+func TestConjunctFields(t *testing.T) {
+	// ARRANGE
+	e, err := ParseExpressions([]string{
+		"status=open AND (priority=1 AND owner=me) AND (type=bug OR type=task) AND NOT label=wontfix",
+		"due=null",
+	})
+	require.NoError(t, err)
+
+	// ACT
+	fields := ConjunctFields(e)
+
+	// ASSERT
+	require.Equal(t, map[string]bool{"status": true, "priority": true, "owner": true, "due": true}, fields)
+}
+
+func TestCompileExpression(t *testing.T) {
+	apply := func(field string, values ...string) (string, []interface{}, error) {
+		switch field {
+		case "status":
+			return ApplyTextField(field, values...)
+		case "priority":
+			return ApplyNumField(field, values...)
+		case "sort":
+			return "", nil, nil
+		default:
+			return "", nil, InvalidExpressionFilterError(field)
+		}
+	}
+
+	t.Run("NestedExpression_isCompiledWithArgumentsInOrder", func(t *testing.T) {
+		// ARRANGE
+		e, err := ParseExpression("status=open OR NOT (priority=gte:3 AND status=ne:null)")
+		require.NoError(t, err)
+
+		// ACT
+		conds, args, err := CompileExpression(e, apply)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "((status=?) OR (NOT ((priority>=?) AND (status IS NOT NULL))))", conds)
+		require.Equal(t, []interface{}{"open", int64(3)}, args)
+	})
+
+	for _, field := range []string{"unknown", "sort"} {
+		t.Run("InvalidFilter"+field+"_returnsWrongInputParameterError", func(t *testing.T) {
+			// ARRANGE
+			e, err := ParseExpression("status=open OR " + field + "=x")
+			require.NoError(t, err)
+
+			// ACT
+			_, _, err = CompileExpression(e, apply)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+}
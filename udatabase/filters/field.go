@@ -0,0 +1,57 @@
+package filters
+
+import (
+	"strings"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+)
+
+// fieldExpression returns the SQL of the field of a filter, which may be a column, such as name
+// or u.user_id, or a computed expression, such as lower(name) (see ident.ValidateExpression).
+// Expressions other than function calls are parenthesized, so they are compared as a whole.
+func fieldExpression(fieldName string) (string, error) {
+	if ident.Validate(fieldName) == nil {
+		return fieldName, nil
+	}
+
+	if err := ident.ValidateExpression(fieldName); err != nil {
+		return "", err
+	}
+
+	if isFunctionCall(fieldName) {
+		return fieldName, nil
+	}
+	return "(" + fieldName + ")", nil
+}
+
+// isFunctionCall reports whether the valid expression expr is a single function call, such as
+// lower(name), in which the first parenthesis is closed at the end.
+func isFunctionCall(expr string) bool {
+	i := strings.IndexByte(expr, '(')
+	if i <= 0 || ident.Validate(expr[:i]) != nil {
+		return false
+	}
+
+	depth := 0
+	var quote byte
+	for j := i; j < len(expr); j++ {
+		c := expr[j]
+		switch {
+		case quote != 0:
+			// Doubled quotes close and open the quote again.
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return j == len(expr)-1
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldExpression(t *testing.T) {
+	tests := []struct {
+		fieldName string
+		expected  string
+	}{
+		{fieldName: "u.user_id", expected: "u.user_id"},
+		{fieldName: "lower(u.name)", expected: "lower(u.name)"},
+		{fieldName: "coalesce(nickname, ')') || name", expected: "(coalesce(nickname, ')') || name)"},
+		{fieldName: "lower(first_name) || lower(last_name)", expected: "(lower(first_name) || lower(last_name))"},
+		{fieldName: "created_at::date", expected: "(created_at::date)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldName, func(t *testing.T) {
+			// ACT
+			expr, err := fieldExpression(tt.fieldName)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, expr)
+		})
+	}
+
+	for _, fieldName := range []string{"name) OR (1=1", "name = ?", "name; --"} {
+		t.Run("Invalid_"+fieldName, func(t *testing.T) {
+			// ACT
+			_, err := fieldExpression(fieldName)
+
+			// ASSERT
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestApplyFieldExpression(t *testing.T) {
+	t.Run("TextFieldWithExpression_comparesExpression", func(t *testing.T) {
+		// ACT
+		conds, args, err := ApplyTextField("lower(name)", "resource", "ne:null")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "((lower(name)=?) AND (lower(name) IS NOT NULL))", conds)
+		require.Equal(t, []interface{}{"resource"}, args)
+	})
+
+	t.Run("NumFieldWithExpression_comparesParenthesizedExpression", func(t *testing.T) {
+		// ACT
+		conds, args, err := ApplyNumField("price * quantity", "gte:100")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "((price * quantity)>=?)", conds)
+		require.Equal(t, []interface{}{int64(100)}, args)
+	})
+}
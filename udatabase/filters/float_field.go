@@ -0,0 +1,59 @@
+package filters
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// FloatOptions configure ApplyFloatField.
+type FloatOptions struct {
+	// Operators are the operators allowed in the values. When empty, NumOperators are used.
+	Operators []Operator
+
+	// Decimal passes the values to the query as decimal strings instead of float64, so that
+	// numeric and decimal columns compare them without the rounding errors of binary floats,
+	// e.g. 0.1 is not 0.1000000000000000055511151231257827.
+	Decimal bool
+}
+
+// ApplyFloatField applies the values of a floating point or decimal number filter, which may have
+// any of the operators of opts (see SplitOperator), like ApplyNumFieldWithOperators. Values are
+// decimal numbers, optionally with exponent, e.g. 1.5 or 2e-3. NaN and infinities are not allowed.
+func ApplyFloatField(fieldName string, opts FloatOptions, values ...string) (conds string, args []interface{},
+	rErr error) {
+	operators := opts.Operators
+	if len(operators) == 0 {
+		operators = NumOperators
+	}
+
+	return applyComparisonField(fieldName, operators, func(value string) (interface{}, error) {
+		num, err := stof(fieldName, value)
+		if err != nil || !opts.Decimal {
+			return num, err
+		}
+		return value, nil
+	}, values...)
+}
+
+func stof(fieldName, s string) (float64, error) {
+	num, err := strconv.ParseFloat(s, 64)
+	if err == nil && (math.IsNaN(num) || math.IsInf(num, 0) || !isDecimal(s)) {
+		err = fmt.Errorf("%q is not a decimal number", s)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("Invalid value for filter %q. It must be a decimal number.", fieldName)
+		return 0, uerr.NewError(uerr.WrongInputParameterError, errMsg).WithCause(err)
+	}
+
+	return num, nil
+}
+
+// isDecimal reports whether s, a valid float for strconv.ParseFloat, is written in decimal
+// notation, which databases accept too, unlike hexadecimal floats or underscores.
+func isDecimal(s string) bool {
+	return !strings.ContainsAny(s, "xX_")
+}
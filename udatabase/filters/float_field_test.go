@@ -0,0 +1,63 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyFloatField(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          FloatOptions
+		values        []string
+		expectedConds string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "Values_areIn",
+			opts:          FloatOptions{},
+			values:        []string{"1.5", "2e-3"},
+			expectedConds: "price IN ( ?,?)",
+			expectedArgs:  []interface{}{1.5, 0.002},
+		},
+		{
+			name:          "Range_isAndOfComparisons",
+			opts:          FloatOptions{},
+			values:        []string{"gte:0.1", "lt:9.99"},
+			expectedConds: "((price>=?) AND (price<?))",
+			expectedArgs:  []interface{}{0.1, 9.99},
+		},
+		{
+			name:          "Decimal_passesValuesAsStrings",
+			opts:          FloatOptions{Decimal: true},
+			values:        []string{"0.1", "ne:0.30"},
+			expectedConds: "((price=?) AND (price<>?))",
+			expectedArgs:  []interface{}{"0.1", "0.30"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			conds, args, err := ApplyFloatField("price", tt.opts, tt.values...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedConds, conds)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	for _, value := range []string{"a", "NaN", "inf", "1e400", "0x1p-2", "1_000"} {
+		t.Run("InvalidValue"+value+"_returnsWrongInputParameterError", func(t *testing.T) {
+			// ACT
+			_, _, err := ApplyFloatField("price", FloatOptions{Decimal: true}, value)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+}
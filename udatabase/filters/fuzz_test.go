@@ -0,0 +1,94 @@
+package filters
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/stretchr/testify/require"
+)
+
+func FuzzApplyTextField(f *testing.F) {
+	f.Add("name", "value")
+	f.Add("name; DROP TABLE resources; --", "value")
+	f.Add("name", "'); DROP TABLE resources; --")
+	f.Add("name", "ne:null")
+	f.Add("lower(name)", "value")
+
+	f.Fuzz(func(t *testing.T, fieldName, value string) {
+		conds, args, err := ApplyTextField(fieldName, value)
+		if err != nil {
+			require.Error(t, ident.ValidateExpression(fieldName))
+			return
+		}
+
+		// Only valid expressions reach the query and values are always passed as arguments.
+		column, err := fieldExpression(fieldName)
+		require.NoError(t, err)
+		require.Contains(t, []string{
+			"(" + column + "=?)",
+			"(" + column + "<>?)",
+			"(" + column + " IS NULL)",
+			"(" + column + " IS NOT NULL)",
+		}, conds)
+		require.LessOrEqual(t, len(args), 1)
+	})
+}
+
+func FuzzApplyNumField(f *testing.F) {
+	f.Add("random_number", "1")
+	f.Add("random_number) OR (1=1", "1")
+	f.Add("random_number", "gte:1")
+	f.Add("random_number", "gt:1) OR (1=1")
+
+	f.Fuzz(func(t *testing.T, fieldName, value string) {
+		conds, _, err := ApplyNumField(fieldName, value)
+		if err != nil {
+			return
+		}
+
+		column, err := fieldExpression(fieldName)
+		require.NoError(t, err)
+		op, _, err := SplitOperator(fieldName, NumOperators, value)
+		require.NoError(t, err)
+		require.Equal(t, "("+column+comparisonOperators[op]+"?)", conds)
+	})
+}
+
+func FuzzSortFieldAndDirection(f *testing.F) {
+	f.Add("name", "-name")
+	f.Add("name; DROP TABLE resources", "name; DROP TABLE resources")
+	f.Add("name", "")
+
+	f.Fuzz(func(t *testing.T, allowedField, value string) {
+		allowedFields := map[string]bool{allowedField: true}
+		col, _, err := SortFieldAndDirection(allowedFields, value)
+		if err != nil {
+			return
+		}
+
+		require.NoError(t, ident.Validate(col))
+	})
+}
+
+func FuzzParseExpression(f *testing.F) {
+	f.Add("status=open OR (name=x AND NOT name=ne:null)")
+	f.Add(`name="'); DROP TABLE resources; --"`)
+	f.Add("name=1) OR (1=1")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		e, err := ParseExpression(s)
+		if err != nil {
+			return
+		}
+
+		// Only the filters reach the query, so the values are always passed as arguments.
+		conds, _, err := CompileExpression(e, func(_ string, values ...string) (string, []interface{}, error) {
+			return ApplyTextField("name", values...)
+		})
+		require.NoError(t, err)
+		stripped := strings.NewReplacer("name", "", "=?", "", "<>?", "", " IS NOT NULL", "", " IS NULL", "",
+			"(", "", ")", "", " AND ", "", " OR ", "", "NOT ", "").Replace(conds)
+		require.Empty(t, stripped)
+	})
+}
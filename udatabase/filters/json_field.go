@@ -0,0 +1,114 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// JSONMode is how JSON filters match the values with Postgres jsonb columns.
+type JSONMode int
+
+const (
+	// JSONContains matches the columns that contain the values, which are JSON documents, e.g.
+	// attributes @> '{"color":"red"}'.
+	JSONContains JSONMode = iota
+	// JSONPathExists matches the columns in which the values, which are SQL/JSON paths, return
+	// any item, e.g. jsonb_path_exists(attributes, '$.tags[*] ? (@ == "new")').
+	JSONPathExists
+	// JSONKeyEquals compares the text of the key of JSONOptions.Key with the values, e.g.
+	// attributes->'size'->>'unit' = 'cm'.
+	JSONKeyEquals
+)
+
+// JSONOptions configure ApplyJSONField.
+type JSONOptions struct {
+	Mode JSONMode
+
+	// Key is the path of object keys of JSONKeyEquals, e.g. size, unit for attributes->'size'->>'unit'.
+	Key []string
+}
+
+// ApplyJSONField applies the values of a filter of a jsonb column in the mode of opts. Columns
+// matching any of the values without operator are selected, unless they match any of the ne
+// values. NullValue matches null columns, or missing keys in JSONKeyEquals, and not null ones
+// with ne. Values are passed as text and cast in the query, so the query only has ? placeholders
+// and none of the jsonb operators that are question marks.
+func ApplyJSONField(fieldName string, opts JSONOptions, values ...string) (conds string, args []interface{},
+	rErr error) {
+	if len(values) == 0 {
+		return "", args, rErr
+	}
+
+	column, rErr := fieldExpression(fieldName)
+	if rErr != nil {
+		return "", nil, rErr
+	}
+
+	var expr, cond string
+	var exprArgs []interface{}
+	switch opts.Mode {
+	case JSONKeyEquals:
+		if len(opts.Key) == 0 {
+			return "", nil, fmt.Errorf("missing key of JSON filter %q", fieldName)
+		}
+
+		// column->?::text->>?::text for the key a, b.
+		var sb strings.Builder
+		sb.WriteString(column)
+		for i, k := range opts.Key {
+			if i == len(opts.Key)-1 {
+				sb.WriteString("->>")
+			} else {
+				sb.WriteString("->")
+			}
+			sb.WriteString("?::text")
+			exprArgs = append(exprArgs, k)
+		}
+		expr = sb.String()
+		cond = expr + "=?::text"
+	case JSONContains:
+		expr, cond = column, column+" @> ?::text::jsonb"
+	case JSONPathExists:
+		expr, cond = column, "jsonb_path_exists("+column+", ?::text::jsonpath)"
+	default:
+		return "", nil, fmt.Errorf("unknown JSON mode %d", opts.Mode)
+	}
+
+	var eq, ne []string
+	var eqNull, neNull bool
+	var eqArgs, neArgs []interface{}
+	for _, v := range values {
+		if op, ok := nullOperator(v); ok {
+			eqNull, neNull = eqNull || op == OpEq, neNull || op == OpNe
+			continue
+		}
+
+		op, value := splitEqualityOperator(v)
+
+		if opts.Mode == JSONContains && !json.Valid([]byte(value)) {
+			errMsg := fmt.Sprintf("Invalid value for filter %q. It must be a JSON document.", fieldName)
+			return "", nil, uerr.NewError(uerr.WrongInputParameterError, errMsg)
+		}
+
+		if op == OpEq {
+			eq = append(eq, "("+cond+")")
+			eqArgs = append(append(eqArgs, exprArgs...), value)
+		} else {
+			ne = append(ne, "("+cond+")")
+			neArgs = append(append(neArgs, exprArgs...), value)
+		}
+	}
+
+	args = append(args, eqArgs...)
+	if eqNull {
+		args = append(args, exprArgs...)
+	}
+	args = append(args, neArgs...)
+	if neNull {
+		args = append(args, exprArgs...)
+	}
+	return matchConditions(expr, eq, ne, eqNull, neNull), args, nil
+}
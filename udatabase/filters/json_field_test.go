@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyJSONField(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          JSONOptions
+		values        []string
+		expectedConds string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "KeyEquals_comparesTextOfKey",
+			opts:          JSONOptions{Mode: JSONKeyEquals, Key: []string{"size", "unit"}},
+			values:        []string{"cm", "mm"},
+			expectedConds: "((attrs->?::text->>?::text=?::text) OR (attrs->?::text->>?::text=?::text))",
+			expectedArgs:  []interface{}{"size", "unit", "cm", "size", "unit", "mm"},
+		},
+		{
+			name:          "KeyEqualsNotNull_isKeyNotNull",
+			opts:          JSONOptions{Mode: JSONKeyEquals, Key: []string{"color"}},
+			values:        []string{"ne:red", "ne:null"},
+			expectedConds: "((NOT (attrs->>?::text=?::text)) AND (attrs->>?::text IS NOT NULL))",
+			expectedArgs:  []interface{}{"color", "red", "color"},
+		},
+		{
+			name:          "KeyEqualsEqualNull_comparesStringNull",
+			opts:          JSONOptions{Mode: JSONKeyEquals, Key: []string{"color"}},
+			values:        []string{"eq:null"},
+			expectedConds: "(attrs->>?::text=?::text)",
+			expectedArgs:  []interface{}{"color", "null"},
+		},
+		{
+			name:          "Contains_castsDocument",
+			opts:          JSONOptions{Mode: JSONContains},
+			values:        []string{`{"color":"red"}`},
+			expectedConds: "(attrs @> ?::text::jsonb)",
+			expectedArgs:  []interface{}{`{"color":"red"}`},
+		},
+		{
+			name:          "PathExists_castsPath",
+			opts:          JSONOptions{Mode: JSONPathExists},
+			values:        []string{`$.tags[*] ? (@ == "new")`},
+			expectedConds: "(jsonb_path_exists(attrs, ?::text::jsonpath))",
+			expectedArgs:  []interface{}{`$.tags[*] ? (@ == "new")`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			conds, args, err := ApplyJSONField("attrs", tt.opts, tt.values...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedConds, conds)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	t.Run("ContainsInvalidDocument_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyJSONField("attrs", JSONOptions{Mode: JSONContains}, "{")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("KeyEqualsWithoutKey_returnsError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyJSONField("attrs", JSONOptions{Mode: JSONKeyEquals}, "red")
+
+		// ASSERT
+		require.Error(t, err)
+	})
+}
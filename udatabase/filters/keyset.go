@@ -0,0 +1,155 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// DefaultUniqueKey is the column that keyset sorts end with when no other unique key is set.
+const DefaultUniqueKey = "id"
+
+// SortKey is a column of the sort of a keyset pagination.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// String returns the key as a sort value, e.g. name or -name.
+func (k SortKey) String() string {
+	if k.Desc {
+		return "-" + k.Column
+	}
+	return k.Column
+}
+
+// Name returns the unqualified and unquoted name of the column, e.g. user_id for u."user_id".
+func (k SortKey) Name() string {
+	name := k.Column
+	if !strings.HasSuffix(name, `"`) {
+		return name[strings.LastIndexByte(name, '.')+1:]
+	}
+
+	// The last part is quoted, so it may contain dots and doubled quotes.
+	i := len(name) - 2
+	for i > 0 && (name[i] != '"' || name[i-1] == '"') {
+		if name[i] == '"' {
+			i--
+		}
+		i--
+	}
+	return strings.ReplaceAll(name[i+1:len(name)-1], `""`, `"`)
+}
+
+// SortKeys returns the keys of the sort of values, in the format of ApplySorter, followed by
+// uniqueKey, unless values sort by it already, so that the order of the rows is total. uniqueKey
+// must be a unique and not null column.
+func SortKeys(allowedFields map[string]bool, uniqueKey string, values ...string) ([]SortKey, error) {
+	return sortKeys(func(value string) (string, string, error) {
+		return SortFieldAndDirection(allowedFields, value)
+	}, uniqueKey, values...)
+}
+
+// SortFieldKeys is like SortKeys, but values are names of fields, in the format of ApplySortFields.
+// Keyset paginations can only sort by fields that are columns, not computed expressions.
+func SortFieldKeys(fields SortFields, uniqueKey string, values ...string) ([]SortKey, error) {
+	return sortKeys(func(value string) (string, string, error) {
+		column, direction, err := SortFieldExpressionAndDirection(fields, value)
+		if err != nil {
+			return "", "", err
+		}
+		return column, direction, ident.Validate(column)
+	}, uniqueKey, values...)
+}
+
+func sortKeys(sortField func(value string) (col, dir string, err error), uniqueKey string,
+	values ...string) ([]SortKey, error) {
+	if err := ident.Validate(uniqueKey); err != nil {
+		return nil, err
+	}
+
+	keys := make([]SortKey, 0, len(values)+1)
+	for _, v := range values {
+		column, direction, err := sortField(v)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, SortKey{Column: column, Desc: direction != ""})
+	}
+	return MergeSortKeys(keys, []SortKey{{Column: uniqueKey}}), nil
+}
+
+// MergeSortKeys returns the keys of sorts in order, skipping the columns already sorted by.
+func MergeSortKeys(sorts ...[]SortKey) []SortKey {
+	var keys []SortKey
+	seen := make(map[string]bool)
+	for _, sort := range sorts {
+		for _, k := range sort {
+			if seen[k.Column] {
+				continue
+			}
+			seen[k.Column] = true
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// ApplyKeyset returns the condition that selects the rows after the row whose values of keys are
+// values, or before it if backward is true, in the order of keys. Sort columns must not be null.
+func ApplyKeyset(keys []SortKey, values []any, backward bool) (conds string, args []any, rErr error) {
+	if len(keys) != len(values) {
+		return "", nil, uerr.NewError(uerr.WrongInputParameterError,
+			fmt.Sprintf("Invalid cursor. It has %d values for %d sort fields.", len(values), len(keys)))
+	}
+
+	// (a > ?) OR (a = ? AND b > ?) OR ..., which unlike row comparisons supports mixed directions.
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for i, k := range keys {
+		if err := ident.Validate(k.Column); err != nil {
+			return "", nil, err
+		}
+
+		if i > 0 {
+			sb.WriteString(" OR ")
+		}
+		sb.WriteByte('(')
+		for j := 0; j < i; j++ {
+			sb.WriteString(keys[j].Column)
+			sb.WriteString("=? AND ")
+			args = append(args, values[j])
+		}
+		sb.WriteString(k.Column)
+		if k.Desc != backward {
+			sb.WriteString("<?")
+		} else {
+			sb.WriteString(">?")
+		}
+		args = append(args, values[i])
+		sb.WriteByte(')')
+	}
+	sb.WriteByte(')')
+
+	return sb.String(), args, nil
+}
+
+// KeysetOrder returns the ORDER BY clause, without the keywords, of keys, with every direction
+// reversed if backward is true, e.g. name ASC, id ASC.
+func KeysetOrder(keys []SortKey, backward bool) string {
+	var sb strings.Builder
+	for i, k := range keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(k.Column)
+		if k.Desc != backward {
+			sb.WriteString(" DESC")
+		} else {
+			sb.WriteString(" ASC")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,111 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortKeys(t *testing.T) {
+	allowedFields := map[string]bool{"name": true, "id": true}
+
+	t.Run("Values_areFollowedByUniqueKey", func(t *testing.T) {
+		// ACT
+		keys, err := SortKeys(allowedFields, "id", "-name")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []SortKey{{Column: "name", Desc: true}, {Column: "id"}}, keys)
+	})
+
+	t.Run("ValuesSortingByUniqueKey_doNotRepeatIt", func(t *testing.T) {
+		// ACT
+		keys, err := SortKeys(allowedFields, "id", "-id")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []SortKey{{Column: "id", Desc: true}}, keys)
+	})
+
+	t.Run("NotAllowedField_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, err := SortKeys(allowedFields, "id", "random_number")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
+
+func TestSortFieldKeys(t *testing.T) {
+	fields := SortFields{"user": "u.user_id", "name": "lower(u.name)"}
+
+	t.Run("Names_areSortedByTheirColumns", func(t *testing.T) {
+		// ACT
+		keys, err := SortFieldKeys(fields, "u.id", "-user")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []SortKey{{Column: "u.user_id", Desc: true}, {Column: "u.id"}}, keys)
+	})
+
+	t.Run("Expression_returnsError", func(t *testing.T) {
+		// ACT
+		_, err := SortFieldKeys(fields, "u.id", "name")
+
+		// ASSERT
+		require.Error(t, err)
+	})
+}
+
+func TestApplyKeyset(t *testing.T) {
+	keys := []SortKey{{Column: "name", Desc: true}, {Column: "id"}}
+
+	t.Run("Forward_selectsRowsAfterValues", func(t *testing.T) {
+		// ACT
+		conds, args, err := ApplyKeyset(keys, []any{"b", 2}, false)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "((name<?) OR (name=? AND id>?))", conds)
+		require.Equal(t, []any{"b", "b", 2}, args)
+		require.Equal(t, "name DESC, id ASC", KeysetOrder(keys, false))
+	})
+
+	t.Run("Backward_selectsRowsBeforeValues", func(t *testing.T) {
+		// ACT
+		conds, args, err := ApplyKeyset(keys, []any{"b", 2}, true)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "((name>?) OR (name=? AND id<?))", conds)
+		require.Equal(t, []any{"b", "b", 2}, args)
+		require.Equal(t, "name ASC, id DESC", KeysetOrder(keys, true))
+	})
+
+	t.Run("WrongNumberOfValues_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyKeyset(keys, []any{"b"}, false)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
+
+func TestSortKeyName(t *testing.T) {
+	tests := map[string]string{
+		"name":          "name",
+		"r.name":        "name",
+		`r."Name"`:      "Name",
+		`"a.b"`:         "a.b",
+		`r."quoted""a"`: `quoted"a`,
+	}
+
+	for column, expected := range tests {
+		t.Run(column, func(t *testing.T) {
+			require.Equal(t, expected, SortKey{Column: column}.Name())
+		})
+	}
+}
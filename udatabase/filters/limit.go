@@ -1,6 +1,7 @@
 package filters
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -10,27 +11,53 @@ import (
 const DefaultLimit = 10
 const DefaultLimitStr = " LIMIT 10"
 
+// DefaultMaxLimit is the maximum value of "limit" when no other maximum is set.
+const DefaultMaxLimit = 100
+
+// ApplyLimit is like ApplyLimitWithMax with DefaultMaxLimit.
 func ApplyLimit(query, value string) (queryResult string, limit int64, rErr error) {
+	return ApplyLimitWithMax(query, value, DefaultMaxLimit)
+}
+
+// ApplyLimitWithMax appends the LIMIT of value, which must be a number between 1 and maxLimit,
+// to query. Empty values do not limit the query.
+func ApplyLimitWithMax(query, value string, maxLimit int64) (queryResult string, limit int64, rErr error) {
 	if value == "" {
 		return query, 0, nil
 	}
 
+	num, rErr := ParseLimit(value, maxLimit)
+	if rErr != nil {
+		return "", 0, rErr
+	}
+
+	var sb strings.Builder
+	sb.WriteString(query)
+	sb.WriteString(" LIMIT ")
+	sb.WriteString(value)
+	return sb.String(), num, nil
+}
+
+// ParseLimit returns the number of value, which must be between 1 and maxLimit.
+func ParseLimit(value string, maxLimit int64) (int64, error) {
 	num, err := strconv.Atoi(value)
 	if err != nil {
 		rErr := uerr.NewError(uerr.WrongInputParameterError,
 			`Invalid value for "limit". It must be a number.`).WithCause(err)
-		return "", 0, rErr
+		return 0, rErr
 	}
 
 	if num < 1 {
 		rErr := uerr.NewError(uerr.WrongInputParameterError,
 			`Invalid value for "limit". It must be greater than 0.`)
-		return "", 0, rErr
+		return 0, rErr
 	}
 
-	var sb strings.Builder
-	sb.WriteString(query)
-	sb.WriteString(" LIMIT ")
-	sb.WriteString(value)
-	return sb.String(), int64(num), nil
+	if int64(num) > maxLimit {
+		rErr := uerr.NewError(uerr.WrongInputParameterError,
+			fmt.Sprintf(`Invalid value for "limit". It must be at most %d.`, maxLimit))
+		return 0, rErr
+	}
+
+	return int64(num), nil
 }
@@ -0,0 +1,40 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyLimitWithMax(t *testing.T) {
+	t.Run("LimitUpToMax_isApplied", func(t *testing.T) {
+		// ACT
+		query, limit, err := ApplyLimitWithMax("SELECT 1", "50", 50)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "SELECT 1 LIMIT 50", query)
+		require.Equal(t, int64(50), limit)
+	})
+
+	for _, value := range []string{"51", "0", "ten"} {
+		t.Run("Invalid_"+value+"_returnsWrongInputParameterError", func(t *testing.T) {
+			// ACT
+			_, _, err := ApplyLimitWithMax("SELECT 1", value, 50)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+
+	t.Run("LimitOverDefaultMaxLimit_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyLimit("SELECT 1", "101")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, `Invalid value for "limit". It must be at most 100.`, uerr.GetMessage(err))
+	})
+}
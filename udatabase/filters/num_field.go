@@ -3,52 +3,24 @@ package filters
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/carlosarismendi/utils/uerr"
 )
 
+// ApplyNumField applies the values of a number filter, with any of NumOperators.
 func ApplyNumField(fieldName string, values ...string) (conds string, args []interface{}, rErr error) {
-	amountValues := len(values)
-	if amountValues == 0 {
-		return "", args, rErr
-	}
-
-	args = make([]interface{}, 0, amountValues)
-	var sb strings.Builder
-
-	var num int64
-	if amountValues == 1 {
-		num, rErr = stoi(fieldName, values[0])
-		if rErr != nil {
-			return "", nil, rErr
-		}
-		args = append(args, num)
-
-		sb.Grow(len(fieldName) + 4)
-		sb.WriteByte('(')
-		sb.WriteString(fieldName)
-		sb.WriteString("=?")
-		sb.WriteByte(')')
-	} else {
-		sb.Grow(len(fieldName) + 6 + 2*amountValues)
-		sb.WriteString(fieldName)
-		sb.WriteString(" IN (")
-		sep := byte(' ')
-		for _, v := range values {
-			num, err := stoi(fieldName, v)
-			if err != nil {
-				return "", nil, err
-			}
-			args = append(args, num)
-			sb.WriteByte(sep)
-			sb.WriteByte('?')
-			sep = ','
-		}
-		sb.WriteByte(')')
-	}
+	return ApplyNumFieldWithOperators(fieldName, NumOperators, values...)
+}
 
-	return sb.String(), args, nil
+// ApplyNumFieldWithOperators applies the values of a number filter, which may have any of the
+// operators of allowed (see SplitOperator). Values without operator match any of them with
+// = or IN, and ne values match none of them, while the rest of values must all match.
+// NullValue matches null columns, and not null ones with ne.
+func ApplyNumFieldWithOperators(fieldName string, allowed []Operator,
+	values ...string) (conds string, args []interface{}, rErr error) {
+	return applyComparisonField(fieldName, allowed, func(value string) (interface{}, error) {
+		return stoi(fieldName, value)
+	}, values...)
 }
 
 func stoi(fieldName, s string) (int64, error) {
@@ -0,0 +1,104 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyNumFieldWithOperators(t *testing.T) {
+	tests := []struct {
+		name          string
+		values        []string
+		expectedConds string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "ValueWithoutOperator_isEqual",
+			values:        []string{"10"},
+			expectedConds: "(price=?)",
+			expectedArgs:  []interface{}{int64(10)},
+		},
+		{
+			name:          "Range_isAndOfComparisons",
+			values:        []string{"gte:10", "lt:50"},
+			expectedConds: "((price>=?) AND (price<?))",
+			expectedArgs:  []interface{}{int64(10), int64(50)},
+		},
+		{
+			name:          "NotEqualValues_areNotIn",
+			values:        []string{"ne:1", "ne:2"},
+			expectedConds: "price NOT IN ( ?,?)",
+			expectedArgs:  []interface{}{int64(1), int64(2)},
+		},
+		{
+			name:          "EqualValuesAndComparison_areInAndComparison",
+			values:        []string{"lte:9", "1", "eq:2"},
+			expectedConds: "(price IN ( ?,?) AND (price<=?))",
+			expectedArgs:  []interface{}{int64(1), int64(2), int64(9)},
+		},
+		{
+			name:          "EveryOperator_isAppliedInOrder",
+			values:        []string{"gt:1", "ne:5", "lte:9", "3"},
+			expectedConds: "((price=?) AND (price>?) AND (price<=?) AND (price<>?))",
+			expectedArgs:  []interface{}{int64(3), int64(1), int64(9), int64(5)},
+		},
+		{
+			name:          "Null_isNull",
+			values:        []string{"null"},
+			expectedConds: "(price IS NULL)",
+		},
+		{
+			name:          "NullOrValue_isNullOrEqual",
+			values:        []string{"null", "1"},
+			expectedConds: "((price=?) OR (price IS NULL))",
+			expectedArgs:  []interface{}{int64(1)},
+		},
+		{
+			name:          "NotNullAndNotEqual_isNotNullAndNotEqual",
+			values:        []string{"ne:null", "ne:1"},
+			expectedConds: "((price<>?) AND (price IS NOT NULL))",
+			expectedArgs:  []interface{}{int64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			conds, args, err := ApplyNumFieldWithOperators("price", NumOperators, tt.values...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedConds, conds)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	t.Run("UnknownOperator_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyNumFieldWithOperators("price", NumOperators, "like:10")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("NotAllowedOperator_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyNumFieldWithOperators("price", []Operator{OpEq}, "gt:10")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("OperatorWithoutNumber_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyNumFieldWithOperators("price", NumOperators, "gt:")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
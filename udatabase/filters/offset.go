@@ -19,9 +19,9 @@ func ApplyOffset(query, value string) (queryResult string, offset int64, rErr er
 		return "", 0, rErr
 	}
 
-	if num < 1 {
+	if num < 0 {
 		rErr := uerr.NewError(uerr.WrongInputParameterError,
-			`Invalid value for "offset". It must be greater than 0.`)
+			`Invalid value for "offset". It must be greater or equal to 0.`)
 		return "", 0, rErr
 	}
 
@@ -0,0 +1,31 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOffset(t *testing.T) {
+	t.Run("ZeroOffset_isApplied", func(t *testing.T) {
+		// ACT
+		query, offset, err := ApplyOffset("SELECT 1", "0")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "SELECT 1 OFFSET 0", query)
+		require.Equal(t, int64(0), offset)
+	})
+
+	for _, value := range []string{"-1", "ten"} {
+		t.Run("Invalid_"+value+"_returnsWrongInputParameterError", func(t *testing.T) {
+			// ACT
+			_, _, err := ApplyOffset("SELECT 1", value)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+}
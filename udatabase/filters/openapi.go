@@ -0,0 +1,229 @@
+package filters
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDialect is the dialect of the schemas of ParametersJSONSchema, which is the default
+// dialect of OpenAPI 3.1.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// OpenAPIParameter is an OpenAPI 3 parameter object of a query parameter.
+type OpenAPIParameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Schema      *JSONSchema `json:"schema"`
+}
+
+// JSONSchema is the subset of JSON Schema used to describe query parameters. Operators are
+// in the x-operators extension.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	MinItems             int                    `json:"minItems,omitempty"`
+	MaxItems             int                    `json:"maxItems,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Operators            []Operator             `json:"x-operators,omitempty"`
+}
+
+// OpenAPIParameters returns the OpenAPI 3 query parameters of descriptions, in the same order.
+func OpenAPIParameters(descriptions []Description) []OpenAPIParameter {
+	params := make([]OpenAPIParameter, 0, len(descriptions))
+	for _, d := range descriptions {
+		schema := d.Schema()
+		params = append(params, OpenAPIParameter{
+			Name:        d.Name,
+			In:          "query",
+			Description: schema.Description,
+			Required:    d.Constraints.Required,
+			Schema:      schema,
+		})
+	}
+	return params
+}
+
+// ParametersJSONSchema returns the JSON Schema of the query parameters of descriptions, as an
+// object whose properties are the parameters, which rejects unknown parameters.
+func ParametersJSONSchema(descriptions []Description) *JSONSchema {
+	additionalProperties := false
+	schema := &JSONSchema{
+		Schema:               JSONSchemaDialect,
+		Type:                 "object",
+		Properties:           make(map[string]*JSONSchema, len(descriptions)),
+		AdditionalProperties: &additionalProperties,
+	}
+	for _, d := range descriptions {
+		schema.Properties[d.Name] = d.Schema()
+		if d.Constraints.Required {
+			schema.Required = append(schema.Required, d.Name)
+		}
+	}
+	return schema
+}
+
+// Schema returns the JSON Schema of the values of the parameter of d. Parameters that take many
+// values are arrays. The items of filters with operators are strings, since their values may have
+// an operator or be null, e.g. gte:10 or ne:null, whose pattern matches the values the filter
+// takes, and their constraints are in the description.
+func (d Description) Schema() *JSONSchema {
+	item := &JSONSchema{Type: "string"}
+	var description []string
+	switch d.Type {
+	case IntegerValue:
+		item.Type, item.Format = "integer", "int64"
+	case NumberValue:
+		item.Type = "number"
+	case BooleanValue:
+		item.Type = "boolean"
+	case UUIDValue:
+		item.Format = "uuid"
+	case EnumValue:
+		item.Enum = d.Enum
+	case TimeValue:
+		description = append(description, "Values are RFC 3339 times, dates, which match the whole day, "+
+			"or times relative to now, e.g. now-7d.")
+	case JSONValue:
+		description = append(description, "Values are matched with a jsonb column.")
+	case ArrayValue:
+		description = append(description, "Values are matched with the elements of an array column.")
+	case SortValue:
+		item.Enum = make([]string, 0, 2*len(d.SortFields))
+		for _, f := range d.SortFields {
+			item.Enum = append(item.Enum, f, "-"+f)
+		}
+		description = append(description, "Fields to sort by, in descending order when they start with -.")
+	case LimitValue:
+		return d.numberSchema(&JSONSchema{Type: "integer", Description: "Maximum number of results."})
+	case OffsetValue:
+		return d.numberSchema(&JSONSchema{Type: "integer", Description: "Number of results to skip."})
+	case CursorValue:
+		return &JSONSchema{Type: "string", Description: "NextCursor or PrevCursor of the previous page."}
+	case ExpressionValue:
+		description = append(description, "Expressions that combine the filters with AND, OR and NOT, "+
+			"e.g. status=open OR (assignee=me AND priority=gte:3).")
+	}
+
+	c := &d.Constraints
+	if len(d.Operators) > 0 {
+		ops := make([]string, len(d.Operators))
+		for i, op := range d.Operators {
+			ops[i] = string(op)
+		}
+		item = &JSONSchema{Type: "string", Operators: d.Operators}
+		if value := d.valuePattern(); value != "" {
+			item.Pattern = operatorsPattern(d.Operators, value)
+		}
+		description = append(description, fmt.Sprintf("Values may start with an operator followed by a colon: %s.",
+			strings.Join(ops, ", ")))
+		// Time filters do not take null.
+		if d.Type != TimeValue {
+			description = append(description, "null matches null values.")
+		}
+
+		if c.Min != nil || c.Max != nil {
+			description = append(description, "Values must be "+c.rangeDescription()+".")
+		}
+		if c.Pattern != nil {
+			description = append(description, fmt.Sprintf("Values without their operator must match %q.",
+				c.Pattern.String()))
+		}
+	} else {
+		if c.Pattern != nil {
+			item.Pattern = c.Pattern.String()
+		}
+		d.numberSchema(item)
+	}
+
+	if c.Validator != "" {
+		description = append(description, fmt.Sprintf("Values must be valid for the validator tag %q.",
+			c.Validator))
+	}
+
+	schema := &JSONSchema{Type: "array", Items: item, Description: strings.Join(description, " ")}
+	if c.Required {
+		schema.MinItems = 1
+	}
+	switch {
+	case c.MaxValues == 0:
+		schema.MaxItems = DefaultMaxValues
+	case c.MaxValues > 0:
+		schema.MaxItems = c.MaxValues
+	}
+	return schema
+}
+
+// valuePattern returns the regular expression of the values of d without operator, or "" if any
+// string is a value.
+func (d Description) valuePattern() string {
+	switch d.Type {
+	case IntegerValue:
+		return `[-+]?[0-9]+`
+	case NumberValue:
+		return `[-+]?(?:[0-9]+\.?[0-9]*|\.[0-9]+)(?:[eE][-+]?[0-9]+)?`
+	case BooleanValue:
+		return `1|t|T|TRUE|true|True|0|f|F|FALSE|false|False`
+	case UUIDValue:
+		return `\{?[0-9a-fA-F]{8}(?:-?[0-9a-fA-F]{4}){3}-?[0-9a-fA-F]{12}\}?`
+	case EnumValue:
+		values := make([]string, len(d.Enum))
+		for i, v := range d.Enum {
+			values[i] = regexp.QuoteMeta(v)
+		}
+		return strings.Join(values, "|")
+	default:
+		return ""
+	}
+}
+
+// operatorsPattern returns the regular expression of the values that match value, with any of
+// operators, which is optional if they include eq, and of null and ne:null, see NullValue.
+func operatorsPattern(operators []Operator, value string) string {
+	var prefixes, patterns []string
+	for _, op := range operators {
+		if op == OpBetween {
+			patterns = append(patterns, "^between:(?:"+value+"),(?:"+value+")$")
+		} else {
+			prefixes = append(prefixes, string(op))
+		}
+	}
+
+	if len(prefixes) > 0 {
+		prefix := "(?:" + strings.Join(prefixes, "|") + "):"
+		if slices.Contains(operators, OpEq) {
+			prefix = "(?:" + prefix + ")?"
+		}
+		patterns = append([]string{"^" + prefix + "(?:" + value + ")$"}, patterns...)
+	}
+	return strings.Join(append(patterns, "^(?:ne:)?null$"), "|")
+}
+
+// numberSchema sets the range and the default value of d to the numeric schema s, and returns it.
+func (d Description) numberSchema(s *JSONSchema) *JSONSchema {
+	if s.Type != "integer" && s.Type != "number" {
+		return s
+	}
+
+	s.Minimum, s.Maximum = d.Constraints.Min, d.Constraints.Max
+	if d.Default != "" {
+		if v, err := strconv.ParseFloat(d.Default, 64); err == nil {
+			s.Default = v
+		}
+	}
+	return s
+}
@@ -0,0 +1,268 @@
+package filters
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionSchema(t *testing.T) {
+	minValue, maxValue := 1.0, 100.0
+	tests := []struct {
+		name        string
+		description Description
+		expected    *JSONSchema
+	}{
+		{
+			name:        "TextFilter_isArrayOfStrings",
+			description: Description{Type: TextValue},
+			expected:    &JSONSchema{Type: "array", Items: &JSONSchema{Type: "string"}, MaxItems: DefaultMaxValues},
+		},
+		{
+			name: "NumberFilterWithConstraints_hasStringItemsOfOperatorsAndItemsLimits",
+			description: Description{
+				Type:        IntegerValue,
+				Operators:   []Operator{OpGte, OpLte},
+				Constraints: Constraints{Required: true, MaxValues: 2, Min: &minValue, Max: &maxValue},
+			},
+			expected: &JSONSchema{
+				Type: "array",
+				Description: "Values may start with an operator followed by a colon: gte, lte. " +
+					"null matches null values. Values must be between 1 and 100.",
+				Items: &JSONSchema{
+					Type:      "string",
+					Pattern:   `^(?:gte|lte):(?:[-+]?[0-9]+)$|^(?:ne:)?null$`,
+					Operators: []Operator{OpGte, OpLte},
+				},
+				MinItems: 1,
+				MaxItems: 2,
+			},
+		},
+		{
+			name: "EnumFilterWithPattern_hasEnumAndPattern",
+			description: Description{
+				Type:        EnumValue,
+				Enum:        []string{"open", "closed"},
+				Constraints: Constraints{MaxValues: -1, Pattern: regexp.MustCompile("^[a-z]+$")},
+			},
+			expected: &JSONSchema{
+				Type:  "array",
+				Items: &JSONSchema{Type: "string", Enum: []string{"open", "closed"}, Pattern: "^[a-z]+$"},
+			},
+		},
+		{
+			name:        "Sorter_isArrayOfFieldsInBothDirections",
+			description: DescribeSortFields(SortFields{"name": "name", "created_at": "created_at"}),
+			expected: &JSONSchema{
+				Type:        "array",
+				Description: "Fields to sort by, in descending order when they start with -.",
+				Items:       &JSONSchema{Type: "string", Enum: []string{"created_at", "-created_at", "name", "-name"}},
+			},
+		},
+		{
+			name:        "Limit_isIntegerWithRangeAndDefault",
+			description: DescribeLimit(10, 100),
+			expected: &JSONSchema{
+				Type:        "integer",
+				Description: "Maximum number of results.",
+				Minimum:     &minValue,
+				Maximum:     &maxValue,
+				Default:     10.0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			schema := tt.description.Schema()
+
+			// ASSERT
+			require.Equal(t, tt.expected, schema)
+		})
+	}
+}
+
+func TestDescriptionSchemaOfFilters(t *testing.T) {
+	tests := []struct {
+		name        string
+		description Description
+		apply       func(values ...string) (string, []interface{}, error)
+		values      []string
+		invalid     []string
+	}{
+		{
+			name:        "Integer",
+			description: Description{Type: IntegerValue, Operators: NumOperators},
+			apply: func(values ...string) (string, []interface{}, error) {
+				return ApplyNumField("n", values...)
+			},
+			values:  []string{"10", "-1", "+2", "gte:10", "eq:3", "null", "ne:null"},
+			invalid: []string{"a", "1.5", "gte:", "gt:null", "between:1,2"},
+		},
+		{
+			name:        "Number",
+			description: Description{Type: NumberValue, Operators: NumOperators},
+			apply: func(values ...string) (string, []interface{}, error) {
+				return ApplyFloatField("n", FloatOptions{}, values...)
+			},
+			values:  []string{"1.5", ".5", "-1.", "2e-3", "lt:1E+3", "null"},
+			invalid: []string{"a", "1_000", "0x1p-2", "NaN"},
+		},
+		{
+			name:        "Boolean",
+			description: Description{Type: BooleanValue, Operators: BoolOperators},
+			apply: func(values ...string) (string, []interface{}, error) {
+				return ApplyBoolField("b", values...)
+			},
+			values:  []string{"true", "ne:False", "1", "eq:f", "null", "ne:null"},
+			invalid: []string{"yes", "gt:true", "eq:null"},
+		},
+		{
+			name:        "UUID",
+			description: Description{Type: UUIDValue, Operators: EqualityOperators},
+			apply: func(values ...string) (string, []interface{}, error) {
+				return ApplyUUIDField("id", values...)
+			},
+			values: []string{"5ceff18d-9039-44b5-a5d3-3d99653f4601", "ne:5CEFF18D903944B5A5D33D99653F4601",
+				"{5ceff18d-9039-44b5-a5d3-3d99653f4601}", "null"},
+			invalid: []string{"1", "gt:5ceff18d-9039-44b5-a5d3-3d99653f4601"},
+		},
+		{
+			name:        "Enum",
+			description: Description{Type: EnumValue, Enum: []string{"a.b", "c"}, Operators: EqualityOperators},
+			apply: func(values ...string) (string, []interface{}, error) {
+				return ApplyEnumField("e", []string{"a.b", "c"}, values...)
+			},
+			values:  []string{"a.b", "ne:c", "eq:c", "null"},
+			invalid: []string{"axb", "d", "a.bc"},
+		},
+		{
+			name:        "Time",
+			description: Description{Type: TimeValue, Operators: TimeOperators},
+			apply: func(values ...string) (string, []interface{}, error) {
+				return ApplyTimeField("t", TimeOptions{}, values...)
+			},
+			values: []string{"2024-01-01", "2024-01-01T10:00:00Z", "gte:now-7d", "between:2024-01-01,now"},
+		},
+		{
+			name:        "Text",
+			description: Description{Type: TextValue, Operators: TextOperators},
+			apply: func(values ...string) (string, []interface{}, error) {
+				return ApplyTextField("s", values...)
+			},
+			values: []string{"a", "eq:null", "ne:x", "x:y", "null"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"_matchesValuesOfFilter", func(t *testing.T) {
+			// ACT
+			item := tt.description.Schema().Items
+
+			// ASSERT
+			require.Equal(t, "string", item.Type)
+			require.Empty(t, item.Format)
+			pattern := regexp.MustCompile(item.Pattern)
+			for _, v := range tt.values {
+				_, _, err := tt.apply(v)
+				require.NoError(t, err, v)
+				require.True(t, pattern.MatchString(v), v)
+			}
+			for _, v := range tt.invalid {
+				_, _, err := tt.apply(v)
+				require.Error(t, err, v)
+				require.False(t, pattern.MatchString(v), v)
+			}
+		})
+	}
+}
+
+func TestDescribeParams(t *testing.T) {
+	// ARRANGE
+	params := map[string]any{
+		"offset": DescribeOffset(),
+		"sort":   DescribeSortFields(SortFields{"name": "name"}),
+		"custom": struct{}{},
+	}
+
+	// ACT
+	descriptions := DescribeParams(params)
+
+	// ASSERT
+	expectedSort := DescribeSortFields(SortFields{"name": "name"})
+	expectedSort.Name = "sort"
+	require.Equal(t, []Description{{Name: "custom"}, DescribeOffset(), expectedSort}, descriptions)
+}
+
+func TestOpenAPIParameters(t *testing.T) {
+	// ARRANGE
+	descriptions := []Description{
+		{Name: "name", Type: TextValue, Constraints: Constraints{Required: true}},
+		DescribeOffset(),
+	}
+
+	// ACT
+	params := OpenAPIParameters(descriptions)
+	b, err := json.Marshal(params)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{
+			"name": "name",
+			"in": "query",
+			"required": true,
+			"schema": {"type": "array", "items": {"type": "string"}, "minItems": 1, "maxItems": 100}
+		},
+		{
+			"name": "offset",
+			"in": "query",
+			"description": "Number of results to skip.",
+			"schema": {"type": "integer", "description": "Number of results to skip.", "minimum": 0}
+		}
+	]`, string(b))
+}
+
+func TestParametersJSONSchema(t *testing.T) {
+	// ARRANGE
+	descriptions := []Description{
+		{Name: "active", Type: BooleanValue, Operators: BoolOperators, Constraints: Constraints{Required: true}},
+		DescribeLimit(10, 50),
+	}
+
+	// ACT
+	schema := ParametersJSONSchema(descriptions)
+	b, err := json.Marshal(schema)
+
+	// ASSERT
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"properties": {
+			"active": {
+				"type": "array",
+				"description": "Values may start with an operator followed by a colon: eq, ne. null matches null values.",
+				"items": {
+					"type": "string",
+					"pattern": "^(?:(?:eq|ne):)?(?:1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)$|^(?:ne:)?null$",
+					"x-operators": ["eq", "ne"]
+				},
+				"minItems": 1,
+				"maxItems": 100
+			},
+			"limit": {
+				"type": "integer",
+				"description": "Maximum number of results.",
+				"minimum": 1,
+				"maximum": 50,
+				"default": 10
+			}
+		},
+		"required": ["active"],
+		"additionalProperties": false
+	}`, string(b))
+}
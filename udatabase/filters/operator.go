@@ -0,0 +1,234 @@
+package filters
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// Operator is a comparison operator of a filter value. Values use it as a prefix followed by
+// a colon, e.g. ?price=gte:10&price=lt:50. Values without a prefix use OpEq.
+type Operator string
+
+const (
+	OpEq  Operator = "eq"
+	OpNe  Operator = "ne"
+	OpGt  Operator = "gt"
+	OpGte Operator = "gte"
+	OpLt  Operator = "lt"
+	OpLte Operator = "lte"
+)
+
+// NumOperators are the operators allowed by default in number filters.
+var NumOperators = []Operator{OpEq, OpNe, OpGt, OpGte, OpLt, OpLte}
+
+// EqualityOperators are the operators of the filters whose values can only be compared for
+// equality, such as UUID and enum filters.
+var EqualityOperators = []Operator{OpEq, OpNe}
+
+var comparisonOperators = map[Operator]string{
+	OpEq:  "=",
+	OpNe:  "<>",
+	OpGt:  ">",
+	OpGte: ">=",
+	OpLt:  "<",
+	OpLte: "<=",
+}
+
+// SplitOperator returns the operator and the value of value, e.g. OpGte and 10 for gte:10, or
+// OpEq and value if value has no operator, that is, it does not start with letters and a colon,
+// so values such as times are not taken for operators. It returns a WrongInputParameterError if the
+// operator is not one of allowed.
+func SplitOperator(fieldName string, allowed []Operator, value string) (Operator, string, error) {
+	op := OpEq
+	if prefix, rest, ok := strings.Cut(value, ":"); ok && isOperatorName(prefix) {
+		op, value = Operator(prefix), rest
+	}
+
+	if !slices.Contains(allowed, op) {
+		errMsg := fmt.Sprintf("Invalid operator %q for filter %q.", op, fieldName)
+		if op == OpEq {
+			errMsg = fmt.Sprintf("Filter %q requires an operator.", fieldName)
+		}
+		return "", "", uerr.NewError(uerr.WrongInputParameterError, errMsg)
+	}
+	return op, value, nil
+}
+
+func isOperatorName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// NullValue is the filter value that matches null columns, e.g. ?deleted_at=null, or columns
+// that are not null with the ne operator, e.g. ?deleted_at=ne:null. With the eq operator it is
+// not null but the string null, so eq:null matches text columns holding null.
+const NullValue = "null"
+
+// nullOperator returns OpEq if value matches null columns and OpNe if it matches the ones that
+// are not null, see NullValue. ok is false for the rest of values.
+func nullOperator(value string) (op Operator, ok bool) {
+	switch value {
+	case NullValue:
+		return OpEq, true
+	case string(OpNe) + ":" + NullValue:
+		return OpNe, true
+	default:
+		return "", false
+	}
+}
+
+// equalityConditions returns the condition of the values of a filter without operator, which
+// matches any of eq, or null if eqNull, and the conditions of the ne values, which match none
+// of ne, nor null if neNull. They take eq and ne as arguments, in that order.
+func equalityConditions(fieldName string, eq, ne []interface{}, eqNull, neNull bool) (eqCond string,
+	neConds []string) {
+	if len(eq) > 0 {
+		eqCond = inCondition(fieldName, "=", " IN (", len(eq))
+	}
+	if eqNull {
+		isNull := "(" + fieldName + " IS NULL)"
+		if eqCond == "" {
+			eqCond = isNull
+		} else {
+			eqCond = "(" + eqCond + " OR " + isNull + ")"
+		}
+	}
+
+	if len(ne) > 0 {
+		neConds = append(neConds, inCondition(fieldName, "<>", " NOT IN (", len(ne)))
+	}
+	if neNull {
+		neConds = append(neConds, "("+fieldName+" IS NOT NULL)")
+	}
+	return eqCond, neConds
+}
+
+// inCondition compares fieldName with a single placeholder using op, or with n placeholders using in.
+func inCondition(fieldName, op, in string, n int) string {
+	var sb strings.Builder
+	if n == 1 {
+		sb.Grow(len(fieldName) + len(op) + 3)
+		sb.WriteByte('(')
+		sb.WriteString(fieldName)
+		sb.WriteString(op)
+		sb.WriteString("?)")
+		return sb.String()
+	}
+
+	sb.Grow(len(fieldName) + len(in) + 1 + 2*n)
+	sb.WriteString(fieldName)
+	sb.WriteString(in)
+	sep := byte(' ')
+	for i := 0; i < n; i++ {
+		sb.WriteByte(sep)
+		sb.WriteByte('?')
+		sep = ','
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// joinConditions returns the conditions of parts joined with sep, between parentheses if there
+// are several of them.
+func joinConditions(parts []string, sep string) string {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+// applyComparisonField applies the values of a filter with the operators of allowed, whose values
+// are converted to arguments with parse. Values without operator match any of them with = or IN,
+// and ne values match none of them, while the rest of values must all match. NullValue matches
+// null columns, and not null ones with ne.
+func applyComparisonField(fieldName string, allowed []Operator, parse func(string) (interface{}, error),
+	values ...string) (conds string, args []interface{}, rErr error) {
+	if len(values) == 0 {
+		return "", args, rErr
+	}
+
+	column, rErr := fieldExpression(fieldName)
+	if rErr != nil {
+		return "", nil, rErr
+	}
+
+	var eq, ne, comparisonArgs []interface{}
+	var eqNull, neNull bool
+	var comparisons []string
+	for _, v := range values {
+		if op, ok := nullOperator(v); ok {
+			eqNull, neNull = eqNull || op == OpEq, neNull || op == OpNe
+			continue
+		}
+
+		op, value, err := SplitOperator(fieldName, allowed, v)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var arg interface{}
+		arg, err = parse(value)
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch op {
+		case OpEq:
+			eq = append(eq, arg)
+		case OpNe:
+			ne = append(ne, arg)
+		default:
+			comparisons = append(comparisons, "("+column+comparisonOperators[op]+"?)")
+			comparisonArgs = append(comparisonArgs, arg)
+		}
+	}
+
+	eqCond, neConds := equalityConditions(column, eq, ne, eqNull, neNull)
+	var parts []string
+	if eqCond != "" {
+		parts = append(parts, eqCond)
+	}
+	parts = append(parts, comparisons...)
+	parts = append(parts, neConds...)
+
+	args = append(args, eq...)
+	args = append(args, comparisonArgs...)
+	args = append(args, ne...)
+	return joinConditions(parts, " AND "), args, nil
+}
+
+// splitEqualityOperator returns the operator and the value of value like SplitOperator, but only
+// eq and ne are operators, so values starting with anything else followed by a colon have none.
+func splitEqualityOperator(value string) (Operator, string) {
+	if prefix, rest, ok := strings.Cut(value, ":"); ok && slices.Contains(EqualityOperators, Operator(prefix)) {
+		return Operator(prefix), rest
+	}
+	return OpEq, value
+}
+
+// matchConditions returns the condition that matches any of the conditions of eq, or a null
+// expr if eqNull, and none of the conditions of ne, nor a null expr if neNull.
+func matchConditions(expr string, eq, ne []string, eqNull, neNull bool) string {
+	if eqNull {
+		eq = append(eq, "("+expr+" IS NULL)")
+	}
+
+	var parts []string
+	if len(eq) > 0 {
+		parts = append(parts, joinConditions(eq, " OR "))
+	}
+	for _, cond := range ne {
+		parts = append(parts, "(NOT "+cond+")")
+	}
+	if neNull {
+		parts = append(parts, "("+expr+" IS NOT NULL)")
+	}
+	return joinConditions(parts, " AND ")
+}
@@ -4,17 +4,36 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/carlosarismendi/utils/udatabase/ident"
 	"github.com/carlosarismendi/utils/uerr"
 )
 
 func ApplySorter(allowedFields map[string]bool, values ...string) (queryResult string, rErr error) {
+	return applySorter(func(value string) (string, string, error) {
+		return SortFieldAndDirection(allowedFields, value)
+	}, values...)
+}
+
+// SortFields maps the names of the sort fields of an API to the columns or SQL expressions they
+// sort by, e.g. user to u.user_id or name to lower(name), so that the API does not depend on the
+// schema. Expressions must be valid for ident.ValidateExpression.
+type SortFields map[string]string
+
+// ApplySortFields is like ApplySorter, but values are names of fields, which are sorted by their
+// column or expression.
+func ApplySortFields(fields SortFields, values ...string) (queryResult string, rErr error) {
+	return applySorter(func(value string) (string, string, error) {
+		return SortFieldExpressionAndDirection(fields, value)
+	}, values...)
+}
+
+func applySorter(sortField func(value string) (col, dir string, err error), values ...string) (string, error) {
 	sep := byte(' ')
 	var sb strings.Builder
-	var column, direction string
 	for _, v := range values {
-		column, direction, rErr = SortFieldAndDirection(allowedFields, v)
-		if rErr != nil {
-			return "", rErr
+		column, direction, err := sortField(v)
+		if err != nil {
+			return "", err
 		}
 		sb.Grow(2 + len(column) + len(direction))
 		sb.WriteByte(sep)
@@ -31,7 +50,7 @@ func ApplySorter(allowedFields map[string]bool, values ...string) (queryResult s
 }
 
 func SortFieldAndDirection(allowedFields map[string]bool, value string) (col, dir string, err error) {
-	if value[0] == '-' {
+	if value != "" && value[0] == '-' {
 		col = value[1:]
 		dir = "DESC"
 	} else {
@@ -42,5 +61,30 @@ func SortFieldAndDirection(allowedFields map[string]bool, value string) (col, di
 		return "", "", uerr.NewError(uerr.WrongInputParameterError, fmt.Sprintf("Invalid sort field %q.", col))
 	}
 
+	if err = ident.Validate(col); err != nil {
+		return "", "", err
+	}
+
 	return col, dir, nil
 }
+
+// SortFieldExpressionAndDirection is like SortFieldAndDirection, but the field of value is the
+// name of a field of fields, whose column or expression is returned.
+func SortFieldExpressionAndDirection(fields SortFields, value string) (expr, dir string, err error) {
+	name := value
+	if value != "" && value[0] == '-' {
+		name = value[1:]
+		dir = "DESC"
+	}
+
+	expr, ok := fields[name]
+	if !ok {
+		return "", "", uerr.NewError(uerr.WrongInputParameterError, fmt.Sprintf("Invalid sort field %q.", name))
+	}
+
+	if err = ident.ValidateExpression(expr); err != nil {
+		return "", "", err
+	}
+
+	return expr, dir, nil
+}
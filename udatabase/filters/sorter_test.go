@@ -0,0 +1,40 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySortFields(t *testing.T) {
+	fields := SortFields{"user": "u.user_id", "name": "lower(u.name)"}
+
+	t.Run("Names_areSortedByTheirColumnOrExpression", func(t *testing.T) {
+		// ACT
+		sort, err := ApplySortFields(fields, "-name", "user")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, " lower(u.name) DESC,u.user_id ", sort)
+	})
+
+	for _, value := range []string{"u.user_id", "lower(u.name)", "-unknown"} {
+		t.Run("NotAName_returnsWrongInputParameterError_"+value, func(t *testing.T) {
+			// ACT
+			_, err := ApplySortFields(fields, value)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+
+	t.Run("InvalidExpression_returnsError", func(t *testing.T) {
+		// ACT
+		_, err := ApplySortFields(SortFields{"name": "name; DROP TABLE resources"}, "name")
+
+		// ASSERT
+		require.Error(t, err)
+	})
+}
@@ -1,36 +1,161 @@
 package filters
 
-import "strings"
+import (
+	"fmt"
+	"strings"
 
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// ApplyTextField applies the values of a text filter in TextExact mode, see ApplyTextFieldWithOptions.
 func ApplyTextField(fieldName string, values ...string) (conds string, args []interface{}, rErr error) {
-	amountValues := len(values)
-	if amountValues == 0 {
+	return ApplyTextFieldWithOptions(fieldName, TextOptions{}, values...)
+}
+
+// TextMode is how text filters compare the column with the values.
+type TextMode int
+
+const (
+	// TextExact matches the values exactly, with = or IN.
+	TextExact TextMode = iota
+	// TextContains matches the columns that contain any of the values.
+	TextContains
+	// TextStartsWith matches the columns that start with any of the values.
+	TextStartsWith
+	// TextEndsWith matches the columns that end with any of the values.
+	TextEndsWith
+	// TextSearch matches the columns that contain the words of any of the values with Postgres
+	// full-text search: to_tsvector(language, column) @@ plainto_tsquery(language, value).
+	TextSearch
+)
+
+// DefaultTextSearchLanguage is the text search configuration of TextSearch when none is set.
+const DefaultTextSearchLanguage = "simple"
+
+// TextOptions configure ApplyTextFieldWithOptions.
+type TextOptions struct {
+	Mode TextMode
+
+	// IgnoreCase makes TextExact, TextContains, TextStartsWith and TextEndsWith compare the lower
+	// case of the column and the values. Note that LIKE already ignores ASCII case in SQLite.
+	IgnoreCase bool
+
+	// Language is the text search configuration of TextSearch, e.g. english. When empty,
+	// DefaultTextSearchLanguage is used.
+	Language string
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, which use \ as escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// TextOperators are the operators of text filters. Unlike in other filters, values starting with
+// anything else followed by a colon have no operator, and eq can be used to match the string null
+// or values starting with an operator, e.g. eq:null matches null and eq:ne:x matches ne:x.
+var TextOperators = []Operator{OpEq, OpNe}
+
+// ApplyTextFieldWithOptions applies the values of a text filter in the mode of opts. Columns
+// matching any of the values without operator are selected, unless they match any of the ne
+// values. NullValue matches null columns, and not null ones with ne.
+func ApplyTextFieldWithOptions(fieldName string, opts TextOptions, values ...string) (conds string,
+	args []interface{}, rErr error) {
+	if len(values) == 0 {
 		return "", args, rErr
 	}
 
-	args = make([]interface{}, 0, amountValues)
-	var sb strings.Builder
-	if amountValues == 1 {
-		args = append(args, values[0])
-
-		sb.Grow(len(fieldName) + 4)
-		sb.WriteByte('(')
-		sb.WriteString(fieldName)
-		sb.WriteString("=?")
-		sb.WriteByte(')')
-	} else {
-		sb.Grow(len(fieldName) + 6 + 2*amountValues)
-		sb.WriteString(fieldName)
-		sb.WriteString(" IN (")
-		sep := byte(' ')
-		for _, v := range values {
-			args = append(args, v)
-			sb.WriteByte(sep)
-			sb.WriteByte('?')
-			sep = ','
+	column, rErr := fieldExpression(fieldName)
+	if rErr != nil {
+		return "", nil, rErr
+	}
+
+	cond, rErr := textCondition(column, &opts)
+	if rErr != nil {
+		return "", nil, rErr
+	}
+
+	var eq, ne []interface{}
+	var eqNull, neNull bool
+	for _, v := range values {
+		if op, ok := nullOperator(v); ok {
+			eqNull, neNull = eqNull || op == OpEq, neNull || op == OpNe
+			continue
+		}
+
+		op, v := splitEqualityOperator(v)
+
+		switch opts.Mode {
+		case TextContains:
+			v = "%" + likeEscaper.Replace(v) + "%"
+		case TextStartsWith:
+			v = likeEscaper.Replace(v) + "%"
+		case TextEndsWith:
+			v = "%" + likeEscaper.Replace(v)
+		}
+
+		if op == OpEq {
+			eq = append(eq, v)
+		} else {
+			ne = append(ne, v)
+		}
+	}
+
+	args = append(args, eq...)
+	args = append(args, ne...)
+
+	if cond == "" {
+		eqCond, neConds := equalityConditions(column, eq, ne, eqNull, neNull)
+		if eqCond != "" {
+			neConds = append([]string{eqCond}, neConds...)
 		}
-		sb.WriteByte(')')
+		return joinConditions(neConds, " AND "), args, nil
+	}
+
+	eqConds := make([]string, len(eq))
+	for i := range eq {
+		eqConds[i] = "(" + cond + ")"
 	}
+	neConds := make([]string, len(ne))
+	for i := range ne {
+		neConds[i] = "(" + cond + ")"
+	}
+	return matchConditions(column, eqConds, neConds, eqNull, neNull), args, nil
+}
 
-	return sb.String(), args, nil
+// ValidateTextSearchLanguage returns an error unless language is an unquoted identifier that can be
+// used as the text search configuration of TextSearch, e.g. english or pg_catalog.english.
+func ValidateTextSearchLanguage(language string) error {
+	if ident.Validate(language) != nil || strings.Contains(language, `"`) {
+		return uerr.NewError(uerr.GenericError, fmt.Sprintf("Invalid text search language %q.", language))
+	}
+	return nil
+}
+
+// textCondition returns the condition that compares fieldName with a value in the mode of opts,
+// or "" for the exact comparisons of equalityConditions.
+func textCondition(fieldName string, opts *TextOptions) (string, error) {
+	column, placeholder := fieldName, "?"
+	if opts.IgnoreCase {
+		column, placeholder = "LOWER("+fieldName+")", "LOWER(?)"
+	}
+
+	switch opts.Mode {
+	case TextExact:
+		if !opts.IgnoreCase {
+			return "", nil
+		}
+		return column + "=" + placeholder, nil
+	case TextContains, TextStartsWith, TextEndsWith:
+		return column + " LIKE " + placeholder + ` ESCAPE '\'`, nil
+	case TextSearch:
+		language := opts.Language
+		if language == "" {
+			language = DefaultTextSearchLanguage
+		}
+		if err := ValidateTextSearchLanguage(language); err != nil {
+			return "", err
+		}
+		return "to_tsvector('" + language + "', " + fieldName + ") @@ plainto_tsquery('" + language + "', ?)", nil
+	default:
+		return "", fmt.Errorf("unknown text mode %d", opts.Mode)
+	}
 }
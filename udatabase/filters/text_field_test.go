@@ -0,0 +1,158 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTextFieldWithOptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          TextOptions
+		values        []string
+		expectedConds string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "Exact_isEqual",
+			opts:          TextOptions{},
+			values:        []string{"a"},
+			expectedConds: "(name=?)",
+			expectedArgs:  []interface{}{"a"},
+		},
+		{
+			name:          "ExactIgnoringCase_comparesLowerCase",
+			opts:          TextOptions{IgnoreCase: true},
+			values:        []string{"A", "b"},
+			expectedConds: "((LOWER(name)=LOWER(?)) OR (LOWER(name)=LOWER(?)))",
+			expectedArgs:  []interface{}{"A", "b"},
+		},
+		{
+			name:          "Contains_escapesWildcards",
+			opts:          TextOptions{Mode: TextContains},
+			values:        []string{`50%_off\`},
+			expectedConds: `(name LIKE ? ESCAPE '\')`,
+			expectedArgs:  []interface{}{`%50\%\_off\\%`},
+		},
+		{
+			name:          "StartsWithIgnoringCase_comparesLowerCase",
+			opts:          TextOptions{Mode: TextStartsWith, IgnoreCase: true},
+			values:        []string{"Res"},
+			expectedConds: `(LOWER(name) LIKE LOWER(?) ESCAPE '\')`,
+			expectedArgs:  []interface{}{"Res%"},
+		},
+		{
+			name:          "EndsWith_matchesSuffix",
+			opts:          TextOptions{Mode: TextEndsWith},
+			values:        []string{"1"},
+			expectedConds: `(name LIKE ? ESCAPE '\')`,
+			expectedArgs:  []interface{}{"%1"},
+		},
+		{
+			name:          "SearchWithoutLanguage_usesSimple",
+			opts:          TextOptions{Mode: TextSearch},
+			values:        []string{"quick fox"},
+			expectedConds: "(to_tsvector('simple', name) @@ plainto_tsquery('simple', ?))",
+			expectedArgs:  []interface{}{"quick fox"},
+		},
+		{
+			name:          "SearchWithLanguage_usesLanguage",
+			opts:          TextOptions{Mode: TextSearch, Language: "english"},
+			values:        []string{"fox"},
+			expectedConds: "(to_tsvector('english', name) @@ plainto_tsquery('english', ?))",
+			expectedArgs:  []interface{}{"fox"},
+		},
+		{
+			name:          "Null_isNull",
+			opts:          TextOptions{},
+			values:        []string{"null"},
+			expectedConds: "(name IS NULL)",
+		},
+		{
+			name:          "NotNull_isNotNull",
+			opts:          TextOptions{Mode: TextContains},
+			values:        []string{"ne:null"},
+			expectedConds: "(name IS NOT NULL)",
+		},
+		{
+			name:          "EqualNull_isStringNull",
+			opts:          TextOptions{},
+			values:        []string{"eq:null", "ne:null"},
+			expectedConds: "((name=?) AND (name IS NOT NULL))",
+			expectedArgs:  []interface{}{"null"},
+		},
+		{
+			name:          "NotEqualValues_areNotIn",
+			opts:          TextOptions{},
+			values:        []string{"ne:a", "ne:b"},
+			expectedConds: "name NOT IN ( ?,?)",
+			expectedArgs:  []interface{}{"a", "b"},
+		},
+		{
+			name:          "NotContains_isNegated",
+			opts:          TextOptions{Mode: TextContains},
+			values:        []string{"a", "ne:b"},
+			expectedConds: `((name LIKE ? ESCAPE '\') AND (NOT (name LIKE ? ESCAPE '\')))`,
+			expectedArgs:  []interface{}{"%a%", "%b%"},
+		},
+		{
+			name:          "UnknownOperator_isPartOfTheValue",
+			opts:          TextOptions{},
+			values:        []string{"note:a"},
+			expectedConds: "(name=?)",
+			expectedArgs:  []interface{}{"note:a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			conds, args, err := ApplyTextFieldWithOptions("name", tt.opts, tt.values...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedConds, conds)
+			require.Equal(t, tt.expectedArgs, args)
+		})
+	}
+
+	t.Run("InvalidLanguage_returnsError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyTextFieldWithOptions("name", TextOptions{Mode: TextSearch, Language: "english'"}, "fox")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.GenericError, uerr.GetKey(err))
+	})
+}
+
+func TestValidateTextSearchLanguage(t *testing.T) {
+	tests := []struct {
+		language string
+		valid    bool
+	}{
+		{language: "english", valid: true},
+		{language: "pg_catalog.english", valid: true},
+		{language: `"english"`},
+		{language: `pg_catalog."english"`},
+		{language: "english'"},
+		{language: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			// ACT
+			err := ValidateTextSearchLanguage(tt.language)
+
+			// ASSERT
+			if tt.valid {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, uerr.GenericError, uerr.GetKey(err))
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// OpBetween matches the values between two bounds, both included, separated by a comma,
+// e.g. between:2024-01-01,2024-01-31.
+const OpBetween Operator = "between"
+
+// TimeOperators are the operators allowed by default in time filters.
+var TimeOperators = []Operator{OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpBetween}
+
+const dateLayout = "2006-01-02"
+
+// TimeOptions configure ApplyTimeField.
+type TimeOptions struct {
+	// Operators are the operators allowed in the values. When empty, TimeOperators are used.
+	Operators []Operator
+
+	// Location is the time zone of the values without offset, such as dates, and of the days
+	// of relative values. When nil, UTC is used.
+	Location *time.Location
+
+	// Now returns the time that relative values are relative to. When nil, time.Now is used.
+	Now func() time.Time
+}
+
+// timeValue is the range of times matched by a value: an instant, or a whole day for dates.
+type timeValue struct {
+	start time.Time
+	end   time.Time // Exclusive end of dates.
+	date  bool
+}
+
+// ApplyTimeField applies the values of a time filter. Values are RFC 3339 times, with or without
+// offset, dates (2006-01-02), which match the whole day, or times relative to now such as now,
+// now-7d or now+1h30m, with the units of time.ParseDuration plus d (days) and w (weeks).
+// They may have any operator of opts (see SplitOperator). Values without operator match any of
+// them, while the rest of values must all match.
+func ApplyTimeField(fieldName string, opts TimeOptions, values ...string) (conds string, args []interface{},
+	rErr error) {
+	if len(values) == 0 {
+		return "", args, rErr
+	}
+
+	column, rErr := fieldExpression(fieldName)
+	if rErr != nil {
+		return "", nil, rErr
+	}
+
+	operators := opts.Operators
+	if len(operators) == 0 {
+		operators = TimeOperators
+	}
+
+	var eq, parts []string
+	var eqArgs []interface{}
+	for _, v := range values {
+		op, value, err := SplitOperator(fieldName, operators, v)
+		if err != nil {
+			return "", nil, err
+		}
+
+		cond, condArgs, err := timeCondition(fieldName, column, op, value, &opts)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if op == OpEq {
+			eq = append(eq, cond)
+			eqArgs = append(eqArgs, condArgs...)
+		} else {
+			parts = append(parts, cond)
+			args = append(args, condArgs...)
+		}
+	}
+
+	if len(eq) == 1 {
+		parts = append([]string{eq[0]}, parts...)
+	} else if len(eq) > 1 {
+		parts = append([]string{"(" + strings.Join(eq, " OR ") + ")"}, parts...)
+	}
+	args = append(eqArgs, args...)
+
+	if len(parts) == 1 {
+		return parts[0], args, nil
+	}
+	return "(" + strings.Join(parts, " AND ") + ")", args, nil
+}
+
+func timeCondition(fieldName, column string, op Operator, value string, opts *TimeOptions) (string, []interface{},
+	error) {
+	if op == OpBetween {
+		from, to, ok := strings.Cut(value, ",")
+		if !ok {
+			errMsg := fmt.Sprintf("Invalid value for filter %q. between requires two values separated by a comma.",
+				fieldName)
+			return "", nil, uerr.NewError(uerr.WrongInputParameterError, errMsg)
+		}
+
+		fromValue, err := parseTime(fieldName, from, opts)
+		if err != nil {
+			return "", nil, err
+		}
+		toValue, err := parseTime(fieldName, to, opts)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if toValue.date {
+			return "(" + column + ">=? AND " + column + "<?)", []interface{}{fromValue.start, toValue.end}, nil
+		}
+		return "(" + column + ">=? AND " + column + "<=?)", []interface{}{fromValue.start, toValue.start}, nil
+	}
+
+	t, err := parseTime(fieldName, value, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if !t.date {
+		return "(" + column + comparisonOperators[op] + "?)", []interface{}{t.start}, nil
+	}
+
+	switch op {
+	case OpEq:
+		return "(" + column + ">=? AND " + column + "<?)", []interface{}{t.start, t.end}, nil
+	case OpNe:
+		return "(" + column + "<? OR " + column + ">=?)", []interface{}{t.start, t.end}, nil
+	case OpGt:
+		return "(" + column + ">=?)", []interface{}{t.end}, nil
+	case OpGte:
+		return "(" + column + ">=?)", []interface{}{t.start}, nil
+	case OpLt:
+		return "(" + column + "<?)", []interface{}{t.start}, nil
+	default: // OpLte
+		return "(" + column + "<?)", []interface{}{t.end}, nil
+	}
+}
+
+func parseTime(fieldName, value string, opts *TimeOptions) (timeValue, error) {
+	loc := opts.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "now") {
+		t, err := parseRelativeTime(value[len("now"):], opts, loc)
+		if err != nil {
+			return timeValue{}, invalidTimeError(fieldName, value, err)
+		}
+		return timeValue{start: t, end: t}, nil
+	}
+
+	if t, err := time.ParseInLocation(dateLayout, value, loc); err == nil {
+		return timeValue{start: t, end: t.AddDate(0, 0, 1), date: true}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return timeValue{start: t, end: t}, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02T15:04:05.999999999", value, loc)
+	if err != nil {
+		return timeValue{}, invalidTimeError(fieldName, value, err)
+	}
+	return timeValue{start: t, end: t}, nil
+}
+
+// parseRelativeTime returns now plus offset, such as -7d or +1h30m.
+func parseRelativeTime(offset string, opts *TimeOptions, loc *time.Location) (time.Time, error) {
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	t := now().In(loc)
+	if offset == "" {
+		return t, nil
+	}
+
+	if offset[0] != '+' && offset[0] != '-' {
+		return time.Time{}, fmt.Errorf("missing sign in %q", offset)
+	}
+
+	// Days and weeks are added to the date, so they keep the time of the day across DST changes.
+	if unit := offset[len(offset)-1]; unit == 'd' || unit == 'w' {
+		n, err := strconv.Atoi(offset[:len(offset)-1])
+		if err != nil {
+			return time.Time{}, err
+		}
+		if unit == 'w' {
+			n *= 7
+		}
+		return t.AddDate(0, 0, n), nil
+	}
+
+	d, err := time.ParseDuration(offset)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Add(d), nil
+}
+
+func invalidTimeError(fieldName, value string, err error) error {
+	errMsg := fmt.Sprintf("Invalid value %q for filter %q. It must be a RFC 3339 time, a date (2006-01-02) "+
+		"or a time relative to now such as now-7d.", value, fieldName)
+	return uerr.NewError(uerr.WrongInputParameterError, errMsg).WithCause(err)
+}
@@ -0,0 +1,102 @@
+package filters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyTimeField(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	require.NoError(t, err)
+
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	opts := TimeOptions{Location: madrid, Now: func() time.Time { return now }}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, madrid)
+	nextDay := time.Date(2024, 1, 3, 0, 0, 0, 0, madrid)
+	instant := time.Date(2024, 1, 2, 10, 30, 0, 0, time.FixedZone("", 2*60*60))
+
+	tests := []struct {
+		name          string
+		values        []string
+		expectedConds string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "RFC3339_isEqual",
+			values:        []string{"2024-01-02T10:30:00+02:00"},
+			expectedConds: "(created_at=?)",
+			expectedArgs:  []interface{}{instant},
+		},
+		{
+			name:          "TimeWithoutOffset_isInLocation",
+			values:        []string{"gt:2024-01-02T10:30:00"},
+			expectedConds: "(created_at>?)",
+			expectedArgs:  []interface{}{time.Date(2024, 1, 2, 10, 30, 0, 0, madrid)},
+		},
+		{
+			name:          "Date_matchesWholeDay",
+			values:        []string{"2024-01-02"},
+			expectedConds: "(created_at>=? AND created_at<?)",
+			expectedArgs:  []interface{}{day, nextDay},
+		},
+		{
+			name:          "DateBounds_includeOrExcludeWholeDays",
+			values:        []string{"gt:2024-01-02", "lte:2024-01-02"},
+			expectedConds: "((created_at>=?) AND (created_at<?))",
+			expectedArgs:  []interface{}{nextDay, nextDay},
+		},
+		{
+			name:          "BetweenDates_includesBothDays",
+			values:        []string{"between:2024-01-01,2024-01-02"},
+			expectedConds: "(created_at>=? AND created_at<?)",
+			expectedArgs:  []interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, madrid), nextDay},
+		},
+		{
+			name:          "RelativeTimes_areRelativeToNow",
+			values:        []string{"gte:now-7d", "lt:now+1h30m"},
+			expectedConds: "((created_at>=?) AND (created_at<?))",
+			expectedArgs: []interface{}{
+				now.In(madrid).AddDate(0, 0, -7),
+				now.In(madrid).Add(90 * time.Minute),
+			},
+		},
+		{
+			name:          "SeveralEqualValues_matchAnyOfThem",
+			values:        []string{"2024-01-02", "now"},
+			expectedConds: "((created_at>=? AND created_at<?) OR (created_at=?))",
+			expectedArgs:  []interface{}{day, nextDay, now.In(madrid)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			conds, args, err := ApplyTimeField("created_at", opts, tt.values...)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedConds, conds)
+			require.Len(t, args, len(tt.expectedArgs))
+			for i := range args {
+				require.True(t, tt.expectedArgs[i].(time.Time).Equal(args[i].(time.Time)),
+					"expected %v, got %v", tt.expectedArgs[i], args[i])
+			}
+		})
+	}
+
+	invalidValues := []string{"yesterday", "now-7x", "now7d", "between:2024-01-01", "2024-13-01", "like:2024-01-01"}
+	for _, value := range invalidValues {
+		t.Run("InvalidValue_"+value+"_returnsWrongInputParameterError", func(t *testing.T) {
+			// ACT
+			_, _, err := ApplyTimeField("created_at", opts, value)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+}
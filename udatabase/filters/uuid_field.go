@@ -0,0 +1,23 @@
+package filters
+
+import (
+	"fmt"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/google/uuid"
+)
+
+// ApplyUUIDField applies the values of a UUID filter. Values without operator match any of
+// them with = or IN, and ne values match none of them. NullValue matches null columns, and not
+// null ones with ne. Values are passed to the query in their canonical form, e.g.
+// 5ceff18d-9039-44b5-a5d3-3d99653f4601, whatever the format accepted by uuid.Parse they had.
+func ApplyUUIDField(fieldName string, values ...string) (conds string, args []interface{}, rErr error) {
+	return applyComparisonField(fieldName, EqualityOperators, func(value string) (interface{}, error) {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			errMsg := fmt.Sprintf("Invalid value for filter %q. It must be a UUID.", fieldName)
+			return nil, uerr.NewError(uerr.WrongInputParameterError, errMsg).WithCause(err)
+		}
+		return id.String(), nil
+	}, values...)
+}
@@ -0,0 +1,42 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyUUIDField(t *testing.T) {
+	t.Run("Values_areCanonicalAndIn", func(t *testing.T) {
+		// ACT
+		conds, args, err := ApplyUUIDField("id", "5CEFF18D-9039-44B5-A5D3-3D99653F4601",
+			"{5ceff18d-9039-44b5-a5d3-3d99653f4602}", "ne:null")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "(id IN ( ?,?) AND (id IS NOT NULL))", conds)
+		require.Equal(t, []interface{}{
+			"5ceff18d-9039-44b5-a5d3-3d99653f4601",
+			"5ceff18d-9039-44b5-a5d3-3d99653f4602",
+		}, args)
+	})
+
+	t.Run("InvalidValue_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyUUIDField("id", "5ceff18d")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("ComparisonOperator_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, _, err := ApplyUUIDField("id", "gt:5ceff18d-9039-44b5-a5d3-3d99653f4601")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
@@ -0,0 +1,335 @@
+package udatabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// FixtureStatement is a statement that loads part of a fixture file. Query uses ? placeholders,
+// so it has to be rebound to the placeholders of the dialect before running it.
+type FixtureStatement struct {
+	// Table is the table of the row inserted by Query, or empty for SQL files.
+	Table string
+	Query string
+	Args  []any
+}
+
+// FixtureDB is the database in which LoadFixtures loads the fixtures, usually a transaction.
+type FixtureDB interface {
+	// Exec runs query with args. query uses ? placeholders when it has args, see FixtureStatement.
+	Exec(ctx context.Context, query string, args ...any) error
+
+	// QueryPairs runs query, which has no arguments and selects two text columns, and returns
+	// its rows. See ScanPairs.
+	QueryPairs(ctx context.Context, query string) ([][2]string, error)
+}
+
+// ForeignKeysDialect is implemented by the dialects that can list the foreign keys of the
+// tables of the database, so LoadFixtures inserts rows after the rows they reference.
+type ForeignKeysDialect interface {
+	// ForeignKeysQuery returns a query without arguments that selects the name of every table
+	// with a foreign key and the name of the table it references.
+	ForeignKeysQuery() string
+}
+
+// LoadFixtures loads the fixture files of paths, see ReadFixtures, in db. When dialect is a
+// ForeignKeysDialect, the rows of the tables of the files are sorted with SortFixtures by the
+// foreign keys of the database, so they can be written in any order. Errors of the statements
+// are returned as they are returned by db.
+func LoadFixtures(ctx context.Context, db FixtureDB, dialect Dialect, paths ...string) error {
+	stmts, err := ReadFixtures(paths...)
+	if err != nil {
+		return err
+	}
+
+	hasRows := slices.ContainsFunc(stmts, func(stmt FixtureStatement) bool { return stmt.Table != "" })
+	if fkDialect, ok := dialect.(ForeignKeysDialect); ok && hasRows {
+		references, err := db.QueryPairs(ctx, fkDialect.ForeignKeysQuery())
+		if err != nil {
+			return fmt.Errorf("reading foreign keys: %w", err)
+		}
+		stmts = SortFixtures(stmts, references)
+	}
+
+	for _, stmt := range stmts {
+		if err = db.Exec(ctx, stmt.Query, stmt.Args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SortFixtures returns stmts with the rows of every table after the rows of the tables they
+// reference, keeping the order of the rows of each table. references are pairs of a table and
+// a table it references, whose names are compared without schema and case. Statements of SQL
+// files are kept in place, so only the rows between them are sorted. The reference that closes a
+// cycle of tables that reference each other is ignored, as well as references of a table to itself.
+func SortFixtures(stmts []FixtureStatement, references [][2]string) []FixtureStatement {
+	referenced := make(map[string][]string, len(references))
+	for _, ref := range references {
+		table, parent := fixtureTableKey(ref[0]), fixtureTableKey(ref[1])
+		if table != parent {
+			referenced[table] = append(referenced[table], parent)
+		}
+	}
+
+	sorted := make([]FixtureStatement, 0, len(stmts))
+	for start := 0; start < len(stmts); {
+		if stmts[start].Table == "" {
+			sorted = append(sorted, stmts[start])
+			start++
+			continue
+		}
+
+		end := start
+		for end < len(stmts) && stmts[end].Table != "" {
+			end++
+		}
+		sorted = append(sorted, sortFixtureRows(stmts[start:end], referenced)...)
+		start = end
+	}
+	return sorted
+}
+
+// sortFixtureRows sorts the tables of rows topologically, in the order in which they appear
+// in rows when they do not depend on each other.
+func sortFixtureRows(rows []FixtureStatement, referenced map[string][]string) []FixtureStatement {
+	var tables []string
+	rowsByTable := make(map[string][]FixtureStatement)
+	for _, row := range rows {
+		table := fixtureTableKey(row.Table)
+		if _, ok := rowsByTable[table]; !ok {
+			tables = append(tables, table)
+		}
+		rowsByTable[table] = append(rowsByTable[table], row)
+	}
+
+	sorted := make([]FixtureStatement, 0, len(rows))
+	const visiting, visited = 1, 2
+	state := make(map[string]int, len(tables))
+	var visit func(table string)
+	visit = func(table string) {
+		if state[table] != 0 {
+			return
+		}
+		state[table] = visiting
+		for _, parent := range referenced[table] {
+			if _, ok := rowsByTable[parent]; ok && state[parent] != visiting {
+				visit(parent)
+			}
+		}
+		state[table] = visited
+		sorted = append(sorted, rowsByTable[table]...)
+	}
+
+	for _, table := range tables {
+		visit(table)
+	}
+	return sorted
+}
+
+// fixtureTableKey returns the name of table without schema, quotes and case.
+func fixtureTableKey(table string) string {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		table = table[i+1:]
+	}
+	return strings.ToLower(strings.Trim(table, `"`))
+}
+
+// PairRows are the rows of a query, such as *sql.Rows or pgx.Rows.
+type PairRows interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+}
+
+// ScanPairs returns the rows of a query that selects two text columns. The caller closes rows.
+func ScanPairs(rows PairRows) ([][2]string, error) {
+	var pairs [][2]string
+	for rows.Next() {
+		var pair [2]string
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, rows.Err()
+}
+
+// ReadFixtures reads the fixture files of paths and returns the statements that load them.
+//
+// YAML (.yaml, .yml) and JSON (.json) files map table names, which may be qualified by their
+// schema, to lists of rows, each row mapping columns to values. Values that are objects or lists
+// are stored as JSON. SQL (.sql) files are run as they are. Statements are returned in the order
+// of paths and, within a file, in the order in which the tables are written; LoadFixtures sorts
+// them by the foreign keys of the tables.
+//
+// Files are text/template templates, executed before they are parsed, with the functions:
+//   - now: the time of the call to ReadFixtures, in RFC 3339 format.
+//   - nowAdd "-24h": now plus a time.ParseDuration duration, in RFC 3339 format.
+//   - uuid: a random UUID. uuid "name" returns the same random UUID for every "name" in
+//     the files of the call, so rows can reference each other.
+func ReadFixtures(paths ...string) ([]FixtureStatement, error) {
+	funcs := fixtureFuncs(time.Now().UTC())
+
+	var stmts []FixtureStatement
+	for _, path := range paths {
+		fileStmts, err := readFixture(path, funcs)
+		if err != nil {
+			return nil, fmt.Errorf("loading fixture %q: %w", path, err)
+		}
+		stmts = append(stmts, fileStmts...)
+	}
+	return stmts, nil
+}
+
+func fixtureFuncs(now time.Time) template.FuncMap {
+	uuids := make(map[string]string)
+	return template.FuncMap{
+		"now": func() string {
+			return now.Format(time.RFC3339Nano)
+		},
+		"nowAdd": func(d string) (string, error) {
+			duration, err := time.ParseDuration(d)
+			if err != nil {
+				return "", err
+			}
+			return now.Add(duration).Format(time.RFC3339Nano), nil
+		},
+		"uuid": func(name ...string) (string, error) {
+			switch len(name) {
+			case 0:
+				return uuid.NewString(), nil
+			case 1:
+				if _, ok := uuids[name[0]]; !ok {
+					uuids[name[0]] = uuid.NewString()
+				}
+				return uuids[name[0]], nil
+			default:
+				return "", fmt.Errorf("uuid expects at most one name, got %d", len(name))
+			}
+		},
+	}
+}
+
+func readFixture(path string, funcs template.FuncMap) ([]FixtureStatement, error) {
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, nil); err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".sql":
+		return []FixtureStatement{{Query: buf.String()}}, nil
+	case ".yaml", ".yml", ".json":
+		return parseFixtureRows(buf.Bytes())
+	default:
+		return nil, fmt.Errorf("unsupported fixture extension %q", filepath.Ext(path))
+	}
+}
+
+// parseFixtureRows parses the rows with yaml.Node, which unlike maps keeps the order of
+// the tables and columns. JSON is parsed the same way since it is valid YAML.
+func parseFixtureRows(content []byte) ([]FixtureStatement, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	tables := doc.Content[0]
+	if tables.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: expected a mapping of table names to rows", tables.Line)
+	}
+
+	var stmts []FixtureStatement
+	for i := 0; i < len(tables.Content); i += 2 {
+		table, rows := tables.Content[i], tables.Content[i+1]
+		if rows.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("line %d: expected a list of rows for table %q", rows.Line, table.Value)
+		}
+
+		for _, row := range rows.Content {
+			stmt, err := insertStatement(table.Value, row)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts, nil
+}
+
+func insertStatement(table string, row *yaml.Node) (FixtureStatement, error) {
+	if row.Kind != yaml.MappingNode || len(row.Content) == 0 {
+		return FixtureStatement{}, fmt.Errorf("line %d: expected a mapping of columns to values", row.Line)
+	}
+
+	quotedTable, err := ident.QuoteQualified(table)
+	if err != nil {
+		return FixtureStatement{}, err
+	}
+
+	columns := make([]string, 0, len(row.Content)/2)
+	args := make([]any, 0, len(row.Content)/2)
+	for i := 0; i < len(row.Content); i += 2 {
+		column, err := ident.Quote(row.Content[i].Value)
+		if err != nil {
+			return FixtureStatement{}, err
+		}
+
+		value, err := fixtureValue(row.Content[i+1])
+		if err != nil {
+			return FixtureStatement{}, err
+		}
+
+		columns = append(columns, column)
+		args = append(args, value)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quotedTable, strings.Join(columns, ", "), placeholders)
+	return FixtureStatement{Table: table, Query: query, Args: args}, nil
+}
+
+func fixtureValue(node *yaml.Node) (any, error) {
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	switch value.(type) {
+	case map[string]any, []any:
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,191 @@
+package udatabase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFixture(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReadFixtures(t *testing.T) {
+	t.Run("YAMLFixture_returnsInsertsInOrder", func(t *testing.T) {
+		// ARRANGE
+		path := writeFixture(t, "resources.yaml", `
+users:
+  - id: 1
+    name: User1
+resources:
+  - id: 2
+    user_id: 1
+    tags: [a, b]
+`)
+
+		// ACT
+		stmts, err := ReadFixtures(path)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []FixtureStatement{
+			{Table: "users", Query: `INSERT INTO "users" ("id", "name") VALUES (?, ?)`, Args: []any{1, "User1"}},
+			{
+				Table: "resources",
+				Query: `INSERT INTO "resources" ("id", "user_id", "tags") VALUES (?, ?, ?)`,
+				Args:  []any{2, 1, `["a","b"]`},
+			},
+		}, stmts)
+	})
+
+	t.Run("SchemaQualifiedTable_quotesEveryPart", func(t *testing.T) {
+		// ARRANGE
+		path := writeFixture(t, "users.yaml", `
+public.users:
+  - id: 1
+`)
+
+		// ACT
+		stmts, err := ReadFixtures(path)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []FixtureStatement{
+			{Table: "public.users", Query: `INSERT INTO "public"."users" ("id") VALUES (?)`, Args: []any{1}},
+		}, stmts)
+	})
+
+	t.Run("JSONFixture_returnsInserts", func(t *testing.T) {
+		// ARRANGE
+		path := writeFixture(t, "resources.json", `{"resources": [{"id": 1, "data": {"key": "value"}}]}`)
+
+		// ACT
+		stmts, err := ReadFixtures(path)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []FixtureStatement{
+			{
+				Table: "resources",
+				Query: `INSERT INTO "resources" ("id", "data") VALUES (?, ?)`,
+				Args:  []any{1, `{"key":"value"}`},
+			},
+		}, stmts)
+	})
+
+	t.Run("SQLFixture_returnsFileContent", func(t *testing.T) {
+		// ARRANGE
+		path := writeFixture(t, "resources.sql", "INSERT INTO resources (id) VALUES (1);")
+
+		// ACT
+		stmts, err := ReadFixtures(path)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []FixtureStatement{{Query: "INSERT INTO resources (id) VALUES (1);"}}, stmts)
+	})
+
+	t.Run("TemplateFunctions_areExecuted", func(t *testing.T) {
+		// ARRANGE
+		users := writeFixture(t, "users.yaml", `users: [{id: '{{ uuid "user1" }}', created_at: '{{ now }}'}]`)
+		resources := writeFixture(t, "resources.yaml",
+			`resources: [{id: '{{ uuid }}', user_id: '{{ uuid "user1" }}', created_at: '{{ nowAdd "-24h" }}'}]`)
+
+		// ACT
+		stmts, err := ReadFixtures(users, resources)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Len(t, stmts, 2)
+		require.Equal(t, stmts[0].Args[0], stmts[1].Args[1])
+		require.NotEqual(t, stmts[0].Args[0], stmts[1].Args[0])
+
+		now, err := time.Parse(time.RFC3339Nano, stmts[0].Args[1].(string))
+		require.NoError(t, err)
+		yesterday, err := time.Parse(time.RFC3339Nano, stmts[1].Args[2].(string))
+		require.NoError(t, err)
+		require.Equal(t, 24*time.Hour, now.Sub(yesterday))
+	})
+
+	t.Run("RowNotMappingColumnsToValues_returnsError", func(t *testing.T) {
+		// ARRANGE
+		path := writeFixture(t, "resources.yaml", "resources: [1]")
+
+		// ACT
+		_, err := ReadFixtures(path)
+
+		// ASSERT
+		require.Error(t, err)
+	})
+
+	t.Run("UnsupportedExtension_returnsError", func(t *testing.T) {
+		// ARRANGE
+		path := writeFixture(t, "resources.csv", "id\n1")
+
+		// ACT
+		_, err := ReadFixtures(path)
+
+		// ASSERT
+		require.Error(t, err)
+	})
+}
+
+func TestSortFixtures(t *testing.T) {
+	row := func(table string, id int) FixtureStatement {
+		return FixtureStatement{Table: table, Query: "INSERT INTO " + table, Args: []any{id}}
+	}
+	sql := FixtureStatement{Query: "INSERT INTO users (id) VALUES (3);"}
+
+	tests := []struct {
+		name       string
+		stmts      []FixtureStatement
+		references [][2]string
+		expected   []FixtureStatement
+	}{
+		{
+			name:       "ReferencingTablesFirst_areMovedAfterReferencedTables",
+			stmts:      []FixtureStatement{row("comments", 1), row("resources", 1), row("users", 1), row("users", 2)},
+			references: [][2]string{{"comments", "resources"}, {"resources", "users"}, {"comments", "users"}},
+			expected:   []FixtureStatement{row("users", 1), row("users", 2), row("resources", 1), row("comments", 1)},
+		},
+		{
+			name:       "IndependentTables_keepTheirOrder",
+			stmts:      []FixtureStatement{row("resources", 1), row("users", 1), row("resources", 2)},
+			references: [][2]string{{"other", "users"}},
+			expected:   []FixtureStatement{row("resources", 1), row("resources", 2), row("users", 1)},
+		},
+		{
+			name:       "TableNames_areComparedWithoutSchemaQuotesAndCase",
+			stmts:      []FixtureStatement{row(`"Resources"`, 1), row("public.users", 1)},
+			references: [][2]string{{"resources", "users"}},
+			expected:   []FixtureStatement{row("public.users", 1), row(`"Resources"`, 1)},
+		},
+		{
+			name:       "SQLStatements_areKeptInPlace",
+			stmts:      []FixtureStatement{row("resources", 1), sql, row("resources", 2), row("users", 1)},
+			references: [][2]string{{"resources", "users"}},
+			expected:   []FixtureStatement{row("resources", 1), sql, row("users", 1), row("resources", 2)},
+		},
+		{
+			name:       "CyclicReferences_ignoreTheReferenceThatClosesTheCycle",
+			stmts:      []FixtureStatement{row("b", 1), row("a", 1), row("a", 2)},
+			references: [][2]string{{"a", "b"}, {"b", "a"}, {"a", "a"}},
+			expected:   []FixtureStatement{row("a", 1), row("a", 2), row("b", 1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			stmts := SortFixtures(tt.stmts, tt.references)
+
+			// ASSERT
+			require.Equal(t, tt.expected, stmts)
+		})
+	}
+}
@@ -0,0 +1,235 @@
+// Package ident validates and quotes SQL identifiers, such as schema, table and column
+// names, so they can be safely concatenated into SQL queries.
+package ident
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// MaxLength is the maximum length of a Postgres identifier (NAMEDATALEN - 1).
+const MaxLength = 63
+
+// Quote returns name as a double-quoted identifier, doubling any double quote it contains.
+// The returned identifier is case sensitive. It returns an error if name is empty, longer
+// than MaxLength or contains a NUL character.
+func Quote(name string) (string, error) {
+	if name == "" || len(name) > MaxLength || strings.IndexByte(name, 0) >= 0 {
+		return "", invalidIdentifierError(name)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(name) + 2)
+	sb.WriteByte('"')
+	for i := 0; i < len(name); i++ {
+		if name[i] == '"' {
+			sb.WriteByte('"')
+		}
+		sb.WriteByte(name[i])
+	}
+	sb.WriteByte('"')
+	return sb.String(), nil
+}
+
+// MustQuote is like Quote but panics if name is not a valid identifier.
+func MustQuote(name string) string {
+	quoted, err := Quote(name)
+	if err != nil {
+		panic(err)
+	}
+	return quoted
+}
+
+// Fold returns name as Postgres stores it when it is not quoted: in lower case if it is a plain
+// identifier, made of letters, digits, '_' and '$' without starting with a digit or '$', or as it
+// is otherwise. Quoting the folded name keeps plain names case insensitive, as they were unquoted.
+func Fold(name string) string {
+	if name == "" || len(name) > MaxLength {
+		return name
+	}
+
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierByte(name[i], i == 0) {
+			return name
+		}
+	}
+	return strings.ToLower(name)
+}
+
+// QuoteQualified is like Quote but quotes every dot-separated part of name, so a qualified
+// name such as public.users becomes "public"."users".
+func QuoteQualified(name string) (string, error) {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		quoted, err := Quote(part)
+		if err != nil {
+			return "", invalidIdentifierError(name)
+		}
+		parts[i] = quoted
+	}
+	return strings.Join(parts, "."), nil
+}
+
+// Validate returns an error unless name is a plain or qualified identifier, such as
+// name, u.user_id or "UserID". Every dot-separated part must be either a double-quoted
+// identifier or be made of letters, digits, '_' and '$' without starting with a digit or '$'.
+func Validate(name string) error {
+	if name == "" {
+		return invalidIdentifierError(name)
+	}
+
+	for i := 0; i < len(name); {
+		n := partLength(name[i:])
+		if n == 0 {
+			return invalidIdentifierError(name)
+		}
+
+		i += n
+		if i == len(name) {
+			return nil
+		}
+
+		// Parts must be separated by a single dot and the name cannot end with one.
+		if name[i] != '.' || i+1 == len(name) {
+			return invalidIdentifierError(name)
+		}
+		i++
+	}
+
+	return nil
+}
+
+// ValidateExpression returns an error unless expr is an identifier (see Validate) or an SQL
+// expression that can be used as an operand of a query, such as lower(name) or
+// first_name || ' ' || last_name. Its parentheses must be balanced and its quotes closed, and it
+// cannot have placeholders, backslashes, statement separators or comments. Expressions are not
+// otherwise checked, so they must be written by developers and never come from user input.
+func ValidateExpression(expr string) error {
+	if Validate(expr) == nil {
+		return nil
+	}
+
+	if strings.TrimSpace(expr) == "" {
+		return invalidExpressionError(expr)
+	}
+
+	depth := 0
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '\'', '"':
+			n := quotedLength(expr[i:], c)
+			if n == 0 {
+				return invalidExpressionError(expr)
+			}
+			i += n - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return invalidExpressionError(expr)
+			}
+		case '-', '/':
+			// -- and /* start comments.
+			if strings.HasPrefix(expr[i:], "--") || strings.HasPrefix(expr[i:], "/*") {
+				return invalidExpressionError(expr)
+			}
+		case '$':
+			// $1 is a placeholder and $$ or $tag$ start dollar-quoted strings, but $ is also an
+			// identifier character.
+			if i == 0 || !isIdentifierByte(expr[i-1], false) {
+				return invalidExpressionError(expr)
+			}
+		case ';', '?', '\\', 0:
+			return invalidExpressionError(expr)
+		}
+	}
+
+	if depth != 0 {
+		return invalidExpressionError(expr)
+	}
+	return nil
+}
+
+// quotedLength returns the length of the string or identifier quoted with quote at the beginning
+// of s, in which quotes are doubled, or 0 if it is not closed or has a forbidden character.
+func quotedLength(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		case '?', '\\', 0:
+			// Placeholders are replaced even inside quotes, and backslashes may escape quotes.
+			return 0
+		}
+	}
+	return 0
+}
+
+// partLength returns the length of the identifier at the beginning of s or 0 if there is none.
+func partLength(s string) int {
+	if s[0] == '"' {
+		return quotedPartLength(s)
+	}
+
+	i := 0
+	for i < len(s) && isIdentifierByte(s[i], i == 0) {
+		i++
+	}
+
+	if i > MaxLength {
+		return 0
+	}
+	return i
+}
+
+func quotedPartLength(s string) int {
+	length := 0
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case 0:
+			return 0
+		case '"':
+			if i+1 < len(s) && s[i+1] == '"' {
+				i++
+				length++
+				continue
+			}
+
+			if length == 0 || length > MaxLength {
+				return 0
+			}
+			return i + 1
+		default:
+			length++
+		}
+	}
+
+	// Missing closing quote.
+	return 0
+}
+
+func isIdentifierByte(c byte, first bool) bool {
+	switch {
+	case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		return true
+	case '0' <= c && c <= '9', c == '$':
+		return !first
+	default:
+		return false
+	}
+}
+
+func invalidIdentifierError(name string) error {
+	return uerr.NewError(uerr.GenericError, fmt.Sprintf("Invalid SQL identifier %q.", name))
+}
+
+func invalidExpressionError(expr string) error {
+	return uerr.NewError(uerr.GenericError, fmt.Sprintf("Invalid SQL expression %q.", expr))
+}
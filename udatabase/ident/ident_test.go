@@ -0,0 +1,228 @@
+package ident
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "PlainIdentifier", input: "my_schema", expected: `"my_schema"`},
+		{name: "MixedCaseIdentifier", input: "MySchema", expected: `"MySchema"`},
+		{name: "IdentifierWithDash", input: "tenant_a-b", expected: `"tenant_a-b"`},
+		{name: "IdentifierWithQuote", input: `a"; DROP SCHEMA public; --`, expected: `"a""; DROP SCHEMA public; --"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			actual, err := Quote(tt.input)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+
+	for _, input := range []string{"", "a\x00b", strings.Repeat("a", MaxLength+1)} {
+		t.Run("InvalidIdentifier_returnsError", func(t *testing.T) {
+			// ACT
+			_, err := Quote(input)
+
+			// ASSERT
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "PlainIdentifier_isLowercased", input: "MySchema_1", expected: "myschema_1"},
+		{name: "IdentifierWithDash_isUnchanged", input: "Tenant_a-B", expected: "Tenant_a-B"},
+		{name: "IdentifierStartingWithDigit_isUnchanged", input: "1Schema", expected: "1Schema"},
+		{name: "IdentifierWithQuote_isUnchanged",
+			input: `A"; DROP SCHEMA public; --`, expected: `A"; DROP SCHEMA public; --`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			actual := Fold(tt.input)
+
+			// ASSERT
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestQuoteQualified(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "PlainIdentifier", input: "users", expected: `"users"`},
+		{name: "QualifiedIdentifier", input: "public.Users", expected: `"public"."Users"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			actual, err := QuoteQualified(tt.input)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+
+	for _, input := range []string{"", "public.", ".users", "a\x00b.users"} {
+		t.Run("InvalidIdentifier_returnsError", func(t *testing.T) {
+			// ACT
+			_, err := QuoteQualified(input)
+
+			// ASSERT
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []string{
+		"name",
+		"random_number",
+		"_private",
+		"col$1",
+		"u.user_id",
+		"public.users.id",
+		`"UserID"`,
+		`u."User ""quoted"" ID"`,
+	}
+	for _, input := range valid {
+		t.Run("ValidIdentifier_returnsNoError", func(t *testing.T) {
+			require.NoError(t, Validate(input), input)
+		})
+	}
+
+	invalid := []string{
+		"",
+		"1name",
+		"$name",
+		"name;",
+		"name = 1 OR 1=1",
+		"name--",
+		"lower(name)",
+		"u.",
+		".id",
+		"u..id",
+		`"unclosed`,
+		`""`,
+		`"a"b`,
+		"name\x00",
+		strings.Repeat("a", MaxLength+1),
+	}
+	for _, input := range invalid {
+		t.Run("InvalidIdentifier_returnsError", func(t *testing.T) {
+			require.Error(t, Validate(input), input)
+		})
+	}
+}
+
+func TestValidateExpression(t *testing.T) {
+	valid := []string{
+		"name",
+		`u."User ID"`,
+		"lower(name)",
+		"coalesce(u.nickname, u.name)",
+		"first_name || ' ' || last_name",
+		"(price - discount) * quantity",
+		"created_at::date",
+		`date_trunc('day', "CreatedAt")`,
+		"'it''s'",
+		"col$1 + 1",
+	}
+	for _, input := range valid {
+		t.Run("ValidExpression_returnsNoError", func(t *testing.T) {
+			require.NoError(t, ValidateExpression(input), input)
+		})
+	}
+
+	invalid := []string{
+		"",
+		" ",
+		"name) OR (1=1",
+		"lower(name",
+		"name; DROP TABLE resources",
+		"name -- comment",
+		"name /* comment */",
+		"name = ?",
+		"'?'",
+		"name = $1",
+		"$$text$$",
+		"'unclosed",
+		`"unclosed`,
+		`E'\'' OR true`,
+		"name\x00",
+	}
+	for _, input := range invalid {
+		t.Run("InvalidExpression_returnsError", func(t *testing.T) {
+			require.Error(t, ValidateExpression(input), input)
+		})
+	}
+}
+
+func FuzzQuote(f *testing.F) {
+	for _, seed := range []string{"name", "MySchema", `a"b`, `"`, "a b;--", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, name string) {
+		quoted, err := Quote(name)
+		if err != nil {
+			return
+		}
+
+		// The quoted identifier is a single valid identifier that unquotes back to name.
+		require.NoError(t, Validate(quoted))
+		inner := quoted[1 : len(quoted)-1]
+		require.NotContains(t, strings.ReplaceAll(inner, `""`, ""), `"`)
+		require.Equal(t, name, strings.ReplaceAll(inner, `""`, `"`))
+	})
+}
+
+func FuzzValidate(f *testing.F) {
+	for _, seed := range []string{"name", "u.user_id", `"User ID"`, `a"; --`, "lower(name)", "a.b.c"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, name string) {
+		if Validate(name) != nil {
+			return
+		}
+
+		// Outside of double quotes, a valid identifier only contains identifier characters and dots.
+		inQuotes := false
+		for i := 0; i < len(name); i++ {
+			c := name[i]
+			switch {
+			case c == '"':
+				inQuotes = !inQuotes
+			case inQuotes:
+				require.NotEqual(t, byte(0), c)
+			default:
+				require.True(t, c == '.' || isIdentifierByte(c, false), "unexpected %q in %q", c, name)
+			}
+		}
+		require.False(t, inQuotes, "unclosed quote in %q", name)
+	})
+}
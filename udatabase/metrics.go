@@ -0,0 +1,256 @@
+package udatabase
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carlosarismendi/utils/uerr"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of the operation histograms.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// OutcomeOK is the outcome label of the operations that did not return an error.
+const OutcomeOK = "ok"
+
+// Metrics collects the duration of repository operations and the stats of connection pools,
+// and exports them in the Prometheus text format. DBHolders register their pools in the
+// Metrics of DBConfig.Metrics, and their repositories observe their operations in it.
+// A nil *Metrics ignores every call.
+type Metrics struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	operations map[operationLabels]*histogram
+	pools      map[PoolLabels]func() sql.DBStats
+}
+
+// NewMetrics returns a *Metrics whose histograms use buckets, or DefaultBuckets if it is empty.
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Metrics{
+		buckets:    buckets,
+		operations: make(map[operationLabels]*histogram),
+		pools:      make(map[PoolLabels]func() sql.DBStats),
+	}
+}
+
+// PoolLabels identify a connection pool.
+type PoolLabels struct {
+	Host   string
+	Schema string
+}
+
+// PoolLabels returns the labels of the connection pool of the database described by the config.
+func (c *DBConfig) PoolLabels() PoolLabels {
+	return PoolLabels{Host: c.Host, Schema: c.SchemaName}
+}
+
+type operationLabels struct {
+	operation  string
+	repository string
+	outcome    string
+}
+
+type histogram struct {
+	counts []uint64 // counts[i] is the number of observations in (buckets[i-1], buckets[i]].
+	sum    float64
+	count  uint64
+}
+
+// RepositoryName returns the name of the repositories of T in the metrics, e.g. users.User
+// for both User and *User.
+func RepositoryName[T any]() string {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.String()
+}
+
+// RegisterPool adds the pool identified by labels, whose stats are returned by stats, replacing
+// the pool previously registered with the same labels.
+func (m *Metrics) RegisterPool(labels PoolLabels, stats func() sql.DBStats) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pools[labels] = stats
+}
+
+// ObserveOperation records that operation of repository started at start and returned err.
+// The outcome of the operation is OutcomeOK when err is nil, otherwise its uerr key.
+func (m *Metrics) ObserveOperation(operation, repository string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	outcome := OutcomeOK
+	if err != nil {
+		outcome = uerr.GetKey(err)
+		if outcome == "" {
+			outcome = uerr.GenericError
+		}
+	}
+
+	seconds := time.Since(start).Seconds()
+	labels := operationLabels{operation: operation, repository: repository, outcome: outcome}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.operations[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets)+1)}
+		m.operations[labels] = h
+	}
+	h.counts[sort.SearchFloat64s(m.buckets, seconds)]++
+	h.sum += seconds
+	h.count++
+}
+
+// Handler returns an http.Handler that writes the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		bw := bufio.NewWriter(w)
+		m.write(bw)
+		_ = bw.Flush()
+	})
+}
+
+type poolMetric struct {
+	name  string
+	kind  string
+	help  string
+	value func(s sql.DBStats) float64
+}
+
+var poolMetrics = []poolMetric{
+	{"udatabase_pool_max_open_connections", "gauge", "Maximum number of open connections to the database.",
+		func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }},
+	{"udatabase_pool_open_connections", "gauge", "Number of established connections, in use and idle.",
+		func(s sql.DBStats) float64 { return float64(s.OpenConnections) }},
+	{"udatabase_pool_in_use_connections", "gauge", "Number of connections currently in use.",
+		func(s sql.DBStats) float64 { return float64(s.InUse) }},
+	{"udatabase_pool_idle_connections", "gauge", "Number of idle connections.",
+		func(s sql.DBStats) float64 { return float64(s.Idle) }},
+	{"udatabase_pool_wait_count_total", "counter", "Total number of connections waited for.",
+		func(s sql.DBStats) float64 { return float64(s.WaitCount) }},
+	{"udatabase_pool_wait_duration_seconds_total", "counter", "Total time blocked waiting for a new connection.",
+		func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }},
+	{"udatabase_pool_max_idle_closed_total", "counter", "Total number of connections closed due to SetMaxIdleConns.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }},
+	{"udatabase_pool_max_idle_time_closed_total", "counter",
+		"Total number of connections closed due to SetConnMaxIdleTime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxIdleTimeClosed) }},
+	{"udatabase_pool_max_lifetime_closed_total", "counter",
+		"Total number of connections closed due to SetConnMaxLifetime.",
+		func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }},
+}
+
+const operationMetric = "udatabase_operation_duration_seconds"
+
+func (m *Metrics) write(w *bufio.Writer) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pools := make([]PoolLabels, 0, len(m.pools))
+	for labels := range m.pools {
+		pools = append(pools, labels)
+	}
+	sort.Slice(pools, func(i, j int) bool {
+		if pools[i].Host != pools[j].Host {
+			return pools[i].Host < pools[j].Host
+		}
+		return pools[i].Schema < pools[j].Schema
+	})
+
+	stats := make([]sql.DBStats, len(pools))
+	for i, labels := range pools {
+		stats[i] = m.pools[labels]()
+	}
+
+	if len(pools) > 0 {
+		for _, metric := range poolMetrics {
+			writeHeader(w, metric.name, metric.kind, metric.help)
+			for i, labels := range pools {
+				writeSample(w, metric.name, metric.value(stats[i]), "host", labels.Host, "schema", labels.Schema)
+			}
+		}
+	}
+
+	operations := make([]operationLabels, 0, len(m.operations))
+	for labels := range m.operations {
+		operations = append(operations, labels)
+	}
+	sort.Slice(operations, func(i, j int) bool {
+		a, b := operations[i], operations[j]
+		if a.operation != b.operation {
+			return a.operation < b.operation
+		}
+		if a.repository != b.repository {
+			return a.repository < b.repository
+		}
+		return a.outcome < b.outcome
+	})
+
+	if len(operations) > 0 {
+		writeHeader(w, operationMetric, "histogram", "Duration of the repository operations.")
+	}
+	for _, labels := range operations {
+		h := m.operations[labels]
+		pairs := []string{"operation", labels.operation, "repository", labels.repository, "outcome", labels.outcome}
+
+		var cumulative uint64
+		for i, upperBound := range m.buckets {
+			cumulative += h.counts[i]
+			le := strconv.FormatFloat(upperBound, 'g', -1, 64)
+			writeSample(w, operationMetric+"_bucket", float64(cumulative), append(pairs, "le", le)...)
+		}
+		writeSample(w, operationMetric+"_bucket", float64(h.count), append(pairs, "le", "+Inf")...)
+		writeSample(w, operationMetric+"_sum", h.sum, pairs...)
+		writeSample(w, operationMetric+"_count", float64(h.count), pairs...)
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, kind, help string) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// writeSample writes a sample of name with the labels of pairs, which alternates label names and values.
+func writeSample(w *bufio.Writer, name string, value float64, pairs ...string) {
+	_, _ = w.WriteString(name)
+	if len(pairs) > 0 {
+		_ = w.WriteByte('{')
+		for i := 0; i < len(pairs); i += 2 {
+			if i > 0 {
+				_ = w.WriteByte(',')
+			}
+			_, _ = fmt.Fprintf(w, "%s=\"%s\"", pairs[i], labelValueReplacer.Replace(pairs[i+1]))
+		}
+		_ = w.WriteByte('}')
+	}
+	_, _ = fmt.Fprintf(w, " %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
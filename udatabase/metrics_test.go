@@ -0,0 +1,101 @@
+package udatabase
+
+import (
+	"database/sql"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+type metricsResource struct{}
+
+func TestRepositoryName(t *testing.T) {
+	require.Equal(t, "udatabase.metricsResource", RepositoryName[metricsResource]())
+	require.Equal(t, "udatabase.metricsResource", RepositoryName[*metricsResource]())
+}
+
+func TestMetrics(t *testing.T) {
+	scrape := func(t *testing.T, m *Metrics) string {
+		rec := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+		body, err := io.ReadAll(rec.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	t.Run("ObservedOperations_areExportedAsHistograms", func(t *testing.T) {
+		// ARRANGE
+		m := NewMetrics(0.1, 1)
+
+		// ACT
+		m.ObserveOperation("Find", "users.User", time.Now(), nil)
+		m.ObserveOperation("Find", "users.User", time.Now().Add(-500*time.Millisecond), nil)
+		m.ObserveOperation("Create", "users.User", time.Now(),
+			uerr.NewError(uerr.ResourceAlreadyExistsError, "Resource already exists."))
+
+		// ASSERT
+		body := scrape(t, m)
+		require.Contains(t, body, "# TYPE udatabase_operation_duration_seconds histogram\n")
+		require.Contains(t, body,
+			`udatabase_operation_duration_seconds_bucket{operation="Create",repository="users.User",`+
+				`outcome="ResourceAlreadyExistsError",le="0.1"} 1`+"\n")
+		require.Contains(t, body,
+			`udatabase_operation_duration_seconds_bucket{operation="Find",repository="users.User",outcome="ok",le="0.1"} 1`+
+				"\n")
+		require.Contains(t, body,
+			`udatabase_operation_duration_seconds_bucket{operation="Find",repository="users.User",outcome="ok",le="1"} 2`+
+				"\n")
+		require.Contains(t, body,
+			`udatabase_operation_duration_seconds_bucket{operation="Find",repository="users.User",outcome="ok",le="+Inf"} 2`+
+				"\n")
+		require.Contains(t, body,
+			`udatabase_operation_duration_seconds_count{operation="Find",repository="users.User",outcome="ok"} 2`+"\n")
+	})
+
+	t.Run("RegisteredPools_areExportedAsGauges", func(t *testing.T) {
+		// ARRANGE
+		m := NewMetrics()
+
+		// ACT
+		m.RegisterPool(PoolLabels{Host: "localhost", Schema: "public"}, func() sql.DBStats {
+			return sql.DBStats{MaxOpenConnections: 10, InUse: 3, WaitDuration: 1500 * time.Millisecond}
+		})
+
+		// ASSERT
+		body := scrape(t, m)
+		require.Contains(t, body, "# TYPE udatabase_pool_in_use_connections gauge\n")
+		require.Contains(t, body, `udatabase_pool_max_open_connections{host="localhost",schema="public"} 10`+"\n")
+		require.Contains(t, body, `udatabase_pool_in_use_connections{host="localhost",schema="public"} 3`+"\n")
+		require.Contains(t, body, `udatabase_pool_wait_duration_seconds_total{host="localhost",schema="public"} 1.5`+"\n")
+	})
+
+	t.Run("LabelValues_areEscaped", func(t *testing.T) {
+		// ARRANGE
+		m := NewMetrics()
+
+		// ACT
+		m.RegisterPool(PoolLabels{Host: "localhost", Schema: "my \"schema\"\n"}, func() sql.DBStats {
+			return sql.DBStats{}
+		})
+
+		// ASSERT
+		require.Contains(t, scrape(t, m), `udatabase_pool_idle_connections{host="localhost",schema="my \"schema\"\n"} 0`)
+	})
+
+	t.Run("NilMetrics_ignoresCalls", func(t *testing.T) {
+		// ARRANGE
+		var m *Metrics
+
+		// ACT
+		m.ObserveOperation("Find", "users.User", time.Now(), nil)
+		m.RegisterPool(PoolLabels{}, func() sql.DBStats { return sql.DBStats{} })
+
+		// ASSERT
+		require.Empty(t, scrape(t, m))
+	})
+}
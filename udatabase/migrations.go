@@ -1,28 +1,42 @@
 package udatabase
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
-	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
 
 	// nolint:blank-imports // it is necessary to run the SQL migrations.
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
-// RunMigrations runs SQL migrations found in the folder specified by DBConfig.MigrationsDir
+// MigrationVersionFormat is the time layout used as version prefix by CreateMigration.
+const MigrationVersionFormat = "20060102150405"
+
+// RunMigrations runs SQL migrations found in the folder specified by DBConfig.MigrationsDir.
+// If DBConfig.MigrationsLock is enabled, migrations run while holding an advisory lock derived
+// from DBConfig.SchemaName, so concurrent instances wait up to DBConfig.MigrationsLockTimeout
+// for the one migrating to finish.
 func RunMigrations(db *sql.DB, cfg *DBConfig) error {
-	config := migratePostgres.Config{
-		SchemaName: cfg.SchemaName,
-	}
-	driver, err := migratePostgres.WithInstance(db, &config)
-	if err != nil {
-		return err
+	if !cfg.MigrationsLock {
+		return runMigrations(db, cfg)
 	}
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", cfg.MigrationsDir),
-		"postgres", driver)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.MigrationsLockTimeout)
+	defer cancel()
+
+	return WithAdvisoryLock(ctx, db, MigrationsLockKey(cfg.SchemaName), func(context.Context) error {
+		return runMigrations(db, cfg)
+	})
+}
+
+func runMigrations(db *sql.DB, cfg *DBConfig) error {
+	m, err := NewMigrate(db, cfg)
 	if err != nil {
 		return err
 	}
@@ -34,3 +48,66 @@ func RunMigrations(db *sql.DB, cfg *DBConfig) error {
 
 	return nil
 }
+
+// NewMigrate returns a *migrate.Migrate that reads the SQL migrations found in the folder
+// specified by DBConfig.MigrationsDir and applies them to the schema DBConfig.SchemaName
+// using the migration driver of DBConfig.Dialect.
+// More on golang-migrate here: https://github.com/golang-migrate/migrate
+func NewMigrate(db *sql.DB, cfg *DBConfig) (*migrate.Migrate, error) {
+	dialect := cfg.GetDialect()
+	driver, err := dialect.MigrationDriver(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithDatabaseInstance(
+		fmt.Sprintf("file://%s", cfg.MigrationsDir),
+		dialect.DriverName(), driver)
+}
+
+// CreateMigration creates an empty pair of up/down SQL migration files in dir.
+// The files are prefixed with t formatted as MigrationVersionFormat, e.g.
+// 20240102150405_create_users.up.sql and 20240102150405_create_users.down.sql.
+// The name cannot contain path separators or "..", and no file is left behind
+// if the pair cannot be created.
+func CreateMigration(dir, name string, t time.Time) (upFile, downFile string, err error) {
+	if name == "" {
+		return "", "", fmt.Errorf("migration name cannot be empty")
+	}
+
+	if strings.ContainsAny(name, `/\`) || strings.Contains(name, "..") {
+		return "", "", fmt.Errorf("migration name %q cannot contain path separators or \"..\"", name)
+	}
+
+	err = os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", "", err
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%s_%s", t.UTC().Format(MigrationVersionFormat), name))
+	upFile = base + ".up.sql"
+	downFile = base + ".down.sql"
+
+	var created []string
+	defer func() {
+		if err != nil {
+			for _, file := range created {
+				_ = os.Remove(file)
+			}
+		}
+	}()
+
+	for _, file := range []string{upFile, downFile} {
+		f, err := os.OpenFile(file, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", err
+		}
+		created = append(created, file)
+
+		if err = f.Close(); err != nil {
+			return "", "", err
+		}
+	}
+
+	return upFile, downFile, nil
+}
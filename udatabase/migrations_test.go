@@ -0,0 +1,80 @@
+package udatabase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMigration(t *testing.T) {
+	t.Run("CreatingMigration_createsTimestampedUpAndDownFiles", func(t *testing.T) {
+		// ARRANGE
+		dir := filepath.Join(t.TempDir(), "migrations")
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+		// ACT
+		up, down, err := CreateMigration(dir, "create_users", now)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, "20240102150405_create_users.up.sql"), up)
+		require.Equal(t, filepath.Join(dir, "20240102150405_create_users.down.sql"), down)
+		require.FileExists(t, up)
+		require.FileExists(t, down)
+	})
+
+	t.Run("CreatingExistingMigration_returnsError", func(t *testing.T) {
+		// ARRANGE
+		dir := t.TempDir()
+		now := time.Now()
+		_, _, err := CreateMigration(dir, "create_users", now)
+		require.NoError(t, err)
+
+		// ACT
+		_, _, err = CreateMigration(dir, "create_users", now)
+
+		// ASSERT
+		require.ErrorIs(t, err, os.ErrExist)
+	})
+
+	t.Run("CreatingMigrationWithExistingDownFile_removesUpFile", func(t *testing.T) {
+		// ARRANGE
+		dir := t.TempDir()
+		now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		down := filepath.Join(dir, "20240102150405_create_users.down.sql")
+		require.NoError(t, os.WriteFile(down, nil, 0o600))
+
+		// ACT
+		_, _, err := CreateMigration(dir, "create_users", now)
+
+		// ASSERT
+		require.ErrorIs(t, err, os.ErrExist)
+		require.NoFileExists(t, filepath.Join(dir, "20240102150405_create_users.up.sql"))
+		require.FileExists(t, down)
+	})
+
+	t.Run("CreatingMigrationWithPathInName_returnsError", func(t *testing.T) {
+		for _, name := range []string{"../create_users", "users/create", `users\create`, "create..users"} {
+			// ARRANGE
+			dir := filepath.Join(t.TempDir(), "migrations")
+
+			// ACT
+			_, _, err := CreateMigration(dir, name, time.Now())
+
+			// ASSERT
+			require.Error(t, err, name)
+			require.NoDirExists(t, dir)
+		}
+	})
+
+	t.Run("CreatingMigrationWithoutName_returnsError", func(t *testing.T) {
+		// ACT
+		_, _, err := CreateMigration(t.TempDir(), "", time.Now())
+
+		// ASSERT
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,223 @@
+package udatabase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// OpenDB opens the database described by the config with the driver of its Dialect. When
+// GetQueryObserver returns an observer, the connections of the returned *sql.DB notify it of
+// every query they run.
+func (c *DBConfig) OpenDB() (*sql.DB, error) {
+	dialect := c.GetDialect()
+	db, err := sql.Open(dialect.DriverName(), c.GetConnectionString())
+	if err != nil {
+		return nil, err
+	}
+
+	observer := c.GetQueryObserver()
+	if observer == nil {
+		return db, nil
+	}
+
+	// db is only used to get the driver, it has not opened any connection yet.
+	var connector driver.Connector = dsnConnector{dsn: c.GetConnectionString(), driver: db.Driver()}
+	if dc, ok := db.Driver().(driver.DriverContext); ok {
+		connector, err = dc.OpenConnector(c.GetConnectionString())
+		if err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
+	_ = db.Close()
+
+	return sql.OpenDB(&observedConnector{Connector: connector, observer: observer}), nil
+}
+
+// observe runs fn notifying observer of query.
+func observe[R any](ctx context.Context, observer QueryObserver, query string, args []driver.NamedValue,
+	fn func(ctx context.Context) (R, error),
+) (R, error) {
+	ctx = observer.BeforeQuery(ctx)
+	start := time.Now()
+	res, err := fn(ctx)
+	if errors.Is(err, driver.ErrSkip) {
+		// database/sql retries the query in another way, e.g. preparing it, which is observed then.
+		return res, err
+	}
+
+	values := make([]any, len(args))
+	for i := range args {
+		values[i] = args[i].Value
+	}
+	observer.AfterQuery(ctx, QueryEvent{Query: query, Args: values, Duration: time.Since(start), Err: err})
+	return res, err
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type observedConnector struct {
+	driver.Connector
+	observer QueryObserver
+}
+
+func (c *observedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &observedConn{Conn: conn, observer: c.observer}, nil
+}
+
+// observedConn notifies its observer of the queries run by the wrapped connection. It implements
+// every optional interface of driver.Conn, falling back to the behavior database/sql has when the
+// wrapped connection does not implement them.
+type observedConn struct {
+	driver.Conn
+	observer QueryObserver
+}
+
+func (c *observedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *observedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &observedStmt{Stmt: stmt, conn: c.Conn, query: query, observer: c.observer}, nil
+}
+
+func (c *observedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return observe(ctx, c.observer, query, args, func(ctx context.Context) (driver.Result, error) {
+		return execer.ExecContext(ctx, query, args)
+	})
+}
+
+func (c *observedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return observe(ctx, c.observer, query, args, func(ctx context.Context) (driver.Rows, error) {
+		return queryer.QueryContext(ctx, query, args)
+	})
+}
+
+func (c *observedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, errors.New("udatabase: driver does not support non-default transaction options")
+	}
+	return c.Conn.Begin() //nolint:staticcheck // only used by drivers without ConnBeginTx.
+}
+
+func (c *observedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *observedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *observedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *observedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type observedStmt struct {
+	driver.Stmt
+	conn     driver.Conn
+	query    string
+	observer QueryObserver
+}
+
+func (s *observedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return observe(ctx, s.observer, s.query, args, func(ctx context.Context) (driver.Result, error) {
+		if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+			return execer.ExecContext(ctx, args)
+		}
+		values, err := namedValuesToValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.Stmt.Exec(values) //nolint:staticcheck // only used by drivers without StmtExecContext.
+	})
+}
+
+func (s *observedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return observe(ctx, s.observer, s.query, args, func(ctx context.Context) (driver.Rows, error) {
+		if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+			return queryer.QueryContext(ctx, args)
+		}
+		values, err := namedValuesToValues(args)
+		if err != nil {
+			return nil, err
+		}
+		return s.Stmt.Query(values) //nolint:staticcheck // only used by drivers without StmtQueryContext.
+	})
+}
+
+func (s *observedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	// database/sql does not check with the connection when the statement is a NamedValueChecker.
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	if checker, ok := s.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("udatabase: driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,142 @@
+package udatabase
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryEvent describes a query run by a DBHolder.
+type QueryEvent struct {
+	Query    string
+	Args     []any
+	Duration time.Duration
+	Err      error
+}
+
+// QueryObserver is notified of the queries run by the DBHolders configured with it,
+// see DBConfig.QueryObserver.
+type QueryObserver interface {
+	// BeforeQuery is called before running a query. The returned ctx is passed to AfterQuery,
+	// so it can carry values such as spans.
+	BeforeQuery(ctx context.Context) context.Context
+
+	// AfterQuery is called after running a query, with the ctx returned by BeforeQuery.
+	AfterQuery(ctx context.Context, event QueryEvent)
+}
+
+// ChainObservers returns a QueryObserver that notifies every one of observers, in order.
+func ChainObservers(observers ...QueryObserver) QueryObserver {
+	return chainObserver(observers)
+}
+
+type chainObserver []QueryObserver
+
+func (c chainObserver) BeforeQuery(ctx context.Context) context.Context {
+	for _, o := range c {
+		ctx = o.BeforeQuery(ctx)
+	}
+	return ctx
+}
+
+func (c chainObserver) AfterQuery(ctx context.Context, event QueryEvent) {
+	for _, o := range c {
+		o.AfterQuery(ctx, event)
+	}
+}
+
+// redacted replaces the arguments of queries in logs, unless SlogObserver.LogArgs is set.
+const redacted = "[REDACTED]"
+
+// SlogObserver is a QueryObserver that logs queries with log/slog.
+type SlogObserver struct {
+	// Logger is the logger to write to. When nil, slog.Default() is used.
+	Logger *slog.Logger
+
+	// SlowThreshold makes the queries that take longer than it to be logged at warn level.
+	// 0 disables it.
+	SlowThreshold time.Duration
+
+	// LogAll makes every query to be logged at debug level, along with its error if it failed.
+	LogAll bool
+
+	// LogArgs makes the arguments of the queries to be logged. Otherwise, they are redacted,
+	// since they may contain personal data or secrets.
+	LogArgs bool
+}
+
+func (o *SlogObserver) BeforeQuery(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (o *SlogObserver) AfterQuery(ctx context.Context, event QueryEvent) {
+	level, msg := slog.LevelDebug, "Query."
+	if o.SlowThreshold > 0 && event.Duration > o.SlowThreshold {
+		level, msg = slog.LevelWarn, "Slow query."
+	} else if !o.LogAll {
+		return
+	}
+
+	logger := o.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	args := event.Args
+	if !o.LogArgs {
+		args = make([]any, len(event.Args))
+		for i := range args {
+			args[i] = redacted
+		}
+	}
+
+	attrs := []slog.Attr{
+		slog.String("query", event.Query),
+		slog.Any("args", args),
+		slog.Duration("duration", event.Duration),
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.Any("error", event.Err))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// TracingObserver is a QueryObserver that creates an OpenTelemetry span for every query.
+// Spans are named after the SQL operation and have the query, without arguments, as the
+// db.statement attribute.
+type TracingObserver struct {
+	// Tracer creates the spans. When nil, the tracer of the global TracerProvider is used.
+	Tracer trace.Tracer
+}
+
+func (o *TracingObserver) BeforeQuery(ctx context.Context) context.Context {
+	tracer := o.Tracer
+	if tracer == nil {
+		tracer = otel.Tracer("github.com/carlosarismendi/utils/udatabase")
+	}
+
+	ctx, _ = tracer.Start(ctx, "query", trace.WithSpanKind(trace.SpanKindClient))
+	return ctx
+}
+
+func (o *TracingObserver) AfterQuery(ctx context.Context, event QueryEvent) {
+	span := trace.SpanFromContext(ctx)
+	if words := strings.Fields(event.Query); len(words) > 0 {
+		span.SetName(strings.ToUpper(words[0]))
+	}
+	span.SetAttributes(attribute.String("db.statement", event.Query))
+	if event.Err != nil {
+		span.RecordError(event.Err)
+		span.SetStatus(codes.Error, event.Err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,99 @@
+package udatabase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogObserver(t *testing.T) {
+	query := "SELECT * FROM users WHERE email = $1"
+	args := []any{"user@example.com"}
+
+	newObserver := func(o SlogObserver) (*SlogObserver, *bytes.Buffer) {
+		buf := &bytes.Buffer{}
+		o.Logger = slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		return &o, buf
+	}
+
+	decode := func(t *testing.T, buf *bytes.Buffer) map[string]any {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		return record
+	}
+
+	t.Run("SlowQuery_isLoggedWithRedactedArgs", func(t *testing.T) {
+		// ARRANGE
+		o, buf := newObserver(SlogObserver{SlowThreshold: time.Second})
+
+		// ACT
+		o.AfterQuery(context.Background(), QueryEvent{Query: query, Args: args, Duration: 2 * time.Second})
+
+		// ASSERT
+		record := decode(t, buf)
+		require.Equal(t, "WARN", record["level"])
+		require.Equal(t, "Slow query.", record["msg"])
+		require.Equal(t, query, record["query"])
+		require.Equal(t, []any{redacted}, record["args"])
+	})
+
+	t.Run("FastQuery_isNotLogged", func(t *testing.T) {
+		// ARRANGE
+		o, buf := newObserver(SlogObserver{SlowThreshold: time.Second})
+
+		// ACT
+		o.AfterQuery(context.Background(), QueryEvent{Query: query, Args: args, Duration: time.Millisecond})
+
+		// ASSERT
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("LogAllWithArgs_logsQueryArgsAndError", func(t *testing.T) {
+		// ARRANGE
+		o, buf := newObserver(SlogObserver{LogAll: true, LogArgs: true})
+
+		// ACT
+		o.AfterQuery(context.Background(), QueryEvent{Query: query, Args: args, Err: errors.New("failed")})
+
+		// ASSERT
+		record := decode(t, buf)
+		require.Equal(t, "DEBUG", record["level"])
+		require.Equal(t, []any{"user@example.com"}, record["args"])
+		require.Equal(t, "failed", record["error"])
+	})
+}
+
+func TestGetQueryObserver(t *testing.T) {
+	t.Run("NoLogsNorObserver_returnsNil", func(t *testing.T) {
+		// ACT
+		actual := (&DBConfig{}).GetQueryObserver()
+
+		// ASSERT
+		require.Nil(t, actual)
+	})
+
+	t.Run("SlowQueryThreshold_returnsSlogObserver", func(t *testing.T) {
+		// ACT
+		actual := (&DBConfig{SlowQueryThreshold: time.Second, LogQueryArgs: true}).GetQueryObserver()
+
+		// ASSERT
+		require.Equal(t, &SlogObserver{SlowThreshold: time.Second, LogArgs: true}, actual)
+	})
+
+	t.Run("LogsAndObserver_returnsBothObservers", func(t *testing.T) {
+		// ARRANGE
+		tracing := &TracingObserver{}
+
+		// ACT
+		actual := (&DBConfig{LogQueries: true, QueryObserver: tracing}).GetQueryObserver()
+
+		// ASSERT
+		require.Equal(t, ChainObservers(&SlogObserver{LogAll: true}, tracing), actual)
+	})
+}
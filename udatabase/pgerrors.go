@@ -1,8 +1,155 @@
 package udatabase
 
-import "github.com/carlosarismendi/utils/uerr"
+import (
+	"errors"
 
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// PqErrors maps Postgres error condition names (see https://www.postgresql.org/docs/current/errcodes-appendix.html)
+// to the UError returned by MapPgError. Its entries can be modified to change the key or message
+// of a condition.
 var PqErrors = map[string]*uerr.UError{
-	"unique_violation":   uerr.NewError(uerr.ResourceAlreadyExistsError, "Resource already exists."),
-	"not_null_violation": uerr.NewError(uerr.WrongInputParameterError, "Missing required value."),
+	// Class 23 - Integrity Constraint Violation
+	"unique_violation":      uerr.NewError(uerr.ResourceAlreadyExistsError, "Resource already exists."),
+	"not_null_violation":    uerr.NewError(uerr.WrongInputParameterError, "Missing required value."),
+	"foreign_key_violation": uerr.NewError(uerr.WrongInputParameterError, "Invalid reference to another resource."),
+	"check_violation":       uerr.NewError(uerr.WrongInputParameterError, "Invalid value."),
+	"exclusion_violation":   uerr.NewError(uerr.ConflictError, "Resource conflicts with an existing one."),
+
+	// Class 22 - Data Exception
+	"string_data_right_truncation": uerr.NewError(uerr.WrongInputParameterError, "Value too long."),
+	"invalid_text_representation":  uerr.NewError(uerr.WrongInputParameterError, "Invalid value format."),
+	"numeric_value_out_of_range":   uerr.NewError(uerr.WrongInputParameterError, "Numeric value out of range."),
+	"invalid_datetime_format":      uerr.NewError(uerr.WrongInputParameterError, "Invalid date format."),
+	"datetime_field_overflow":      uerr.NewError(uerr.WrongInputParameterError, "Date out of range."),
+
+	// Class 40 - Transaction Rollback
+	"serialization_failure": uerr.NewError(uerr.ConflictError, "Concurrent update detected. Try again."),
+	"deadlock_detected":     uerr.NewError(uerr.ConflictError, "Deadlock detected. Try again."),
+
+	// Class 55 - Object Not In Prerequisite State
+	"lock_not_available": uerr.NewError(uerr.ConflictError, "Resource is locked. Try again."),
+
+	// Class 57 - Operator Intervention
+	"query_canceled": uerr.NewError(uerr.TimeoutError, "Query canceled."),
+}
+
+// PqErrorClasses maps Postgres error class names to the UError returned by MapPgError
+// for the conditions of the class that are not in PqErrors.
+var PqErrorClasses = map[string]*uerr.UError{
+	"data_exception":                 uerr.NewError(uerr.WrongInputParameterError, "Invalid value."),
+	"integrity_constraint_violation": uerr.NewError(uerr.WrongInputParameterError, "Invalid value."),
+	"transaction_rollback":           uerr.NewError(uerr.ConflictError, "Transaction rolled back. Try again."),
+}
+
+// ConstraintErrors maps constraint names to the UError returned by MapPgError when they are
+// violated, taking precedence over PqErrors. It allows returning specific messages such as
+// "Email already in use." for the constraint "users_email_key".
+var ConstraintErrors = map[string]*uerr.UError{}
+
+// Keys of the metadata added by MapPgError to the returned errors.
+const (
+	MetadataSQLState   = "sqlstate"
+	MetadataSchema     = "schema"
+	MetadataTable      = "table"
+	MetadataColumn     = "column"
+	MetadataConstraint = "constraint"
+)
+
+// PgError contains the fields of a Postgres error returned by either lib/pq or pgx.
+type PgError struct {
+	Code       string
+	Message    string
+	Schema     string
+	Table      string
+	Column     string
+	Constraint string
+}
+
+// AsPgError returns the Postgres error found in the chain of err, which can be
+// a *pq.Error or a *pgconn.PgError.
+func AsPgError(err error) (*PgError, bool) {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return &PgError{
+			Code:       string(pqErr.Code),
+			Message:    pqErr.Message,
+			Schema:     pqErr.Schema,
+			Table:      pqErr.Table,
+			Column:     pqErr.Column,
+			Constraint: pqErr.Constraint,
+		}, true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return &PgError{
+			Code:       pgErr.Code,
+			Message:    pgErr.Message,
+			Schema:     pgErr.SchemaName,
+			Table:      pgErr.TableName,
+			Column:     pgErr.ColumnName,
+			Constraint: pgErr.ConstraintName,
+		}, true
+	}
+
+	return nil, false
+}
+
+// MapPgError returns an UError for the Postgres error found in err, or nil if there is none
+// or its condition is not mapped. The error is looked up in ConstraintErrors, PqErrors and
+// PqErrorClasses, in that order. The returned error has err as cause and the SQLSTATE code,
+// schema, table, column and constraint of the Postgres error as metadata.
+func MapPgError(err error) *uerr.UError {
+	pgErr, ok := AsPgError(err)
+	if !ok {
+		return nil
+	}
+
+	code := pq.ErrorCode(pgErr.Code)
+	tmpl, ok := ConstraintErrors[pgErr.Constraint]
+	if !ok {
+		tmpl, ok = PqErrors[code.Name()]
+	}
+	if !ok && len(code) == 5 {
+		tmpl, ok = PqErrorClasses[code.Class().Name()]
+	}
+	if !ok {
+		return nil
+	}
+
+	rErr := fromTemplate(tmpl, err).WithMetadata(MetadataSQLState, pgErr.Code)
+
+	metadata := [][2]string{
+		{MetadataSchema, pgErr.Schema},
+		{MetadataTable, pgErr.Table},
+		{MetadataColumn, pgErr.Column},
+		{MetadataConstraint, pgErr.Constraint},
+	}
+	for _, m := range metadata {
+		if m[1] != "" {
+			rErr.WithMetadata(m[0], m[1])
+		}
+	}
+
+	return rErr
+}
+
+// ConditionError returns an UError for the Postgres condition name (e.g. "unique_violation")
+// built from PqErrors with err as cause, or nil if the condition is not mapped. It allows
+// other dialects to map their errors to the same keys and messages as Postgres.
+func ConditionError(condition string, err error) *uerr.UError {
+	tmpl, ok := PqErrors[condition]
+	if !ok {
+		return nil
+	}
+	return fromTemplate(tmpl, err)
+}
+
+// fromTemplate returns a new error since the templates are shared.
+func fromTemplate(tmpl *uerr.UError, cause error) *uerr.UError {
+	return uerr.NewError(uerr.GetKey(tmpl), uerr.GetMessage(tmpl)).WithCause(cause)
 }
@@ -0,0 +1,104 @@
+package udatabase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapPgError(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        string
+		expectedKey string
+	}{
+		{name: "UniqueViolation", code: "23505", expectedKey: uerr.ResourceAlreadyExistsError},
+		{name: "NotNullViolation", code: "23502", expectedKey: uerr.WrongInputParameterError},
+		{name: "ForeignKeyViolation", code: "23503", expectedKey: uerr.WrongInputParameterError},
+		{name: "CheckViolation", code: "23514", expectedKey: uerr.WrongInputParameterError},
+		{name: "StringDataRightTruncation", code: "22001", expectedKey: uerr.WrongInputParameterError},
+		{name: "InvalidTextRepresentation", code: "22P02", expectedKey: uerr.WrongInputParameterError},
+		{name: "SerializationFailure", code: "40001", expectedKey: uerr.ConflictError},
+		{name: "DeadlockDetected", code: "40P01", expectedKey: uerr.ConflictError},
+		{name: "LockNotAvailable", code: "55P03", expectedKey: uerr.ConflictError},
+		{name: "QueryCanceled", code: "57014", expectedKey: uerr.TimeoutError},
+		{name: "UnmappedDataExceptionFallsBackToClass", code: "22012", expectedKey: uerr.WrongInputParameterError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+"FromPq", func(t *testing.T) {
+			// ACT
+			err := MapPgError(&pq.Error{Code: pq.ErrorCode(tt.code)})
+
+			// ASSERT
+			require.NotNil(t, err)
+			require.Equal(t, tt.expectedKey, uerr.GetKey(err))
+		})
+
+		t.Run(tt.name+"FromPgx", func(t *testing.T) {
+			// ACT
+			err := MapPgError(&pgconn.PgError{Code: tt.code})
+
+			// ASSERT
+			require.NotNil(t, err)
+			require.Equal(t, tt.expectedKey, uerr.GetKey(err))
+		})
+	}
+
+	t.Run("WrappedError_addsMetadata", func(t *testing.T) {
+		// ARRANGE
+		pgErr := &pgconn.PgError{
+			Code:           "23505",
+			SchemaName:     "public",
+			TableName:      "users",
+			ConstraintName: "users_email_key",
+		}
+
+		// ACT
+		err := MapPgError(fmt.Errorf("saving user: %w", pgErr))
+
+		// ASSERT
+		require.NotNil(t, err)
+		require.Equal(t, map[string]string{
+			MetadataSQLState:   "23505",
+			MetadataSchema:     "public",
+			MetadataTable:      "users",
+			MetadataConstraint: "users_email_key",
+		}, uerr.GetMetadata(err))
+	})
+
+	t.Run("ConstraintWithCustomError_returnsCustomError", func(t *testing.T) {
+		// ARRANGE
+		ConstraintErrors["users_email_key"] = uerr.NewError(uerr.ResourceAlreadyExistsError, "Email already in use.")
+		defer delete(ConstraintErrors, "users_email_key")
+
+		// ACT
+		err := MapPgError(&pq.Error{Code: "23505", Constraint: "users_email_key", Column: "email"})
+
+		// ASSERT
+		require.NotNil(t, err)
+		require.Equal(t, uerr.ResourceAlreadyExistsError, uerr.GetKey(err))
+		require.Equal(t, "Email already in use.", uerr.GetMessage(err))
+		require.Equal(t, "email", uerr.GetMetadata(err)[MetadataColumn])
+	})
+
+	t.Run("MappingError_doesNotModifyTemplates", func(t *testing.T) {
+		// ACT
+		_ = MapPgError(&pq.Error{Code: "23505", Table: "users"})
+
+		// ASSERT
+		require.Nil(t, uerr.GetMetadata(PqErrors["unique_violation"]))
+	})
+
+	t.Run("UnmappedCode_returnsNil", func(t *testing.T) {
+		require.Nil(t, MapPgError(&pq.Error{Code: "42601"}))
+	})
+
+	t.Run("NonPostgresError_returnsNil", func(t *testing.T) {
+		require.Nil(t, MapPgError(fmt.Errorf("err")))
+	})
+}
@@ -0,0 +1,20 @@
+package udatabase
+
+import "context"
+
+type ctxk string
+
+const primaryName ctxk = "dbprimary"
+
+// WithPrimary returns a copy of ctx that makes DBHolders run read queries against the
+// primary database instead of a read replica. It is useful to read your own writes
+// right after committing them, since replicas may lag behind the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryName, true)
+}
+
+// UsePrimary returns true if ctx was created with WithPrimary.
+func UsePrimary(ctx context.Context) bool {
+	usePrimary, _ := ctx.Value(primaryName).(bool)
+	return usePrimary
+}
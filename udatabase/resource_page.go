@@ -1,8 +1,102 @@
 package udatabase
 
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
 type ResourcePage[T any] struct {
-	Total     int64 `json:"total"`
-	Limit     int64 `json:"limit"`
-	Offset    int64 `json:"offset"`
-	Resources []T   `json:"resources"`
+	Total  int64 `json:"total"`
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+
+	// HasMore is true when there are resources after the page, see SetResources.
+	HasMore bool `json:"hasMore"`
+	// NextOffset is the offset of the next page when HasMore is true.
+	NextOffset int64 `json:"nextOffset,omitempty"`
+
+	Resources []T `json:"resources"`
+}
+
+const totalName ctxk = "dbtotal"
+
+// WithTotal returns a copy of ctx that makes repositories count the resources matching the
+// filters of a search, with a separate count query, to set it as the Total of the ResourcePage.
+// Otherwise, Total is the number of resources of the page.
+func WithTotal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, totalName, true)
+}
+
+// CountTotal returns true if ctx was created with WithTotal.
+func CountTotal(ctx context.Context) bool {
+	countTotal, _ := ctx.Value(totalName).(bool)
+	return countTotal
+}
+
+// SetResources sets the resources of the page and its Total, HasMore and NextOffset from them
+// and from Limit and Offset. total is the number of resources matching the filters, or -1 if
+// it was not counted, in which case Total is the number of resources of the page and HasMore
+// is true when the page is full.
+func (rp *ResourcePage[T]) SetResources(resources []T, total int64) {
+	rp.Resources = resources
+	size := int64(len(resources))
+	if total < 0 {
+		rp.Total = size
+		rp.HasMore = rp.Limit > 0 && size >= rp.Limit
+	} else {
+		rp.Total = total
+		rp.HasMore = rp.Offset+size < total
+	}
+
+	rp.NextOffset = 0
+	if rp.HasMore {
+		rp.NextOffset = rp.Offset + size
+	}
+}
+
+// NextURL returns u with the offset of the next page, or "" if there is no next page. The rest of
+// the query of u is kept as it is, so u should be the URL of the request that returned the page.
+func (rp *ResourcePage[T]) NextURL(u *url.URL) string {
+	if !rp.HasMore {
+		return ""
+	}
+	return pageURL(u, rp.NextOffset, rp.Limit)
+}
+
+// PrevURL returns u with the offset of the previous page, or "" if the page is the first one.
+// The rest of the query of u is kept as it is.
+func (rp *ResourcePage[T]) PrevURL(u *url.URL) string {
+	if rp.Offset <= 0 {
+		return ""
+	}
+	return pageURL(u, max(rp.Offset-rp.Limit, 0), rp.Limit)
+}
+
+// LinkHeader returns the value of a RFC 8288 Link header with the next and prev URLs of the page,
+// e.g. `<https://api.example.com/users?limit=10&offset=20>; rel="next"`. It returns "" if there
+// are neither.
+func (rp *ResourcePage[T]) LinkHeader(u *url.URL) string {
+	var links []string
+	if next := rp.NextURL(u); next != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, next))
+	}
+	if prev := rp.PrevURL(u); prev != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, prev))
+	}
+	return strings.Join(links, ", ")
+}
+
+func pageURL(u *url.URL, offset, limit int64) string {
+	v := u.Query()
+	v.Set("offset", strconv.FormatInt(offset, 10))
+	if limit > 0 {
+		v.Set("limit", strconv.FormatInt(limit, 10))
+	}
+
+	page := *u
+	page.RawQuery = v.Encode()
+	return page.String()
 }
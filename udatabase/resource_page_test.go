@@ -0,0 +1,102 @@
+package udatabase
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTotal(t *testing.T) {
+	// ACT
+	ctx := WithTotal(context.Background())
+
+	// ASSERT
+	require.True(t, CountTotal(ctx))
+	require.False(t, CountTotal(context.Background()))
+}
+
+func TestResourcePageSetResources(t *testing.T) {
+	tests := []struct {
+		name      string
+		page      ResourcePage[int]
+		resources []int
+		total     int64
+		expected  ResourcePage[int]
+	}{
+		{
+			name:      "CountedTotalWithMoreResources_hasMore",
+			page:      ResourcePage[int]{Limit: 2, Offset: 2},
+			resources: []int{3, 4},
+			total:     5,
+			expected:  ResourcePage[int]{Total: 5, Limit: 2, Offset: 2, HasMore: true, NextOffset: 4, Resources: []int{3, 4}},
+		},
+		{
+			name:      "CountedTotalInLastPage_hasNoMore",
+			page:      ResourcePage[int]{Limit: 2, Offset: 4},
+			resources: []int{5},
+			total:     5,
+			expected:  ResourcePage[int]{Total: 5, Limit: 2, Offset: 4, Resources: []int{5}},
+		},
+		{
+			name:      "NotCountedTotalWithFullPage_hasMore",
+			page:      ResourcePage[int]{Limit: 2},
+			resources: []int{1, 2},
+			total:     -1,
+			expected:  ResourcePage[int]{Total: 2, Limit: 2, HasMore: true, NextOffset: 2, Resources: []int{1, 2}},
+		},
+		{
+			name:      "NotCountedTotalWithoutFullPage_hasNoMore",
+			page:      ResourcePage[int]{Limit: 2},
+			resources: []int{1},
+			total:     -1,
+			expected:  ResourcePage[int]{Total: 1, Limit: 2, Resources: []int{1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ACT
+			tt.page.SetResources(tt.resources, tt.total)
+
+			// ASSERT
+			require.Equal(t, tt.expected, tt.page)
+		})
+	}
+}
+
+func TestResourcePageLinks(t *testing.T) {
+	u, err := url.Parse("https://api.example.com/resources?name=a&limit=2&offset=2")
+	require.NoError(t, err)
+
+	t.Run("MiddlePage_returnsNextAndPrevLinks", func(t *testing.T) {
+		// ARRANGE
+		rp := ResourcePage[int]{Limit: 2, Offset: 2}
+		rp.SetResources([]int{3, 4}, 5)
+
+		// ACT
+		next := rp.NextURL(u)
+		prev := rp.PrevURL(u)
+		link := rp.LinkHeader(u)
+
+		// ASSERT
+		require.Equal(t, "https://api.example.com/resources?limit=2&name=a&offset=4", next)
+		require.Equal(t, "https://api.example.com/resources?limit=2&name=a&offset=0", prev)
+		require.Equal(t, `<`+next+`>; rel="next", <`+prev+`>; rel="prev"`, link)
+	})
+
+	t.Run("OnlyPage_returnsNoLinks", func(t *testing.T) {
+		// ARRANGE
+		rp := ResourcePage[int]{Limit: 2}
+		rp.SetResources([]int{1}, -1)
+
+		// ACT
+		link := rp.LinkHeader(u)
+
+		// ASSERT
+		require.Empty(t, rp.NextURL(u))
+		require.Empty(t, rp.PrevURL(u))
+		require.Empty(t, link)
+	})
+}
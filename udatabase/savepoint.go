@@ -0,0 +1,70 @@
+package udatabase
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	savepointName   ctxk = "dbsavepoint"
+	testTransaction ctxk = "dbtesttx"
+)
+
+// WithTestTransaction returns a copy of ctx, which must hold a transaction, in which nested Begin
+// calls of repositories create savepoints instead of joining the transaction, and the queries that
+// are routed to the primary database or a read replica run in the transaction. The BeginTestTx of
+// the TestDBHolders use it, so the code under test sees its own changes and they are rolled back.
+func WithTestTransaction(ctx context.Context) context.Context {
+	return context.WithValue(ctx, testTransaction, true)
+}
+
+// IsTestTransaction reports whether ctx was returned by WithTestTransaction.
+func IsTestTransaction(ctx context.Context) bool {
+	isTestTx, _ := ctx.Value(testTransaction).(bool)
+	return isTestTx
+}
+
+// savepoint is the state of the savepoint of a context created with WithSavepoint.
+type savepoint struct {
+	depth    int
+	released bool
+}
+
+func (s *savepoint) name() string {
+	return fmt.Sprintf("udatabase_sp_%d", s.depth)
+}
+
+// WithSavepoint returns a copy of ctx for a savepoint nested in the transaction of ctx, and the
+// name of the savepoint. Repositories use it to map Begin calls on a ctx that already holds a
+// transaction to savepoints, so Commit releases the savepoint and Rollback only undoes what was
+// done since Begin.
+func WithSavepoint(ctx context.Context) (context.Context, string) {
+	sp := &savepoint{depth: 1}
+	if parent, ok := ctx.Value(savepointName).(*savepoint); ok {
+		sp.depth = parent.depth + 1
+	}
+	return context.WithValue(ctx, savepointName, sp), sp.name()
+}
+
+// SavepointFromContext returns the name of the savepoint of ctx, if it was created with WithSavepoint.
+func SavepointFromContext(ctx context.Context) (string, bool) {
+	sp, ok := ctx.Value(savepointName).(*savepoint)
+	if !ok {
+		return "", false
+	}
+	return sp.name(), true
+}
+
+// ReleaseSavepoint records that the savepoint of ctx was released, which Commit does, so that a
+// deferred Rollback does not roll back to a savepoint that no longer exists, see IsSavepointReleased.
+func ReleaseSavepoint(ctx context.Context) {
+	if sp, ok := ctx.Value(savepointName).(*savepoint); ok {
+		sp.released = true
+	}
+}
+
+// IsSavepointReleased reports whether the savepoint of ctx was released with ReleaseSavepoint.
+func IsSavepointReleased(ctx context.Context) bool {
+	sp, ok := ctx.Value(savepointName).(*savepoint)
+	return ok && sp.released
+}
@@ -0,0 +1,58 @@
+package udatabase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTestTransaction(t *testing.T) {
+	// ACT
+	ctx := WithTestTransaction(context.Background())
+
+	// ASSERT
+	require.True(t, IsTestTransaction(ctx))
+	require.False(t, IsTestTransaction(context.Background()))
+}
+
+func TestWithSavepoint(t *testing.T) {
+	t.Run("ContextWithoutSavepoint_hasNoSavepoint", func(t *testing.T) {
+		// ACT
+		_, ok := SavepointFromContext(context.Background())
+
+		// ASSERT
+		require.False(t, ok)
+	})
+
+	t.Run("NestedSavepoints_haveDifferentNames", func(t *testing.T) {
+		// ACT
+		ctx1, name1 := WithSavepoint(context.Background())
+		ctx2, name2 := WithSavepoint(ctx1)
+
+		// ASSERT
+		require.NotEqual(t, name1, name2)
+
+		actual1, ok := SavepointFromContext(ctx1)
+		require.True(t, ok)
+		require.Equal(t, name1, actual1)
+
+		actual2, ok := SavepointFromContext(ctx2)
+		require.True(t, ok)
+		require.Equal(t, name2, actual2)
+	})
+
+	t.Run("ReleasedSavepoint_isReleasedOnlyInItsContext", func(t *testing.T) {
+		// ARRANGE
+		ctx1, _ := WithSavepoint(context.Background())
+		ctx2, _ := WithSavepoint(ctx1)
+
+		// ACT
+		ReleaseSavepoint(ctx2)
+
+		// ASSERT
+		require.True(t, IsSavepointReleased(ctx2))
+		require.False(t, IsSavepointReleased(ctx1))
+		require.False(t, IsSavepointReleased(context.Background()))
+	})
+}
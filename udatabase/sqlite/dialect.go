@@ -0,0 +1,161 @@
+// Package sqlite provides the SQLite udatabase.Dialect, which allows running usql/uorm
+// repositories and TestDBHolders without a Postgres server. It uses the pure Go
+// driver modernc.org/sqlite, registered as "sqlite", so it does not require cgo.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/golang-migrate/migrate/v4/database"
+	migrateSqlite "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// connParams enables foreign keys, which SQLite disables by default, and makes concurrent
+// writers wait for each other instead of failing with SQLITE_BUSY.
+const connParams = "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+
+// conditions maps SQLite extended result codes to the Postgres condition names of udatabase.PqErrors,
+// so errors are mapped to the same keys and messages in every dialect.
+var conditions = map[int]string{
+	sqlite3.SQLITE_CONSTRAINT_UNIQUE:     "unique_violation",
+	sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY: "unique_violation",
+	sqlite3.SQLITE_CONSTRAINT_NOTNULL:    "not_null_violation",
+	sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY: "foreign_key_violation",
+	sqlite3.SQLITE_CONSTRAINT_CHECK:      "check_violation",
+	sqlite3.SQLITE_TOOBIG:                "string_data_right_truncation",
+	sqlite3.SQLITE_BUSY:                  "lock_not_available",
+	sqlite3.SQLITE_LOCKED:                "lock_not_available",
+	sqlite3.SQLITE_INTERRUPT:             "query_canceled",
+}
+
+// Dialect is the udatabase.Dialect of SQLite. SQLite has no schemas, so every
+// DBConfig.SchemaName is a different database. Only DatabaseName, SchemaName
+// and MigrationsDir of the DBConfig are used.
+//
+// Usage:
+//
+//	cfg := &udatabase.DBConfig{SchemaName: "users_test", Dialect: sqlite.Dialect{}}
+//	dbHolder := usql.NewTestDBHolderFromConfig(cfg)
+type Dialect struct {
+	// Dir is the folder where the database files are stored. If empty, databases are kept
+	// in memory until the last connection to them is closed.
+	Dir string
+}
+
+func (Dialect) DriverName() string {
+	return "sqlite"
+}
+
+func (d Dialect) ConnectionString(cfg *udatabase.DBConfig) string {
+	if d.Dir == "" {
+		name := url.PathEscape(fmt.Sprintf("%s_%s", cfg.DatabaseName, cfg.SchemaName))
+		return fmt.Sprintf("file:/%s?vfs=memdb&%s", name, connParams)
+	}
+
+	path := filepath.Join(d.Dir, url.PathEscape(cfg.SchemaName)+".db")
+	return fmt.Sprintf("file:%s?%s", path, connParams)
+}
+
+// CreateSchema does nothing since the database is created when connecting to it.
+func (Dialect) CreateSchema(*sql.DB, *udatabase.DBConfig) error {
+	return nil
+}
+
+// DropSchema drops every table and view of the database.
+func (Dialect) DropSchema(db *sql.DB, _ *udatabase.DBConfig) (rErr error) {
+	ctx := context.Background()
+
+	// Foreign keys are disabled in a single connection so tables can be dropped in any order.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+		rErr = errors.Join(rErr, err, conn.Close())
+	}()
+
+	_, err = conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF")
+	if err != nil {
+		return err
+	}
+
+	type object struct {
+		kind string
+		name string
+	}
+	var objects []object
+	rows, err := conn.QueryContext(ctx,
+		"SELECT type, name FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%'")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o object
+		if err = rows.Scan(&o.kind, &o.name); err != nil {
+			return err
+		}
+		objects = append(objects, o)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, o := range objects {
+		name, err := ident.Quote(o.name)
+		if err != nil {
+			return err
+		}
+
+		_, err = conn.ExecContext(ctx, fmt.Sprintf("DROP %s IF EXISTS %s", o.kind, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (Dialect) MigrationDriver(db *sql.DB, _ *udatabase.DBConfig) (database.Driver, error) {
+	return migrateSqlite.WithInstance(db, &migrateSqlite.Config{})
+}
+
+// MapError maps constraint violations and locking errors to the UErrors of the
+// equivalent Postgres conditions in udatabase.PqErrors.
+func (Dialect) MapError(err error) *uerr.UError {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return nil
+	}
+
+	condition, ok := conditions[sqliteErr.Code()]
+	if !ok {
+		return nil
+	}
+
+	return udatabase.ConditionError(condition, err)
+}
+
+func (Dialect) BindType() int {
+	return sqlx.QUESTION
+}
+
+// ForeignKeysQuery returns the query of the foreign keys of the tables, see
+// udatabase.ForeignKeysDialect.
+func (Dialect) ForeignKeysQuery() string {
+	return "SELECT m.name, f.\"table\" FROM sqlite_master AS m JOIN pragma_foreign_key_list(m.name) AS f " +
+		"WHERE m.type = 'table'"
+}
@@ -0,0 +1,186 @@
+package udatabase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/golang-migrate/migrate/v4"
+	migratePostgres "github.com/golang-migrate/migrate/v4/database/postgres"
+)
+
+const tenantName ctxk = "dbtenant"
+
+// maxIdentifierLength is the maximum length of a Postgres identifier (NAMEDATALEN - 1).
+const maxIdentifierLength = 63
+
+var tenantIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// WithTenant returns a copy of ctx that makes repositories run queries against the
+// schema of tenantID. See DBConfig.TenantSchemaName.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantName, tenantID)
+}
+
+// TenantFromContext returns the tenant ID stored in ctx by WithTenant or empty string if there is none.
+func TenantFromContext(ctx context.Context) string {
+	tenantID, _ := ctx.Value(tenantName).(string)
+	return tenantID
+}
+
+// TenantSchemaName returns the name of the schema of tenantID, which is DBConfig.TenantSchemaPrefix
+// followed by tenantID. Tenant IDs can only contain letters, numbers, '_' and '-'.
+func (c *DBConfig) TenantSchemaName(tenantID string) (string, error) {
+	schemaName := c.TenantSchemaPrefix + tenantID
+	if !tenantIDRegexp.MatchString(tenantID) || len(schemaName) > maxIdentifierLength {
+		msg := fmt.Sprintf("Invalid tenant %q. It must contain only letters, numbers, '_' or '-' and "+
+			"be at most %d characters long including the prefix %q.",
+			tenantID, maxIdentifierLength, c.TenantSchemaPrefix)
+		return "", uerr.NewError(uerr.WrongInputParameterError, msg)
+	}
+
+	return schemaName, nil
+}
+
+// TenantSearchPath returns the value for search_path of the tenant stored in ctx,
+// or empty string if ctx has no tenant.
+func (c *DBConfig) TenantSearchPath(ctx context.Context) (string, error) {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == "" {
+		return "", nil
+	}
+
+	schemaName, err := c.TenantSchemaName(tenantID)
+	if err != nil {
+		return "", err
+	}
+
+	return ident.Quote(schemaName)
+}
+
+// ProvisionTenant creates the schema of tenantID if it does not exist and runs
+// the SQL migrations found in DBConfig.MigrationsDir on it.
+func ProvisionTenant(ctx context.Context, db *sql.DB, cfg *DBConfig, tenantID string) error {
+	schemaName, err := cfg.TenantSchemaName(tenantID)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;", ident.MustQuote(schemaName)))
+	if err != nil {
+		return uerr.NewError(uerr.GenericError, "Error creating tenant schema.").WithCause(err)
+	}
+
+	return runTenantMigrations(ctx, db, cfg, schemaName)
+}
+
+// ListTenants returns the IDs of the tenants that have a schema in the database.
+func ListTenants(ctx context.Context, db *sql.DB, cfg *DBConfig) ([]string, error) {
+	rows, err := db.QueryContext(ctx,
+		`SELECT substr(schema_name, length($1) + 1) FROM information_schema.schemata
+		WHERE left(schema_name, length($1)) = $1 AND length(schema_name) > length($1)
+		ORDER BY schema_name`,
+		cfg.TenantSchemaPrefix)
+	if err != nil {
+		return nil, uerr.NewError(uerr.GenericError, "Error listing tenants.").WithCause(err)
+	}
+	defer rows.Close()
+
+	var tenantIDs []string
+	for rows.Next() {
+		var tenantID string
+		if err := rows.Scan(&tenantID); err != nil {
+			return nil, uerr.NewError(uerr.GenericError, "Error listing tenants.").WithCause(err)
+		}
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, uerr.NewError(uerr.GenericError, "Error listing tenants.").WithCause(err)
+	}
+	return tenantIDs, nil
+}
+
+// MigrateTenants runs the SQL migrations found in DBConfig.MigrationsDir on the schema of
+// every tenant returned by ListTenants. It stops at the first tenant that fails.
+func MigrateTenants(ctx context.Context, db *sql.DB, cfg *DBConfig) error {
+	tenantIDs, err := ListTenants(ctx, db, cfg)
+	if err != nil {
+		return err
+	}
+
+	for _, tenantID := range tenantIDs {
+		schemaName, err := cfg.TenantSchemaName(tenantID)
+		if err != nil {
+			return err
+		}
+
+		err = runTenantMigrations(ctx, db, cfg, schemaName)
+		if err != nil {
+			return fmt.Errorf("migrating tenant %q: %w", tenantID, err)
+		}
+	}
+
+	return nil
+}
+
+// runTenantMigrations runs the migrations on a dedicated connection whose search_path is
+// schemaName, so unqualified names in the migrations refer to the tenant schema.
+func runTenantMigrations(ctx context.Context, db *sql.DB, cfg *DBConfig, schemaName string) error {
+	if !cfg.MigrationsLock {
+		return migrateSchema(ctx, db, cfg, schemaName)
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, cfg.MigrationsLockTimeout)
+	defer cancel()
+
+	return WithAdvisoryLock(lockCtx, db, MigrationsLockKey(schemaName), func(context.Context) error {
+		return migrateSchema(ctx, db, cfg, schemaName)
+	})
+}
+
+func migrateSchema(ctx context.Context, db *sql.DB, cfg *DBConfig, schemaName string) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var m *migrate.Migrate
+	defer func() {
+		// The connection is discarded instead of returned to the pool, since its search_path
+		// no longer matches the one of the connection string.
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		_ = conn.Close()
+		if m != nil {
+			_, _ = m.Close()
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, "SELECT set_config('search_path', $1, false)", ident.MustQuote(schemaName))
+	if err != nil {
+		return err
+	}
+
+	migrateDriver, err := migratePostgres.WithConnection(ctx, conn, &migratePostgres.Config{
+		SchemaName: schemaName,
+	})
+	if err != nil {
+		return err
+	}
+
+	m, err = migrate.NewWithDatabaseInstance(fmt.Sprintf("file://%s", cfg.MigrationsDir), "postgres", migrateDriver)
+	if err != nil {
+		return err
+	}
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
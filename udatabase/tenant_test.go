@@ -0,0 +1,66 @@
+package udatabase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantSchemaName(t *testing.T) {
+	cfg := &DBConfig{TenantSchemaPrefix: "tenant_"}
+
+	t.Run("ValidTenantID_returnsPrefixedSchemaName", func(t *testing.T) {
+		// ACT
+		actual, err := cfg.TenantSchemaName("0ea57dec-5e79-40dc-b971-a52561fcc2c7")
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, "tenant_0ea57dec-5e79-40dc-b971-a52561fcc2c7", actual)
+	})
+
+	invalidTenantIDs := []string{
+		"",
+		"a b",
+		`a"; DROP SCHEMA public CASCADE; --`,
+		"tenant.name",
+		strings.Repeat("a", 60),
+	}
+	for _, tenantID := range invalidTenantIDs {
+		t.Run("InvalidTenantID_returnsWrongInputParameterError", func(t *testing.T) {
+			// ACT
+			_, err := cfg.TenantSchemaName(tenantID)
+
+			// ASSERT
+			require.Error(t, err)
+			require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		})
+	}
+}
+
+func TestTenantSearchPath(t *testing.T) {
+	cfg := &DBConfig{TenantSchemaPrefix: "tenant_"}
+
+	t.Run("ContextWithoutTenant_returnsEmptySearchPath", func(t *testing.T) {
+		// ACT
+		actual, err := cfg.TenantSearchPath(context.Background())
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Empty(t, actual)
+	})
+
+	t.Run("ContextWithTenant_returnsQuotedSchemaName", func(t *testing.T) {
+		// ARRANGE
+		ctx := WithTenant(context.Background(), "Acme-1")
+
+		// ACT
+		actual, err := cfg.TenantSearchPath(ctx)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, `"tenant_Acme-1"`, actual)
+	})
+}
@@ -0,0 +1,205 @@
+package udatabase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// TB is the subset of testing.TB used by the test helpers of the repositories, so that the
+// packages do not import testing. *testing.T and *testing.B implement it.
+type TB interface {
+	Helper()
+	Cleanup(func())
+	Errorf(format string, args ...any)
+	Fatalf(format string, args ...any)
+}
+
+// migratedTemplates contains the template databases already created by this process, by server and name.
+var migratedTemplates sync.Map
+
+// NewIsolatedDBConfig returns a copy of cfg that points to a database only used by t, so tests
+// using it can run with t.Parallel(). With Postgres, the database is cloned from a template database
+// that is migrated once per set of migrations and reused between runs, and it is dropped with
+// t.Cleanup (dropping requires Postgres 13 or newer). Other dialects get a random SchemaName,
+// so the caller has to run the migrations on it.
+func NewIsolatedDBConfig(t TB, cfg *DBConfig) *DBConfig {
+	t.Helper()
+
+	// cfg is copied since it may be shared by parallel tests.
+	base := *cfg
+	base.SetEmptyValuesToDefaults()
+	base.ReplicaHosts = nil
+	cfg = &base
+	isolated := base
+
+	if _, ok := cfg.GetDialect().(PostgresDialect); !ok {
+		isolated.SchemaName = fmt.Sprintf("%s_%s", cfg.SchemaName, randomName(t))
+		return &isolated
+	}
+
+	admin, err := sql.Open(cfg.GetDialect().DriverName(), cfg.GetConnectionString())
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	defer admin.Close()
+
+	template, err := migrateTemplate(admin, cfg)
+	if err != nil {
+		t.Fatalf("creating template database: %v", err)
+	}
+
+	isolated.DatabaseName = "utest_" + randomName(t)
+	_, err = admin.Exec(fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", ident.MustQuote(isolated.DatabaseName),
+		ident.MustQuote(template)))
+	if err != nil {
+		t.Fatalf("cloning template database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		admin, err := sql.Open(cfg.GetDialect().DriverName(), cfg.GetConnectionString())
+		if err != nil {
+			t.Errorf("%v", err)
+			return
+		}
+		defer admin.Close()
+
+		_, err = admin.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS %s WITH (FORCE)", ident.MustQuote(isolated.DatabaseName)))
+		if err != nil {
+			t.Errorf("dropping test database: %v", err)
+		}
+	})
+
+	return &isolated
+}
+
+// migrateTemplate returns the name of the template database with the migrations of cfg,
+// creating it if it does not exist. Templates are named after a hash of SchemaName and the
+// migrations, so changing a migration creates a new template.
+func migrateTemplate(admin *sql.DB, cfg *DBConfig) (string, error) {
+	name, err := templateName(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	key := fmt.Sprintf("%s:%s/%s", cfg.Host, cfg.Port, name)
+	if _, ok := migratedTemplates.Load(key); ok {
+		return name, nil
+	}
+
+	// The lock prevents several test binaries from creating the same template at the same time.
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.MigrationsLockTimeout)
+	defer cancel()
+
+	err = WithAdvisoryLock(ctx, admin, AdvisoryLockKey("udatabase_template:"+name), func(ctx context.Context) error {
+		var isTemplate sql.NullBool
+		err := admin.QueryRowContext(ctx, "SELECT datistemplate FROM pg_database WHERE datname = $1", name).
+			Scan(&isTemplate)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		// Databases are only marked as templates once migrated, so others are leftovers of failed runs.
+		if isTemplate.Valid && isTemplate.Bool {
+			return nil
+		}
+
+		quotedName := ident.MustQuote(name)
+		if isTemplate.Valid {
+			if _, err = admin.ExecContext(ctx, fmt.Sprintf("DROP DATABASE %s WITH (FORCE)", quotedName)); err != nil {
+				return err
+			}
+		}
+
+		if _, err = admin.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %s", quotedName)); err != nil {
+			return err
+		}
+
+		templateCfg := *cfg
+		templateCfg.DatabaseName = name
+		templateCfg.MigrationsLock = false
+		if err = migrateDatabase(&templateCfg); err != nil {
+			return err
+		}
+
+		_, err = admin.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s IS_TEMPLATE true", quotedName))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	migratedTemplates.Store(key, struct{}{})
+	return name, nil
+}
+
+// migrateDatabase creates SchemaName in the database of cfg and runs the migrations on it.
+// Every connection is closed when it returns, so the database can be used as template.
+func migrateDatabase(cfg *DBConfig) error {
+	db, err := sql.Open(cfg.GetDialect().DriverName(), cfg.GetConnectionString())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err = cfg.GetDialect().CreateSchema(db, cfg); err != nil {
+		return err
+	}
+
+	m, err := NewMigrate(db, cfg)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// templateName returns the name of the template database for the SchemaName and migrations of cfg.
+func templateName(cfg *DBConfig) (string, error) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(cfg.SchemaName))
+
+	entries, err := os.ReadDir(cfg.MigrationsDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(cfg.MigrationsDir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		_, _ = h.Write([]byte(entry.Name()))
+		_, _ = h.Write(content)
+	}
+
+	return fmt.Sprintf("utmpl_%x", h.Sum64()), nil
+}
+
+// randomName returns 16 random hexadecimal characters.
+func randomName(t TB) string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("%v", err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,88 @@
+package udatabase
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateName(t *testing.T) {
+	newConfig := func(t *testing.T) *DBConfig {
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "1_create_users.up.sql"), []byte("CREATE TABLE users (id INT);"), 0o600)
+		require.NoError(t, err)
+		return &DBConfig{SchemaName: "public", MigrationsDir: dir}
+	}
+
+	t.Run("SameMigrations_returnSameName", func(t *testing.T) {
+		// ARRANGE
+		cfg1 := newConfig(t)
+		cfg2 := newConfig(t)
+
+		// ACT
+		name1, err1 := templateName(cfg1)
+		name2, err2 := templateName(cfg2)
+
+		// ASSERT
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.Equal(t, name1, name2)
+		require.Regexp(t, "^utmpl_[0-9a-f]{1,16}$", name1)
+	})
+
+	t.Run("ChangedMigration_returnsDifferentName", func(t *testing.T) {
+		// ARRANGE
+		cfg := newConfig(t)
+		name1, err := templateName(cfg)
+		require.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(cfg.MigrationsDir, "1_create_users.up.sql"),
+			[]byte("CREATE TABLE users (id BIGINT);"), 0o600)
+		require.NoError(t, err)
+
+		// ACT
+		name2, err := templateName(cfg)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.NotEqual(t, name1, name2)
+	})
+
+	t.Run("DifferentSchema_returnsDifferentName", func(t *testing.T) {
+		// ARRANGE
+		cfg := newConfig(t)
+		name1, err := templateName(cfg)
+		require.NoError(t, err)
+		cfg.SchemaName = "other"
+
+		// ACT
+		name2, err := templateName(cfg)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.NotEqual(t, name1, name2)
+	})
+}
+
+func TestNewIsolatedDBConfig(t *testing.T) {
+	t.Run("NonPostgresDialect_returnsRandomSchemaWithoutModifyingConfig", func(t *testing.T) {
+		// ARRANGE
+		cfg := &DBConfig{SchemaName: "users", Dialect: fakeDialect{}}
+
+		// ACT
+		isolated1 := NewIsolatedDBConfig(t, cfg)
+		isolated2 := NewIsolatedDBConfig(t, cfg)
+
+		// ASSERT
+		require.Regexp(t, "^users_[0-9a-f]{16}$", isolated1.SchemaName)
+		require.NotEqual(t, isolated1.SchemaName, isolated2.SchemaName)
+		require.Equal(t, "users", cfg.SchemaName)
+		require.Empty(t, cfg.Host)
+	})
+}
+
+type fakeDialect struct {
+	PostgresDialect
+}
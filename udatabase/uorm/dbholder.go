@@ -2,6 +2,8 @@ package uorm
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
 
 	"github.com/carlosarismendi/utils/udatabase"
 
@@ -14,38 +16,80 @@ import (
 )
 
 type DBHolder struct {
-	config *udatabase.DBConfig
-	db     *gorm.DB
+	config   *udatabase.DBConfig
+	db       *gorm.DB
+	replicas []*gorm.DB
+	next     atomic.Uint64
 }
 
 // Returns a *DBHolder initialized with the provided config.
 // In case the *DBConfig object has zero values, those will
-// be filled with default values.
+// be filled with default values. The database engine is
+// selected by DBConfig.Dialect, Postgres by default. Dialects
+// other than Postgres must implement GormDialect, like the
+// SQLite dialect of the uorm/sqlite package.
 func NewDBHolder(config *udatabase.DBConfig) *DBHolder {
 	config.SetEmptyValuesToDefaults()
 
-	conn := config.GetConnectionString()
-	pg := postgres.Open(conn)
-	db, err := gorm.Open(pg, &gorm.Config{
+	dbHolder := &DBHolder{
+		config: config,
+		db:     open(config),
+	}
+
+	sdb, err := dbHolder.db.DB()
+	if err != nil {
+		panic(err)
+	}
+	config.CreateSchema(sdb)
+
+	for _, replicaConfig := range config.ReplicaConfigs() {
+		dbHolder.replicas = append(dbHolder.replicas, open(replicaConfig))
+	}
+
+	return dbHolder
+}
+
+// GormDialect is implemented by the dialects that can be used by the GORM DBHolder.
+type GormDialect interface {
+	// GormDialector returns the GORM dialector that connects to the database described by cfg.
+	GormDialector(cfg *udatabase.DBConfig) gorm.Dialector
+}
+
+// dialector returns the GORM dialector of the dialect of config. Dialects that do not
+// implement GormDialect are opened with GORM's Postgres dialector if they use the
+// Postgres driver.
+func dialector(config *udatabase.DBConfig) gorm.Dialector {
+	dialect := config.GetDialect()
+	if d, ok := dialect.(GormDialect); ok {
+		return d.GormDialector(config)
+	}
+
+	if dialect.DriverName() != udatabase.Postgres.DriverName() {
+		panic(fmt.Sprintf("dialect %s cannot be used with GORM", dialect.DriverName()))
+	}
+	return postgres.Open(config.GetConnectionString())
+}
+
+func open(config *udatabase.DBConfig) *gorm.DB {
+	db, err := gorm.Open(dialector(config), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	if err != nil {
 		panic(err)
 	}
 
-	dbHolder := &DBHolder{
-		config: config,
-		db:     db,
+	if observer := config.GetQueryObserver(); observer != nil {
+		if err = registerObserver(db, observer); err != nil {
+			panic(err)
+		}
 	}
 
 	sdb, err := db.DB()
 	if err != nil {
 		panic(err)
 	}
-	config.CreateSchema(sdb)
-	config.SetSearchPath(sdb)
-
-	return dbHolder
+	config.Metrics.RegisterPool(config.PoolLabels(), sdb.Stats)
+	return db
 }
 
 // RunMigrations runs SQL migrations found in the folder specified by DBConfig.MigrationsDir
@@ -57,6 +101,36 @@ func (d *DBHolder) RunMigrations() error {
 	return udatabase.RunMigrations(sdb, d.config)
 }
 
+// ProvisionTenant creates the schema of tenantID if it does not exist and runs
+// the SQL migrations found in DBConfig.MigrationsDir on it.
+func (d *DBHolder) ProvisionTenant(ctx context.Context, tenantID string) error {
+	sdb, err := d.db.DB()
+	if err != nil {
+		return err
+	}
+	return udatabase.ProvisionTenant(ctx, sdb, d.config, tenantID)
+}
+
+// MigrateTenants runs the SQL migrations found in DBConfig.MigrationsDir on the schema of every tenant.
+func (d *DBHolder) MigrateTenants(ctx context.Context) error {
+	sdb, err := d.db.DB()
+	if err != nil {
+		return err
+	}
+	return udatabase.MigrateTenants(ctx, sdb, d.config)
+}
+
+// WithAdvisoryLock runs fn while holding the Postgres advisory lock identified by key.
+// It waits until the lock is acquired or ctx is done. Keys can be derived from
+// names with udatabase.AdvisoryLockKey.
+func (d *DBHolder) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	sdb, err := d.db.DB()
+	if err != nil {
+		return err
+	}
+	return udatabase.WithAdvisoryLock(ctx, sdb, key, fn)
+}
+
 // GetDBInstance returns the inner database object *gorm.DB provided by GORM.
 // More on GORM here: https://gorm.io/
 func (d *DBHolder) GetDBInstance(ctx context.Context) *gorm.DB {
@@ -66,3 +140,20 @@ func (d *DBHolder) GetDBInstance(ctx context.Context) *gorm.DB {
 	}
 	return txFromCtx.(*gorm.DB)
 }
+
+// GetReadDBInstance returns the *gorm.DB to run read queries with. It returns the transaction
+// in ctx if there is one, otherwise the read replicas in round-robin order, or the primary
+// database if there are no replicas or ctx was created with udatabase.WithPrimary.
+func (d *DBHolder) GetReadDBInstance(ctx context.Context) *gorm.DB {
+	txFromCtx := ctx.Value(ctxk(transactionName))
+	if txFromCtx != nil {
+		return txFromCtx.(*gorm.DB)
+	}
+
+	if len(d.replicas) == 0 || udatabase.UsePrimary(ctx) {
+		return d.db.WithContext(ctx)
+	}
+
+	i := d.next.Add(1) % uint64(len(d.replicas))
+	return d.replicas[i].WithContext(ctx)
+}
@@ -6,13 +6,15 @@ import (
 	"fmt"
 	"github.com/carlosarismendi/utils/udatabase/filters"
 	"net/url"
+	"reflect"
+	"slices"
+	"time"
 
 	"github.com/carlosarismendi/utils/udatabase"
 	uormFilters "github.com/carlosarismendi/utils/udatabase/uorm/filters"
 	"github.com/carlosarismendi/utils/uerr"
-	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type ctxk string
@@ -23,6 +25,7 @@ const transactionName string = "dbtx"
 // well as methods like Save or Find.
 type DBrepository[T any] struct {
 	db      *DBHolder
+	name    string
 	filters map[string]uormFilters.Filter[T]
 }
 
@@ -45,18 +48,51 @@ func NewDBRepository[T any](dbHolder *DBHolder, filtersMap map[string]uormFilter
 
 	return &DBrepository[T]{
 		db:      dbHolder,
+		name:    udatabase.RepositoryName[T](),
 		filters: filtersMap,
 	}
 }
 
-// Begin opens a new transaction.
-// NOTE: Nested transactions not supported.
-func (r *DBrepository[T]) Begin(ctx context.Context) (context.Context, error) {
-	txFromCtx := ctx.Value(transactionName)
+// Describe returns the descriptions of the query parameters of the repository, its filters,
+// including "limit" and "offset", sorted by name, e.g. to generate the API documentation of the
+// endpoint with filters.OpenAPIParameters or filters.ParametersJSONSchema. Endpoints paginated
+// by keyset may add filters.DescribeCursor().
+func (r *DBrepository[T]) Describe() []filters.Description {
+	params := make(map[string]any, len(r.filters))
+	for name, f := range r.filters {
+		params[name] = f
+	}
+	return filters.DescribeParams(params)
+}
+
+// Begin opens a new transaction. If ctx has a tenant (see udatabase.WithTenant),
+// the transaction runs against the schema of the tenant. If ctx already holds a
+// transaction, Begin returns ctx as it is, so the caller joins the transaction, unless
+// ctx holds the transaction of a test (see udatabase.WithTestTransaction), in which
+// case Begin creates a savepoint in it.
+func (r *DBrepository[T]) Begin(ctx context.Context) (_ context.Context, rErr error) {
+	defer r.observe("Begin", time.Now(), &rErr)
+
+	txFromCtx := ctx.Value(ctxk(transactionName))
 	if txFromCtx != nil {
+		if !udatabase.IsTestTransaction(ctx) {
+			return ctx, nil
+		}
+
+		ctx, savepoint := udatabase.WithSavepoint(ctx)
+		err := txFromCtx.(*gorm.DB).SavePoint(savepoint).Error
+		if err != nil {
+			tErr := uerr.NewError(uerr.GenericError, "Error creating savepoint.").WithCause(err)
+			return nil, tErr
+		}
 		return ctx, nil
 	}
 
+	searchPath, err := r.db.config.TenantSearchPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	tx := r.db.db.WithContext(ctx).Begin()
 
 	if tx.Error != nil {
@@ -64,44 +100,84 @@ func (r *DBrepository[T]) Begin(ctx context.Context) (context.Context, error) {
 		return nil, tErr
 	}
 
+	if searchPath != "" {
+		err = tx.Exec(setLocalSearchPath, searchPath).Error
+		if err != nil {
+			_ = tx.Rollback().Error
+			tErr := uerr.NewError(uerr.GenericError, "Error setting tenant schema.").WithCause(err)
+			return nil, tErr
+		}
+	}
+
 	ctx = context.WithValue(ctx, ctxk(transactionName), tx)
 	return ctx, nil
 }
 
-// Commit closes and confirms the current transaction.
-func (r *DBrepository[T]) Commit(ctx context.Context) error {
+// Commit closes and confirms the current transaction, or releases the
+// current savepoint if ctx was returned by a nested Begin.
+func (r *DBrepository[T]) Commit(ctx context.Context) (rErr error) {
+	defer r.observe("Commit", time.Now(), &rErr)
+
 	txFromCtx := ctx.Value(ctxk(transactionName))
 	if txFromCtx == nil {
 		tErr := uerr.NewError(uerr.GenericError, "Missing transaction when doing Commit.")
 		return tErr
 	}
 	tx := txFromCtx.(*gorm.DB)
+
+	if savepoint, ok := udatabase.SavepointFromContext(ctx); ok {
+		if err := tx.Exec("RELEASE SAVEPOINT " + savepoint).Error; err != nil {
+			return err
+		}
+		udatabase.ReleaseSavepoint(ctx)
+		return nil
+	}
 	return tx.Commit().Error
 }
 
-// Rollback cancels the current transaction.
+// Rollback cancels the current transaction, or the changes made since the
+// current savepoint if ctx was returned by a nested Begin. It does nothing
+// if Commit already released the savepoint, so it can be deferred.
 func (r *DBrepository[T]) Rollback(ctx context.Context) {
 	txFromCtx := ctx.Value(ctxk(transactionName))
 	if txFromCtx == nil {
 		return
 	}
 	tx := txFromCtx.(*gorm.DB)
+
+	if savepoint, ok := udatabase.SavepointFromContext(ctx); ok {
+		if !udatabase.IsSavepointReleased(ctx) {
+			_ = tx.RollbackTo(savepoint).Error
+		}
+		return
+	}
 	_ = tx.Rollback().Error
 }
 
+// observe records the duration and the outcome of operation in DBConfig.Metrics.
+func (r *DBrepository[T]) observe(operation string, start time.Time, err *error) {
+	r.db.config.Metrics.ObserveOperation(operation, r.name, start, *err)
+}
+
 // Save is a combination function. If save value does not contain primary key,
 // it will execute Create, otherwise it will execute Update (with all fields).
-func (r *DBrepository[T]) Save(ctx context.Context, value T) error {
-	db := r.GetDBInstance(ctx)
-	err := db.Save(value).Error
+func (r *DBrepository[T]) Save(ctx context.Context, value T) (rErr error) {
+	defer r.observe("Save", time.Now(), &rErr)
+
+	err := r.db.runWithTenant(ctx, r.GetDBInstance(ctx), func(db *gorm.DB) error {
+		return db.Save(value).Error
+	})
 
 	return r.HandleSaveOrUpdateError(err)
 }
 
 // Create is a function that creates the resource in the database.
-func (r *DBrepository[T]) Create(ctx context.Context, value T) error {
-	db := r.GetDBInstance(ctx)
-	err := db.Create(value).Error
+func (r *DBrepository[T]) Create(ctx context.Context, value T) (rErr error) {
+	defer r.observe("Create", time.Now(), &rErr)
+
+	err := r.db.runWithTenant(ctx, r.GetDBInstance(ctx), func(db *gorm.DB) error {
+		return db.Create(value).Error
+	})
 
 	return r.HandleSaveOrUpdateError(err)
 }
@@ -113,13 +189,16 @@ func (r *DBrepository[T]) Create(ctx context.Context, value T) error {
 //	var obj Resource
 //	repository.FindByID(ctx, "an_ID", &obj)
 func (r *DBrepository[T]) FindByID(ctx context.Context, id string, dst any) error {
-	db := r.GetDBInstance(ctx)
-	err := db.Where("id = ?", id).First(dst).Error
+	err := r.db.runWithTenant(ctx, r.GetReadDBInstance(ctx), func(db *gorm.DB) error {
+		return db.Where("id = ?", id).First(dst).Error
+	})
 
 	var tErr error
 	if err != nil {
 		if r.IsResourceNotFound(err) {
 			tErr = uerr.NewError(uerr.ResourceNotFoundError, "Resource not found.")
+		} else if rErr := r.db.config.GetDialect().MapError(err); rErr != nil {
+			tErr = rErr
 		} else {
 			tErr = uerr.NewError(uerr.GenericError, "Error finding resource by id.").WithCause(err)
 		}
@@ -128,7 +207,8 @@ func (r *DBrepository[T]) FindByID(ctx context.Context, id string, dst any) erro
 	return tErr
 }
 
-// Find returns a list of elements matching the provided filters.
+// Find returns a list of elements matching the provided filters. If ctx was created
+// with udatabase.WithTotal, the Total of the page is counted with the same filters.
 // Usage:
 //
 //	type Resource struct {...}
@@ -154,13 +234,25 @@ func (r *DBrepository[T]) FindByID(ctx context.Context, id string, dst any) erro
 //	v.Add("field", "value to use to filter")
 //	v.Add("sort", "field")  // sort in ascending order
 //	v.Add("sort", "-field") // sort in descending order
-func (r *DBrepository[T]) Find(ctx context.Context, v url.Values) (*udatabase.ResourcePage[T], error) {
-	db := r.GetDBInstance(ctx)
+func (r *DBrepository[T]) Find(ctx context.Context, v url.Values) (rp *udatabase.ResourcePage[T], err error) {
+	defer r.observe("Find", time.Now(), &err)
+
+	err = r.db.runWithTenant(ctx, r.GetReadDBInstance(ctx), func(db *gorm.DB) error {
+		rp, err = r.find(ctx, db, v)
+		return err
+	})
+	return rp, err
+}
 
+func (r *DBrepository[T]) find(ctx context.Context, db *gorm.DB, v url.Values) (*udatabase.ResourcePage[T], error) {
 	if v.Get("limit") == "" {
 		v.Add("limit", fmt.Sprintf("%d", filters.DefaultLimit))
 	}
 
+	if err := uormFilters.CheckConstraints(r.filters, v); err != nil {
+		return nil, err
+	}
+
 	rp := &udatabase.ResourcePage[T]{}
 	for key, values := range v {
 		if len(values) == 0 {
@@ -180,21 +272,177 @@ func (r *DBrepository[T]) Find(ctx context.Context, v url.Values) (*udatabase.Re
 		}
 	}
 
+	return r.findPage(ctx, db, rp)
+}
+
+// FindCursor returns a list of elements matching the provided filters like Find, but it paginates
+// by keyset instead of by offset: the "cursor" value of v is the NextCursor or PrevCursor of the
+// previous page, and rows are sorted by the sort of v followed by the unique key of its sorter
+// (see uormFilters.KeysetSorter). The columns of the sort must not be null.
+// Usage:
+//
+//	page, err := repository.FindCursor(ctx, url.Values{"sort": {"-created_at"}, "limit": {"20"}})
+//	page, err = repository.FindCursor(ctx, url.Values{"sort": {"-created_at"}, "cursor": {page.NextCursor}})
+func (r *DBrepository[T]) FindCursor(ctx context.Context, v url.Values) (cp *udatabase.CursorPage[T], err error) {
+	defer r.observe("FindCursor", time.Now(), &err)
+
+	err = r.db.runWithTenant(ctx, r.GetReadDBInstance(ctx), func(db *gorm.DB) error {
+		cp, err = r.findCursor(ctx, db, v)
+		return err
+	})
+	return cp, err
+}
+
+func (r *DBrepository[T]) findCursor(ctx context.Context, db *gorm.DB, v url.Values) (*udatabase.CursorPage[T],
+	error) {
+	if _, ok := v["offset"]; ok {
+		return nil, uerr.NewError(uerr.WrongInputParameterError, `"offset" cannot be used with cursor pagination.`)
+	}
+
+	// The limit filter sets the limit of the page, which is exceeded below by one row to know if
+	// there are more rows.
+	rp := &udatabase.ResourcePage[T]{Limit: filters.DefaultLimit}
+	db, err := r.filters["limit"].Apply(db, v["limit"], rp)
+	if err != nil {
+		return nil, err
+	}
+	v.Del("limit")
+
+	keys, err := r.sortKeys(v)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := udatabase.DecodeCursor(r.db.config.CursorKey(), v.Get("cursor"), sortValues(keys))
+	if err != nil {
+		return nil, err
+	}
+	v.Del("cursor")
+
+	if err = uormFilters.CheckConstraints(r.filters, v); err != nil {
+		return nil, err
+	}
+
+	for key, values := range v {
+		if len(values) == 0 {
+			continue
+		}
+
+		filter, ok := r.filters[key]
+		if !ok {
+			rErr := uerr.NewError(uerr.WrongInputParameterError, fmt.Sprintf("Invalid filter %q.", key))
+			return nil, rErr
+		}
+
+		db, err = filter.Apply(db, values, rp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	backward := false
+	if cursor != nil {
+		keyset, args, kErr := filters.ApplyKeyset(keys, cursor.Values, cursor.Backward)
+		if kErr != nil {
+			return nil, kErr
+		}
+		db = db.Where(keyset, args...)
+		backward = cursor.Backward
+	}
+
 	var dst []T
-	result := db.Find(&dst)
-	if result.Error != nil {
-		rErr := uerr.NewError(uerr.GenericError, "Error finding resources.").WithCause(result.Error)
-		return nil, rErr
+	tx := db.Order(filters.KeysetOrder(keys, backward)).Limit(int(rp.Limit) + 1).Find(&dst)
+	if tx.Error != nil {
+		return nil, r.handleFindError(tx.Error)
 	}
 
-	rp.Resources = dst
-	rp.Total = result.RowsAffected
-	return rp, nil
+	cp := &udatabase.CursorPage[T]{Limit: rp.Limit}
+	err = cp.SetResources(r.db.config.CursorKey(), dst, cursor, sortValues(keys),
+		r.keyValues(ctx, tx.Statement.Schema, keys))
+	if err != nil {
+		return nil, uerr.NewError(uerr.GenericError, "Error encoding cursor.").WithCause(err)
+	}
+	return cp, nil
+}
+
+// sortKeys returns the keys of the keyset sorters in v, which are removed from v, followed by the
+// unique keys of the rest of keyset sorters, or filters.DefaultUniqueKey if there are none.
+func (r *DBrepository[T]) sortKeys(v url.Values) ([]filters.SortKey, error) {
+	names := make([]string, 0, len(r.filters))
+	for name := range r.filters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var requested, unique [][]filters.SortKey
+	for _, name := range names {
+		sorter, ok := r.filters[name].(uormFilters.KeysetSorter[T])
+		if !ok {
+			continue
+		}
+
+		values, ok := v[name]
+		keys, err := sorter.Keys(values)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			requested = append(requested, keys)
+			v.Del(name)
+		} else {
+			unique = append(unique, keys)
+		}
+	}
+
+	keys := filters.MergeSortKeys(append(requested, unique...)...)
+	if len(keys) == 0 {
+		keys = []filters.SortKey{{Column: filters.DefaultUniqueKey}}
+	}
+	return keys, nil
+}
+
+// keyValues returns a function that returns the values of the fields of a resource mapped to the
+// columns of keys by sch.
+func (r *DBrepository[T]) keyValues(ctx context.Context, sch *schema.Schema,
+	keys []filters.SortKey) func(resource T) ([]any, error) {
+	return func(resource T) ([]any, error) {
+		rv := reflect.Indirect(reflect.ValueOf(resource))
+		if sch == nil || rv.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s is not a model", r.name)
+		}
+
+		values := make([]any, len(keys))
+		for i, k := range keys {
+			field := sch.LookUpField(k.Name())
+			if field == nil {
+				return nil, fmt.Errorf("sort column %q is not a field of %s", k.Name(), r.name)
+			}
+			values[i], _ = field.ValueOf(ctx, rv)
+		}
+		return values, nil
+	}
+}
+
+func sortValues(keys []filters.SortKey) []string {
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = k.String()
+	}
+	return values
 }
 
 func (r *DBrepository[T]) FindWithFilters(ctx context.Context,
+	fs ...uormFilters.ValuedFilter[T]) (rp *udatabase.ResourcePage[T], err error) {
+	err = r.db.runWithTenant(ctx, r.GetReadDBInstance(ctx), func(db *gorm.DB) error {
+		rp, err = r.findWithFilters(ctx, db, fs...)
+		return err
+	})
+	return rp, err
+}
+
+func (r *DBrepository[T]) findWithFilters(ctx context.Context, db *gorm.DB,
 	fs ...uormFilters.ValuedFilter[T]) (*udatabase.ResourcePage[T], error) {
-	db := r.GetDBInstance(ctx)
 	rp := &udatabase.ResourcePage[T]{}
 	for _, filter := range fs {
 		var err error
@@ -204,23 +452,48 @@ func (r *DBrepository[T]) FindWithFilters(ctx context.Context,
 		}
 	}
 
+	return r.findPage(ctx, db, rp)
+}
+
+// findPage finds the resources of rp with the filters applied to db. If ctx was created with
+// udatabase.WithTotal, the resources matching the filters are counted, without limit and offset.
+func (r *DBrepository[T]) findPage(ctx context.Context, db *gorm.DB,
+	rp *udatabase.ResourcePage[T]) (*udatabase.ResourcePage[T], error) {
 	var dst []T
-	result := db.Find(&dst)
-	if result.Error != nil {
-		rErr := uerr.NewError(uerr.GenericError, "Error finding resources.").WithCause(result.Error)
-		return nil, rErr
+	total := int64(-1)
+	if udatabase.CountTotal(ctx) {
+		// The session keeps the statement of db as it is for the Find below.
+		err := db.Session(&gorm.Session{}).Model(&dst).Limit(-1).Offset(-1).Count(&total).Error
+		if err != nil {
+			return nil, r.handleFindError(err)
+		}
+	}
+
+	err := db.Find(&dst).Error
+	if err != nil {
+		return nil, r.handleFindError(err)
 	}
 
-	rp.Resources = dst
-	rp.Total = result.RowsAffected
+	rp.SetResources(dst, total)
 	return rp, nil
 }
 
+func (r *DBrepository[T]) handleFindError(err error) error {
+	if rErr := r.db.config.GetDialect().MapError(err); rErr != nil {
+		return rErr
+	}
+	return uerr.NewError(uerr.GenericError, "Error finding resources.").WithCause(err)
+}
+
 func (r *DBrepository[T]) ParseFilters(v url.Values) ([]uormFilters.ValuedFilter[T], error) {
 	if v.Get("limit") == "" {
 		v.Add("limit", fmt.Sprintf("%d", filters.DefaultLimit))
 	}
 
+	if err := uormFilters.CheckConstraints(r.filters, v); err != nil {
+		return nil, err
+	}
+
 	var fs []uormFilters.ValuedFilter[T]
 	for k, values := range v {
 		if len(values) == 0 {
@@ -246,6 +519,7 @@ func (r *DBrepository[T]) IsResourceNotFound(err error) bool {
 
 // HandleSaveOrUpdateError in case of running an INSERT/UPDATE query, this method provides
 // an easy way of checking if the returned error is nil or if it violates a PRIMARY KEY/UNIQUE constraint.
+// Database errors are mapped to UError by the dialect of the DBConfig (udatabase.MapPgError for Postgres).
 func (r *DBrepository[T]) HandleSaveOrUpdateError(err error) error {
 	if err == nil {
 		return nil
@@ -255,18 +529,8 @@ func (r *DBrepository[T]) HandleSaveOrUpdateError(err error) error {
 		return uerr.NewError(uerr.ResourceAlreadyExistsError, "Resource already exists.").WithCause(err)
 	}
 
-	var pqErr *pq.Error
-	switch v := err.(type) {
-	case *pq.Error:
-		pqErr = v
-	case *pgconn.PgError:
-		pqErr = &pq.Error{Code: pq.ErrorCode(v.Code)}
-	}
-
-	if pqErr != nil {
-		if rErr, ok := udatabase.PqErrors[pqErr.Code.Name()]; ok {
-			return rErr.WithCause(err)
-		}
+	if rErr := r.db.config.GetDialect().MapError(err); rErr != nil {
+		return rErr
 	}
 
 	return uerr.NewError(uerr.GenericError, "Error saving or updating resource.").WithCause(err)
@@ -275,3 +539,9 @@ func (r *DBrepository[T]) HandleSaveOrUpdateError(err error) error {
 func (r *DBrepository[T]) GetDBInstance(ctx context.Context) *gorm.DB {
 	return r.db.GetDBInstance(ctx)
 }
+
+// GetReadDBInstance returns the transaction in ctx if there is one, otherwise a read replica
+// or the primary database if there are no replicas or ctx was created with udatabase.WithPrimary.
+func (r *DBrepository[T]) GetReadDBInstance(ctx context.Context) *gorm.DB {
+	return r.db.GetReadDBInstance(ctx)
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
 	uormFilters "github.com/carlosarismendi/utils/udatabase/uorm/filters"
 	"github.com/carlosarismendi/utils/uerr"
 	"github.com/stretchr/testify/require"
@@ -133,6 +134,51 @@ func TestTransactions(t *testing.T) {
 		}()
 		require.Error(t, err)
 	})
+	t.Run("committingNestedTransactionWithDeferredRollback_keepsOuterTransaction", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+		ctx := dbHolder.BeginTestTx(t)
+
+		resource := Resource{
+			ID:           "0ea57dec-5e79-40dc-b971-a52561fcc2c7",
+			Name:         "Resource name",
+			RandomNumber: 4,
+		}
+
+		// ACT
+		err := func() error {
+			outerCtx, err := r.Begin(ctx)
+			if err != nil {
+				return err
+			}
+			defer r.Rollback(outerCtx)
+
+			err = func() error {
+				innerCtx, err := r.Begin(outerCtx)
+				if err != nil {
+					return err
+				}
+				defer r.Rollback(innerCtx)
+
+				if err = r.Save(innerCtx, &resource); err != nil {
+					return err
+				}
+				return r.Commit(innerCtx)
+			}()
+			if err != nil {
+				return err
+			}
+			return r.Commit(outerCtx)
+		}()
+
+		// ASSERT
+		require.NoError(t, err)
+		var actual Resource
+		err = r.FindByID(ctx, resource.ID, &actual)
+		require.NoError(t, err)
+		require.Equal(t, resource.Name, actual.Name)
+	})
 }
 
 func TestSave(t *testing.T) {
@@ -293,6 +339,70 @@ func TestFindWithFilters(t *testing.T) {
 	}
 }
 
+type Product struct {
+	ID string
+}
+
+func TestFindWithJSONAndArrayFields(t *testing.T) {
+	// ARRANGE
+	dbHolder := NewTestDBHolder("db_orm_repository_test_json_array")
+	dbHolder.Reset()
+
+	r := NewDBRepository[*Product](dbHolder.DBHolder, map[string]uormFilters.Filter[*Product]{
+		"attributes": uormFilters.JSONField[*Product]("attributes"),
+		"color":      uormFilters.JSONField[*Product]("attributes").WithKey("color"),
+		"unit":       uormFilters.JSONField[*Product]("attributes").WithKey("size", "unit"),
+		"path":       uormFilters.JSONField[*Product]("attributes").WithMode(filters.JSONPathExists),
+		"tag":        uormFilters.ArrayField[*Product]("tags"),
+		"tags":       uormFilters.ArrayField[*Product]("tags").WithMode(filters.ArrayContains),
+		"sizes": uormFilters.ArrayField[*Product]("sizes").WithMode(filters.ArrayOverlaps).
+			WithElementType("integer"),
+		"sort": uormFilters.Sorter[*Product]("id"),
+	})
+	db := r.GetDBInstance(context.Background())
+	require.NoError(t, db.Exec("CREATE TABLE products (id TEXT PRIMARY KEY, attributes JSONB, tags TEXT[], "+
+		"sizes INTEGER[])").Error)
+	err := db.Exec(`INSERT INTO products VALUES
+		('p1', '{"color": "red", "size": {"value": 10, "unit": "cm"}}', '{new,sale}', '{1,2}'),
+		('p2', '{"color": "blue", "size": {"value": 3, "unit": "mm"}}', '{sale}', '{3}'),
+		('p3', NULL, NULL, NULL)`).Error
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		v        url.Values
+		expected []string
+	}{
+		{name: "JSONContains", v: url.Values{"attributes": {`{"size": {"unit": "cm"}}`}}, expected: []string{"p1"}},
+		{name: "JSONKeyEquals", v: url.Values{"color": {"red", "blue"}}, expected: []string{"p1", "p2"}},
+		{name: "JSONNestedKeyNotEqual", v: url.Values{"unit": {"ne:cm"}}, expected: []string{"p2"}},
+		{name: "JSONPathExists", v: url.Values{"path": {"$.size.value ? (@ > 5)"}}, expected: []string{"p1"}},
+		{name: "JSONNull", v: url.Values{"attributes": {"null"}}, expected: []string{"p3"}},
+		{name: "ArrayAny", v: url.Values{"tag": {"new"}}, expected: []string{"p1"}},
+		{name: "ArrayContains", v: url.Values{"tags": {"sale", "new"}}, expected: []string{"p1"}},
+		{name: "ArrayNotAny", v: url.Values{"tags": {"ne:new"}}, expected: []string{"p2"}},
+		{name: "ArrayOverlapsIntegers", v: url.Values{"sizes": {"2", "3"}}, expected: []string{"p1", "p2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ARRANGE
+			tt.v.Set("sort", "id")
+
+			// ACT
+			rp, err := r.Find(context.Background(), tt.v)
+
+			// ASSERT
+			require.NoError(t, err)
+			ids := make([]string, 0, len(rp.Resources))
+			for _, p := range rp.Resources {
+				ids = append(ids, p.ID)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
 func TestFindWithValuedFilters(t *testing.T) {
 	dbHolder := NewTestDBHolder("db_orm_repository_test_find")
 	dbHolder.Reset()
@@ -524,6 +634,40 @@ func TestFindWithSorters(t *testing.T) {
 	}
 }
 
+func TestDescribe(t *testing.T) {
+	// ARRANGE
+	filtersMap := map[string]uormFilters.Filter[*Resource]{
+		"name":        uormFilters.TextField[*Resource]("name").WithConstraints(filters.Constraints{Required: true}),
+		"random_bool": uormFilters.BoolField[*Resource]("random_bool"),
+		"limit":       uormFilters.Limit[*Resource](5).WithMax(20),
+		"sort":        uormFilters.Sorter[*Resource]("name"),
+	}
+	filtersMap[filters.ExpressionParam] = uormFilters.Expression[*Resource](filtersMap)
+	r := NewDBRepository[*Resource](nil, filtersMap)
+
+	// ACT
+	descriptions := r.Describe()
+
+	// ASSERT
+	names := make([]string, 0, len(descriptions))
+	for _, d := range descriptions {
+		names = append(names, d.Name)
+	}
+	require.Equal(t, []string{"filter", "limit", "name", "offset", "random_bool", "sort"}, names)
+	require.Equal(t, filters.ExpressionValue, descriptions[0].Type)
+	require.Equal(t, "5", descriptions[1].Default)
+	require.Equal(t, 20.0, *descriptions[1].Constraints.Max)
+	require.Equal(t, filters.TextValue, descriptions[2].Type)
+	require.True(t, descriptions[2].Constraints.Required)
+	require.Equal(t, filters.OffsetValue, descriptions[3].Type)
+	require.Equal(t, filters.BooleanValue, descriptions[4].Type)
+	require.Equal(t, []string{"name"}, descriptions[5].SortFields)
+
+	params := filters.OpenAPIParameters(descriptions)
+	require.Equal(t, "name", params[2].Name)
+	require.True(t, params[2].Required)
+}
+
 func populateDB(ctx context.Context, t testing.TB, r *DBrepository[*Resource]) (r1, r2, r3, r4 *Resource) {
 	r1 = &Resource{
 		ID:           "5ceff18d-9039-44b5-a5d3-3d99653f4601",
@@ -0,0 +1,80 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"github.com/carlosarismendi/utils/udatabase/ident"
+	"gorm.io/gorm"
+)
+
+type ArrayFieldFilter[T any] struct {
+	field       string
+	opts        filters.ArrayOptions
+	constraints filters.Constraints
+}
+
+// ArrayField returns a filter of a Postgres array column, see filters.ApplyArrayField.
+func ArrayField[T any](field string) *ArrayFieldFilter[T] {
+	return &ArrayFieldFilter[T]{
+		field: field,
+	}
+}
+
+// WithMode sets how the filter matches the column with the values. It is filters.ArrayAny by default.
+func (f *ArrayFieldFilter[T]) WithMode(mode filters.ArrayMode) *ArrayFieldFilter[T] {
+	f.opts.Mode = mode
+	return f
+}
+
+// WithElementType sets the type of the elements of the column, e.g. integer for integer[]. It is
+// filters.DefaultArrayElementType by default. It panics if elementType is not a valid identifier.
+func (f *ArrayFieldFilter[T]) WithElementType(elementType string) *ArrayFieldFilter[T] {
+	if err := ident.Validate(elementType); err != nil {
+		panic(err)
+	}
+	f.opts.ElementType = elementType
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *ArrayFieldFilter[T]) WithConstraints(constraints filters.Constraints) *ArrayFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *ArrayFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *ArrayFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.ArrayValue,
+		Operators:   filters.EqualityOperators,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *ArrayFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+	return f.arrayField(db, values...)
+}
+
+func (f *ArrayFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
+	return func(db *gorm.DB, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+		return f.arrayField(db, values...)
+	}
+}
+
+func (f *ArrayFieldFilter[T]) arrayField(db *gorm.DB, values ...string) (*gorm.DB, error) {
+	query, args, err := filters.ApplyArrayField(f.field, f.opts, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Where(query, args...), nil
+}
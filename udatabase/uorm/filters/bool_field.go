@@ -7,7 +7,8 @@ import (
 )
 
 type BoolFieldFilter[T any] struct {
-	field string
+	field       string
+	constraints filters.Constraints
 }
 
 func BoolField[T any](field string) *BoolFieldFilter[T] {
@@ -16,6 +17,30 @@ func BoolField[T any](field string) *BoolFieldFilter[T] {
 	}
 }
 
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *BoolFieldFilter[T]) WithConstraints(constraints filters.Constraints) *BoolFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *BoolFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.BoolOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *BoolFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.BooleanValue,
+		Operators:   filters.BoolOperators,
+		Constraints: f.constraints,
+	}
+}
+
 func (f *BoolFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
 	return f.BoolField(db, values...)
 }
@@ -0,0 +1,69 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"gorm.io/gorm"
+)
+
+type EnumFieldFilter[T any] struct {
+	field       string
+	allowed     []string
+	constraints filters.Constraints
+}
+
+// EnumField returns a filter of a column whose values must be one of allowed, see
+// filters.ApplyEnumField. It panics if there are no allowed values.
+func EnumField[T any](field string, allowed ...string) *EnumFieldFilter[T] {
+	if len(allowed) == 0 {
+		panic("EnumField requires at least one allowed value")
+	}
+	return &EnumFieldFilter[T]{
+		field:   field,
+		allowed: allowed,
+	}
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *EnumFieldFilter[T]) WithConstraints(constraints filters.Constraints) *EnumFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *EnumFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *EnumFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.EnumValue,
+		Operators:   filters.EqualityOperators,
+		Enum:        f.allowed,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *EnumFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+	return f.enumField(db, values...)
+}
+
+func (f *EnumFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
+	return func(db *gorm.DB, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+		return f.enumField(db, values...)
+	}
+}
+
+func (f *EnumFieldFilter[T]) enumField(db *gorm.DB, values ...string) (*gorm.DB, error) {
+	query, args, err := filters.ApplyEnumField(f.field, f.allowed, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Where(query, args...), nil
+}
@@ -0,0 +1,154 @@
+package filters
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExpressionFilter[T any] struct {
+	filters     map[string]Filter[T]
+	constraints filters.Constraints
+}
+
+// Expression returns a filter of filter expressions, see filters.ParseExpression, whose conditions
+// apply the filters of filtersMap, e.g. ?filter=status=open OR assignee=me, after checking their
+// constraints (see ConstrainedFilter) with all of their values in the expressions. Only filters
+// that add conditions, and nothing else, can be used in the expressions. Register it in filtersMap
+// as filters.ExpressionParam to accept expressions.
+func Expression[T any](filtersMap map[string]Filter[T]) *ExpressionFilter[T] {
+	return &ExpressionFilter[T]{
+		filters: filtersMap,
+	}
+}
+
+// WithConstraints sets the constraints that the expressions must meet, see filters.Constraints,
+// e.g. MaxValues limits how many times the parameter can be repeated.
+// It panics if constraints are not valid.
+func (f *ExpressionFilter[T]) WithConstraints(constraints filters.Constraints) *ExpressionFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *ExpressionFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, nil, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *ExpressionFilter[T]) Describe() filters.Description {
+	return filters.Description{Type: filters.ExpressionValue, Constraints: f.constraints}
+}
+
+func (f *ExpressionFilter[T]) Apply(db *gorm.DB, values []string, rp *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+	return f.expression(db, rp, values...)
+}
+
+func (f *ExpressionFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
+	return func(db *gorm.DB, rp *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+		return f.expression(db, rp, values...)
+	}
+}
+
+// expression adds the condition of the expressions of values, which must all match, to db.
+func (f *ExpressionFilter[T]) expression(db *gorm.DB, rp *udatabase.ResourcePage[T],
+	values ...string) (*gorm.DB, error) {
+	exprs, err := filters.ParseExpressions(values)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = f.checkConstraints(exprs); err != nil {
+		return nil, err
+	}
+
+	for _, e := range exprs {
+		cond, err := f.compile(db, rp, e)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where(cond)
+	}
+	return db, nil
+}
+
+// checkConstraints returns a WrongInputParameterError with the violations of the constraints of
+// the filters of the conditions of e by all of their values in e, see filters.ConditionValues.
+func (f *ExpressionFilter[T]) checkConstraints(e filters.Expr) error {
+	values := filters.ConditionValues(e)
+	var violations []string
+	for _, field := range slices.Sorted(maps.Keys(values)) {
+		filter, ok := f.filters[field].(ConstrainedFilter[T])
+		if _, nested := filter.(*ExpressionFilter[T]); ok && !nested {
+			violations = append(violations, filter.CheckConstraints(field, values[field])...)
+		}
+	}
+	return filters.ConstraintsError(violations)
+}
+
+// compile returns the clause of e. The conditions of its filters are those they add to a new
+// statement, which are nested in the clause as variables, so GORM builds them with the
+// placeholders of the dialect.
+func (f *ExpressionFilter[T]) compile(db *gorm.DB, rp *udatabase.ResourcePage[T],
+	e filters.Expr) (clause.Expression, error) {
+	switch e := e.(type) {
+	case filters.Condition:
+		return f.condition(db, rp, e)
+	case filters.Not:
+		cond, err := f.compile(db, rp, e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return clause.Expr{SQL: "(NOT ?)", Vars: []interface{}{cond}}, nil
+	case filters.And:
+		return f.compileAll(db, rp, e, " AND ")
+	case filters.Or:
+		return f.compileAll(db, rp, e, " OR ")
+	default:
+		return nil, fmt.Errorf("unknown expression %T", e)
+	}
+}
+
+func (f *ExpressionFilter[T]) compileAll(db *gorm.DB, rp *udatabase.ResourcePage[T], exprs []filters.Expr,
+	sep string) (clause.Expression, error) {
+	vars := make([]interface{}, 0, len(exprs))
+	placeholders := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		cond, err := f.compile(db, rp, e)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, cond)
+		placeholders = append(placeholders, "?")
+	}
+	return clause.Expr{SQL: "(" + strings.Join(placeholders, sep) + ")", Vars: vars}, nil
+}
+
+func (f *ExpressionFilter[T]) condition(db *gorm.DB, rp *udatabase.ResourcePage[T],
+	c filters.Condition) (clause.Expression, error) {
+	filter, ok := f.filters[c.Field]
+	if _, nested := filter.(*ExpressionFilter[T]); !ok || nested {
+		return nil, filters.InvalidExpressionFilterError(c.Field)
+	}
+
+	tx, err := filter.Apply(db.Session(&gorm.Session{NewDB: true}), []string{c.Value}, rp)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filters such as limit or sorters set other clauses, which cannot be part of a condition.
+	where, ok := tx.Statement.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok || len(where.Exprs) == 0 || len(tx.Statement.Clauses) > 1 {
+		return nil, filters.InvalidExpressionFilterError(c.Field)
+	}
+	return clause.And(where.Exprs...), nil
+}
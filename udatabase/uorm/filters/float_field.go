@@ -0,0 +1,84 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"gorm.io/gorm"
+)
+
+type FloatFieldFilter[T any] struct {
+	field       string
+	opts        filters.FloatOptions
+	constraints filters.Constraints
+}
+
+// FloatField returns a filter of a floating point or decimal column, see filters.ApplyFloatField.
+func FloatField[T any](field string) *FloatFieldFilter[T] {
+	return &FloatFieldFilter[T]{
+		field: field,
+	}
+}
+
+// WithOperators sets the operators allowed in the values of the filter, e.g. gte:9.99.
+// They are filters.NumOperators by default.
+func (f *FloatFieldFilter[T]) WithOperators(operators ...filters.Operator) *FloatFieldFilter[T] {
+	f.opts.Operators = operators
+	return f
+}
+
+// WithDecimal passes the values to the query as decimal strings, so that numeric columns compare
+// them without rounding errors. Use it for numeric and decimal columns.
+func (f *FloatFieldFilter[T]) WithDecimal() *FloatFieldFilter[T] {
+	f.opts.Decimal = true
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *FloatFieldFilter[T]) WithConstraints(constraints filters.Constraints) *FloatFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *FloatFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, f.operators(), values)
+}
+
+func (f *FloatFieldFilter[T]) operators() []filters.Operator {
+	if len(f.opts.Operators) == 0 {
+		return filters.NumOperators
+	}
+	return f.opts.Operators
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *FloatFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.NumberValue,
+		Operators:   f.operators(),
+		Constraints: f.constraints,
+	}
+}
+
+func (f *FloatFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+	return f.floatField(db, values...)
+}
+
+func (f *FloatFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
+	return func(db *gorm.DB, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+		return f.floatField(db, values...)
+	}
+}
+
+func (f *FloatFieldFilter[T]) floatField(db *gorm.DB, values ...string) (*gorm.DB, error) {
+	query, args, err := filters.ApplyFloatField(f.field, f.opts, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Where(query, args...), nil
+}
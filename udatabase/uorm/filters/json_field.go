@@ -0,0 +1,81 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"gorm.io/gorm"
+)
+
+type JSONFieldFilter[T any] struct {
+	field       string
+	opts        filters.JSONOptions
+	constraints filters.Constraints
+}
+
+// JSONField returns a filter of a Postgres jsonb column, see filters.ApplyJSONField. It matches
+// the columns that contain the values, which are JSON documents, unless it has another mode.
+func JSONField[T any](field string) *JSONFieldFilter[T] {
+	return &JSONFieldFilter[T]{
+		field: field,
+	}
+}
+
+// WithMode sets how the filter matches the column with the values. It is filters.JSONContains by default.
+func (f *JSONFieldFilter[T]) WithMode(mode filters.JSONMode) *JSONFieldFilter[T] {
+	f.opts.Mode = mode
+	return f
+}
+
+// WithKey makes the filter compare the text of the key of the path of object keys with the
+// values, e.g. WithKey("size", "unit") for attributes->'size'->>'unit'. It panics if key is empty.
+func (f *JSONFieldFilter[T]) WithKey(key ...string) *JSONFieldFilter[T] {
+	if len(key) == 0 {
+		panic("WithKey requires at least one key")
+	}
+	f.opts.Mode = filters.JSONKeyEquals
+	f.opts.Key = key
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *JSONFieldFilter[T]) WithConstraints(constraints filters.Constraints) *JSONFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *JSONFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *JSONFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.JSONValue,
+		Operators:   filters.EqualityOperators,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *JSONFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+	return f.jsonField(db, values...)
+}
+
+func (f *JSONFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
+	return func(db *gorm.DB, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+		return f.jsonField(db, values...)
+	}
+}
+
+func (f *JSONFieldFilter[T]) jsonField(db *gorm.DB, values ...string) (*gorm.DB, error) {
+	query, args, err := filters.ApplyJSONField(f.field, f.opts, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Where(query, args...), nil
+}
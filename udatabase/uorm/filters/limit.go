@@ -2,24 +2,41 @@ package filters
 
 import (
 	"github.com/carlosarismendi/utils/udatabase"
-	"github.com/carlosarismendi/utils/uerr"
+	"github.com/carlosarismendi/utils/udatabase/filters"
 	"gorm.io/gorm"
-	"strconv"
 )
 
 type LimitFilter[T any] struct {
 	defaultValue int
+	maxValue     int
 }
 
+// Limit returns the filter of the "limit" of pages, which is defaultValue when not set, and at
+// most filters.DefaultMaxLimit, or defaultValue if it is greater, unless WithMax sets another.
 func Limit[T any](defaultValue int) *LimitFilter[T] {
 	if defaultValue < 1 {
 		panic("Limit defaultValue must be greater than 0")
 	}
 	return &LimitFilter[T]{
 		defaultValue: defaultValue,
+		maxValue:     max(defaultValue, filters.DefaultMaxLimit),
 	}
 }
 
+// WithMax sets the maximum limit. It panics if maxValue is less than the default value.
+func (f *LimitFilter[T]) WithMax(maxValue int) *LimitFilter[T] {
+	if maxValue < f.defaultValue {
+		panic("Limit maxValue must be greater than or equal to defaultValue")
+	}
+	f.maxValue = maxValue
+	return f
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *LimitFilter[T]) Describe() filters.Description {
+	return filters.DescribeLimit(int64(f.defaultValue), int64(f.maxValue))
+}
+
 func (f *LimitFilter[T]) Apply(db *gorm.DB, values []string, rp *udatabase.ResourcePage[T]) (*gorm.DB, error) {
 	return f.limit(db, rp, values...)
 }
@@ -36,19 +53,11 @@ func (f *LimitFilter[T]) limit(db *gorm.DB, rp *udatabase.ResourcePage[T], value
 		return db.Limit(f.defaultValue), nil
 	}
 
-	num, err := strconv.Atoi(values[0])
+	num, err := filters.ParseLimit(values[0], int64(f.maxValue))
 	if err != nil {
-		rErr := uerr.NewError(uerr.WrongInputParameterError,
-			`Invalid value for "limit". It must be a number.`).WithCause(err)
-		return nil, rErr
-	}
-
-	if num < 1 {
-		rErr := uerr.NewError(uerr.WrongInputParameterError,
-			`Invalid value for "limit". It must be greater than 0.`)
-		return nil, rErr
+		return nil, err
 	}
 
-	rp.Limit = int64(num)
-	return db.Limit(num), nil
+	rp.Limit = num
+	return db.Limit(int(num)), nil
 }
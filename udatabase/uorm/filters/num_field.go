@@ -7,12 +7,46 @@ import (
 )
 
 type NumFieldFilter[T any] struct {
-	field string
+	field       string
+	operators   []filters.Operator
+	constraints filters.Constraints
 }
 
 func NumField[T any](field string) *NumFieldFilter[T] {
 	return &NumFieldFilter[T]{
-		field: field,
+		field:     field,
+		operators: filters.NumOperators,
+	}
+}
+
+// WithOperators sets the operators allowed in the values of the filter, e.g. gte:10.
+// They are filters.NumOperators by default.
+func (f *NumFieldFilter[T]) WithOperators(operators ...filters.Operator) *NumFieldFilter[T] {
+	f.operators = operators
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *NumFieldFilter[T]) WithConstraints(constraints filters.Constraints) *NumFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *NumFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, f.operators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *NumFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.IntegerValue,
+		Operators:   f.operators,
+		Constraints: f.constraints,
 	}
 }
 
@@ -27,7 +61,7 @@ func (f *NumFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
 }
 
 func (f *NumFieldFilter[T]) numField(db *gorm.DB, values ...string) (*gorm.DB, error) {
-	query, args, err := filters.ApplyNumField(f.field, values...)
+	query, args, err := filters.ApplyNumFieldWithOperators(f.field, f.operators, values...)
 	if err != nil {
 		return nil, err
 	}
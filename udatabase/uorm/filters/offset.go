@@ -2,6 +2,7 @@ package filters
 
 import (
 	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
 	"github.com/carlosarismendi/utils/uerr"
 	"gorm.io/gorm"
 	"strconv"
@@ -14,6 +15,11 @@ func Offset[T any]() *OffsetFilter[T] {
 	return &OffsetFilter[T]{}
 }
 
+// Describe returns the description of the filter, see Describer.
+func (f *OffsetFilter[T]) Describe() filters.Description {
+	return filters.DescribeOffset()
+}
+
 func (f *OffsetFilter[T]) Apply(db *gorm.DB, values []string, rp *udatabase.ResourcePage[T]) (*gorm.DB, error) {
 	return f.offset(db, rp, values...)
 }
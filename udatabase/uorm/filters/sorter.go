@@ -3,24 +3,50 @@ package filters
 import (
 	"github.com/carlosarismendi/utils/udatabase"
 	"github.com/carlosarismendi/utils/udatabase/filters"
+	"github.com/carlosarismendi/utils/udatabase/ident"
 	"gorm.io/gorm"
 )
 
 type SorterFilter[T any] struct {
-	allowedFields map[string]bool
+	fields    filters.SortFields
+	uniqueKey string
 }
 
 func Sorter[T any](allowedFields ...string) *SorterFilter[T] {
-	fields := make(map[string]bool)
+	fields := make(filters.SortFields)
 	for _, f := range allowedFields {
-		fields[f] = true
+		fields[f] = f
 	}
 
 	return &SorterFilter[T]{
-		allowedFields: fields,
+		fields:    fields,
+		uniqueKey: filters.DefaultUniqueKey,
 	}
 }
 
+// WithAlias adds the sort field name, which sorts by the column or SQL expression expr, e.g.
+// WithAlias("user", "u.user_id") or WithAlias("name", "lower(name)"), so that the API does not
+// expose the schema. It panics if expr is not valid for ident.ValidateExpression.
+func (f *SorterFilter[T]) WithAlias(name, expr string) *SorterFilter[T] {
+	if err := ident.ValidateExpression(expr); err != nil {
+		panic(err)
+	}
+	f.fields[name] = expr
+	return f
+}
+
+// WithUniqueKey sets the unique and not null column that keyset paginations sort by after the
+// fields of the sort. It is filters.DefaultUniqueKey by default.
+func (f *SorterFilter[T]) WithUniqueKey(column string) *SorterFilter[T] {
+	f.uniqueKey = column
+	return f
+}
+
+// Describe returns the description of the sorter, see Describer.
+func (f *SorterFilter[T]) Describe() filters.Description {
+	return filters.DescribeSortFields(f.fields)
+}
+
 func (f *SorterFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
 	return f.sorter(db, values...)
 }
@@ -31,9 +57,13 @@ func (f *SorterFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
 	}
 }
 
+func (f *SorterFilter[T]) Keys(values []string) ([]filters.SortKey, error) {
+	return filters.SortFieldKeys(f.fields, f.uniqueKey, values...)
+}
+
 func (f *SorterFilter[T]) sorter(db *gorm.DB, values ...string) (*gorm.DB, error) {
 	for _, v := range values {
-		column, direction, err := filters.SortFieldAndDirection(f.allowedFields, v)
+		column, direction, err := filters.SortFieldExpressionAndDirection(f.fields, v)
 		if err != nil {
 			return nil, err
 		}
@@ -7,7 +7,9 @@ import (
 )
 
 type TextFieldFilter[T any] struct {
-	field string
+	field       string
+	opts        filters.TextOptions
+	constraints filters.Constraints
 }
 
 func TextField[T any](field string) *TextFieldFilter[T] {
@@ -16,6 +18,52 @@ func TextField[T any](field string) *TextFieldFilter[T] {
 	}
 }
 
+// WithMode sets how the filter compares the column with the values. It is filters.TextExact by default.
+func (f *TextFieldFilter[T]) WithMode(mode filters.TextMode) *TextFieldFilter[T] {
+	f.opts.Mode = mode
+	return f
+}
+
+// WithIgnoreCase makes the filter case-insensitive.
+func (f *TextFieldFilter[T]) WithIgnoreCase() *TextFieldFilter[T] {
+	f.opts.IgnoreCase = true
+	return f
+}
+
+// WithLanguage sets the text search configuration of filters.TextSearch, e.g. english.
+// It panics if language is not a valid unquoted identifier, see filters.ValidateTextSearchLanguage.
+func (f *TextFieldFilter[T]) WithLanguage(language string) *TextFieldFilter[T] {
+	if err := filters.ValidateTextSearchLanguage(language); err != nil {
+		panic(err)
+	}
+	f.opts.Language = language
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *TextFieldFilter[T]) WithConstraints(constraints filters.Constraints) *TextFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *TextFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.TextOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *TextFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.TextValue,
+		Operators:   filters.TextOperators,
+		Constraints: f.constraints,
+	}
+}
+
 func (f *TextFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
 	return f.textField(db, values)
 }
@@ -27,7 +75,7 @@ func (f *TextFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T]
 }
 
 func (f *TextFieldFilter[T]) textField(db *gorm.DB, values []string) (*gorm.DB, error) {
-	query, args, err := filters.ApplyTextField(f.field, values...)
+	query, args, err := filters.ApplyTextFieldWithOptions(f.field, f.opts, values...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,85 @@
+package filters
+
+import (
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"gorm.io/gorm"
+)
+
+type TimeFieldFilter[T any] struct {
+	field       string
+	opts        filters.TimeOptions
+	constraints filters.Constraints
+}
+
+// TimeField returns a filter of a timestamp or date column, see filters.ApplyTimeField.
+func TimeField[T any](field string) *TimeFieldFilter[T] {
+	return &TimeFieldFilter[T]{
+		field: field,
+	}
+}
+
+// WithOperators sets the operators allowed in the values of the filter, e.g. gte:2024-01-01.
+// They are filters.TimeOperators by default.
+func (f *TimeFieldFilter[T]) WithOperators(operators ...filters.Operator) *TimeFieldFilter[T] {
+	f.opts.Operators = operators
+	return f
+}
+
+// WithLocation sets the time zone of the values without offset, such as dates. It is UTC by default.
+func (f *TimeFieldFilter[T]) WithLocation(loc *time.Location) *TimeFieldFilter[T] {
+	f.opts.Location = loc
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *TimeFieldFilter[T]) WithConstraints(constraints filters.Constraints) *TimeFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *TimeFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, f.operators(), values)
+}
+
+func (f *TimeFieldFilter[T]) operators() []filters.Operator {
+	if len(f.opts.Operators) == 0 {
+		return filters.TimeOperators
+	}
+	return f.opts.Operators
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *TimeFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.TimeValue,
+		Operators:   f.operators(),
+		Constraints: f.constraints,
+	}
+}
+
+func (f *TimeFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+	return f.timeField(db, values...)
+}
+
+func (f *TimeFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
+	return func(db *gorm.DB, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+		return f.timeField(db, values...)
+	}
+}
+
+func (f *TimeFieldFilter[T]) timeField(db *gorm.DB, values ...string) (*gorm.DB, error) {
+	query, args, err := filters.ApplyTimeField(f.field, f.opts, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Where(query, args...), nil
+}
@@ -0,0 +1,62 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"gorm.io/gorm"
+)
+
+type UUIDFieldFilter[T any] struct {
+	field       string
+	constraints filters.Constraints
+}
+
+// UUIDField returns a filter of a UUID column, see filters.ApplyUUIDField.
+func UUIDField[T any](field string) *UUIDFieldFilter[T] {
+	return &UUIDFieldFilter[T]{
+		field: field,
+	}
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *UUIDFieldFilter[T]) WithConstraints(constraints filters.Constraints) *UUIDFieldFilter[T] {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *UUIDFieldFilter[T]) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *UUIDFieldFilter[T]) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.UUIDValue,
+		Operators:   filters.EqualityOperators,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *UUIDFieldFilter[T]) Apply(db *gorm.DB, values []string, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+	return f.uuidField(db, values...)
+}
+
+func (f *UUIDFieldFilter[T]) ValuedFilterFunc(values ...string) ValuedFilter[T] {
+	return func(db *gorm.DB, _ *udatabase.ResourcePage[T]) (*gorm.DB, error) {
+		return f.uuidField(db, values...)
+	}
+}
+
+func (f *UUIDFieldFilter[T]) uuidField(db *gorm.DB, values ...string) (*gorm.DB, error) {
+	query, args, err := filters.ApplyUUIDField(f.field, values...)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.Where(query, args...), nil
+}
@@ -0,0 +1,64 @@
+package uorm
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"gorm.io/gorm"
+)
+
+const observedQueryKey = "udatabase:observed_query"
+
+type observedQuery struct {
+	parent context.Context
+	start  time.Time
+}
+
+// registerObserver registers GORM callbacks that notify observer of every statement run by db.
+func registerObserver(db *gorm.DB, observer udatabase.QueryObserver) error {
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(observedQueryKey, observedQuery{parent: tx.Statement.Context, start: time.Now()})
+		tx.Statement.Context = observer.BeforeQuery(tx.Statement.Context)
+	}
+
+	after := func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(observedQueryKey)
+		if !ok {
+			return
+		}
+		q := v.(observedQuery)
+
+		err := tx.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+		observer.AfterQuery(tx.Statement.Context, udatabase.QueryEvent{
+			Query:    tx.Statement.SQL.String(),
+			Args:     tx.Statement.Vars,
+			Duration: time.Since(q.start),
+			Err:      err,
+		})
+
+		// The statement may be reused by the next query of a chain, which must not be
+		// run in the context of this one.
+		tx.Statement.Context = q.parent
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("udatabase:before_create", before),
+		cb.Create().After("gorm:create").Register("udatabase:after_create", after),
+		cb.Query().Before("gorm:query").Register("udatabase:before_query", before),
+		cb.Query().After("gorm:query").Register("udatabase:after_query", after),
+		cb.Update().Before("gorm:update").Register("udatabase:before_update", before),
+		cb.Update().After("gorm:update").Register("udatabase:after_update", after),
+		cb.Delete().Before("gorm:delete").Register("udatabase:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("udatabase:after_delete", after),
+		cb.Row().Before("gorm:row").Register("udatabase:before_row", before),
+		cb.Row().After("gorm:row").Register("udatabase:after_row", after),
+		cb.Raw().Before("gorm:raw").Register("udatabase:before_raw", before),
+		cb.Raw().After("gorm:raw").Register("udatabase:after_raw", after),
+	)
+}
@@ -0,0 +1,29 @@
+// Package sqlite provides the SQLite dialect of the GORM DBHolder. It is kept apart from
+// uorm so that only the programs that use SQLite link GORM's SQLite driver.
+package sqlite
+
+import (
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/sqlite"
+	gormSqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Dialect is the sqlite.Dialect with the GORM dialector that uorm.GormDialect requires.
+//
+// Usage:
+//
+//	cfg := &udatabase.DBConfig{SchemaName: "users_test", Dialect: uormSqlite.Dialect{}}
+//	dbHolder := uorm.NewTestDBHolderFromConfig(cfg)
+type Dialect struct {
+	sqlite.Dialect
+}
+
+// GormDialector returns GORM's SQLite dialector, which opens the connections with the
+// modernc.org/sqlite driver of the dialect instead of its default cgo driver.
+func (d Dialect) GormDialector(cfg *udatabase.DBConfig) gorm.Dialector {
+	return gormSqlite.New(gormSqlite.Config{
+		DriverName: d.DriverName(),
+		DSN:        d.ConnectionString(cfg),
+	})
+}
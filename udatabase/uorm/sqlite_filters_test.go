@@ -0,0 +1,295 @@
+package uorm
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	uormFilters "github.com/carlosarismendi/utils/udatabase/uorm/filters"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteRepository returns a repository with filtersMap of a new SQLite database populated with
+// the resources of populateDB, which are returned too.
+func newSQLiteRepository(t *testing.T, schemaName string, filtersMap map[string]uormFilters.Filter[*Resource]) (
+	r *DBrepository[*Resource], r1, r2, r3, r4 *Resource) {
+	dbHolder := newSQLiteTestDBHolder(t, schemaName)
+	dbHolder.Reset()
+	r = NewDBRepository[*Resource](dbHolder.DBHolder, filtersMap)
+	r1, r2, r3, r4 = populateDB(context.Background(), t, r)
+	return r, r1, r2, r3, r4
+}
+
+type sqliteFindTest struct {
+	name           string
+	v              url.Values
+	expected       []*Resource
+	expectedErrKey string
+}
+
+func (ft *sqliteFindTest) testFind(r *DBrepository[*Resource]) func(*testing.T) {
+	return func(t *testing.T) {
+		// ACT
+		rp, err := r.Find(context.Background(), ft.v)
+
+		// ASSERT
+		if ft.expectedErrKey != "" {
+			require.Error(t, err)
+			require.Equal(t, ft.expectedErrKey, uerr.GetKey(err))
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, ft.expected, rp.Resources)
+	}
+}
+
+type Event struct {
+	ID        string
+	CreatedAt time.Time
+}
+
+func TestSQLiteTimeField(t *testing.T) {
+	// ARRANGE
+	dbHolder := newSQLiteTestDBHolder(t, "db_orm_repository_test_sqlite_time")
+	dbHolder.Reset()
+	r := NewDBRepository[*Event](dbHolder.DBHolder, map[string]uormFilters.Filter[*Event]{
+		"created_at": uormFilters.TimeField[*Event]("created_at"),
+		"sort":       uormFilters.Sorter[*Event]("created_at"),
+	})
+	require.NoError(t, r.GetDBInstance(context.Background()).
+		Exec("CREATE TABLE events (id TEXT PRIMARY KEY, created_at TIMESTAMP NOT NULL)").Error)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	e1 := &Event{ID: "1", CreatedAt: now.AddDate(0, 0, -10)}
+	e2 := &Event{ID: "2", CreatedAt: now.AddDate(0, 0, -3)}
+	e3 := &Event{ID: "3", CreatedAt: now.Add(-time.Hour)}
+	for _, e := range []*Event{e1, e2, e3} {
+		require.NoError(t, r.Create(context.Background(), e))
+	}
+
+	t.Run("RelativeBound_returnsRecentEvents", func(t *testing.T) {
+		// ACT
+		rp, err := r.Find(context.Background(), url.Values{"created_at": {"gte:now-7d"}, "sort": {"created_at"}})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []string{e2.ID, e3.ID}, eventIDs(rp.Resources))
+	})
+
+	t.Run("BeforeDate_returnsOlderEvents", func(t *testing.T) {
+		// ACT
+		rp, err := r.Find(context.Background(),
+			url.Values{"created_at": {"lt:" + now.AddDate(0, 0, -5).Format("2006-01-02")}})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []string{e1.ID}, eventIDs(rp.Resources))
+	})
+}
+
+func eventIDs(events []*Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestSQLiteTextFieldModes(t *testing.T) {
+	r, _, r2, r3, r4 := newSQLiteRepository(t, "db_orm_repository_test_sqlite_text",
+		map[string]uormFilters.Filter[*Resource]{
+			"name":        uormFilters.TextField[*Resource]("name").WithIgnoreCase(),
+			"name_suffix": uormFilters.TextField[*Resource]("name").WithMode(filters.TextEndsWith),
+			"sort":        uormFilters.Sorter[*Resource]("random_number"),
+		})
+
+	tests := []sqliteFindTest{
+		{name: "IgnoringCase_returnsEqualResources", v: url.Values{"name": {"RESOURCE2"}},
+			expected: []*Resource{r2}},
+		{name: "EndsWith_returnsResourcesWithSuffix", v: url.Values{"name_suffix": {"3"}, "sort": {"random_number"}},
+			expected: []*Resource{r4, r3}},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testFind(r))
+	}
+}
+
+func TestSQLiteFieldTypes(t *testing.T) {
+	r, r1, r2, r3, r4 := newSQLiteRepository(t, "db_orm_repository_test_sqlite_types",
+		map[string]uormFilters.Filter[*Resource]{
+			"id":            uormFilters.UUIDField[*Resource]("id"),
+			"name":          uormFilters.EnumField[*Resource]("name", "Resource1", "Resource2", "Resource3"),
+			"random_number": uormFilters.FloatField[*Resource]("random_number"),
+			"sort":          uormFilters.Sorter[*Resource]("name", "random_number"),
+		})
+	sort := []string{"name", "random_number"}
+
+	tests := []sqliteFindTest{
+		{name: "UUIDs_returnsResourcesWithIDs", v: url.Values{"id": {strings.ToUpper(r1.ID), r3.ID}, "sort": sort},
+			expected: []*Resource{r1, r3}},
+		{name: "NotEnumValue_returnsOtherResources", v: url.Values{"name": {"ne:Resource3"}, "sort": sort},
+			expected: []*Resource{r1, r2}},
+		{name: "FloatRange_returnsResourcesInRange", v: url.Values{"random_number": {"gt:0.5", "lte:1.5"},
+			"sort": sort}, expected: []*Resource{r1}},
+		{name: "Floats_returnsEqualResources", v: url.Values{"random_number": {"0", "2.0"}, "sort": sort},
+			expected: []*Resource{r2, r4, r3}},
+		{name: "InvalidUUID_returnsWrongInputParameterError", v: url.Values{"id": {"1"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testFind(r))
+	}
+}
+
+func TestSQLiteFilterExpression(t *testing.T) {
+	filtersMap := map[string]uormFilters.Filter[*Resource]{
+		"name":          uormFilters.TextField[*Resource]("name"),
+		"random_number": uormFilters.NumField[*Resource]("random_number"),
+		"random_bool":   uormFilters.BoolField[*Resource]("random_bool"),
+		"sort":          uormFilters.Sorter[*Resource]("name", "random_number"),
+	}
+	filtersMap[filters.ExpressionParam] = uormFilters.Expression[*Resource](filtersMap)
+	r, r1, r2, r3, r4 := newSQLiteRepository(t, "db_orm_repository_test_sqlite_expression", filtersMap)
+	sort := []string{"name", "random_number"}
+
+	tests := []sqliteFindTest{
+		{name: "Or_returnsResourcesMatchingAnyCondition",
+			v:        url.Values{"filter": {"name=Resource1 OR random_number=0"}, "sort": sort},
+			expected: []*Resource{r1, r4}},
+		{name: "NestedGroupsAndNot_returnsMatchingResources",
+			v: url.Values{"filter": {"NOT (random_bool=true AND random_number=gte:2) AND random_number=lte:2"},
+				"sort": sort},
+			expected: []*Resource{r1, r2, r4}},
+		{name: "ExpressionAndFilters_mustAllMatch",
+			v:        url.Values{"filter": {"random_number=1 OR random_number=2"}, "name": {"Resource3"}, "sort": sort},
+			expected: []*Resource{r3}},
+	}
+	for _, expr := range []string{"sort=name OR name=Resource1", "limit=1", "unknown=1", "name=Resource1 OR"} {
+		tests = append(tests, sqliteFindTest{name: "Invalid_" + expr + "_returnsWrongInputParameterError",
+			v: url.Values{"filter": {expr}}, expectedErrKey: uerr.WrongInputParameterError})
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testFind(r))
+	}
+}
+
+func TestSQLiteAliases(t *testing.T) {
+	filtersMap := map[string]uormFilters.Filter[*Resource]{
+		"title": uormFilters.TextField[*Resource]("lower(name)"),
+		"score": uormFilters.NumField[*Resource]("random_number * 10"),
+		"sort": uormFilters.Sorter[*Resource]().
+			WithAlias("title", "lower(name)").
+			WithAlias("score", "random_number"),
+	}
+	filtersMap[filters.ExpressionParam] = uormFilters.Expression[*Resource](filtersMap)
+	r, r1, r2, r3, r4 := newSQLiteRepository(t, "db_orm_repository_test_sqlite_aliases", filtersMap)
+
+	tests := []sqliteFindTest{
+		{name: "ExpressionFilter_returnsResourcesMatchingExpression",
+			v: url.Values{"title": {"resource3"}, "sort": {"-score"}}, expected: []*Resource{r3, r4}},
+		{name: "ComputedFilter_returnsResourcesMatchingExpression",
+			v: url.Values{"score": {"gte:20"}, "sort": {"title"}}, expected: []*Resource{r2, r3}},
+		{name: "AliasSorts_returnsSortedResources",
+			v: url.Values{"sort": {"-title", "score"}}, expected: []*Resource{r4, r3, r2, r1}},
+		{name: "AliasesInFilterExpression_returnsMatchingResources",
+			v: url.Values{"filter": {"title=resource2 OR score=0"}, "sort": {"score"}}, expected: []*Resource{r4, r2}},
+		{name: "SortByColumnOfAlias_returnsWrongInputParameterError",
+			v: url.Values{"sort": {"random_number"}}, expectedErrKey: uerr.WrongInputParameterError},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testFind(r))
+	}
+}
+
+func TestSQLiteConstraints(t *testing.T) {
+	minNumber, maxNumber := 0.0, 10.0
+	filtersMap := map[string]uormFilters.Filter[*Resource]{
+		"name": uormFilters.TextField[*Resource]("name").WithConstraints(filters.Constraints{
+			Required: true,
+			Pattern:  regexp.MustCompile("^Resource"),
+		}),
+		"random_number": uormFilters.NumField[*Resource]("random_number").WithConstraints(filters.Constraints{
+			MaxValues: 2,
+			Min:       &minNumber,
+			Max:       &maxNumber,
+		}),
+		"limit": uormFilters.Limit[*Resource](filters.DefaultLimit).WithMax(20),
+		"sort":  uormFilters.Sorter[*Resource]("name", "random_number"),
+	}
+	filtersMap[filters.ExpressionParam] = uormFilters.Expression[*Resource](filtersMap).
+		WithConstraints(filters.Constraints{MaxValues: 1})
+	r, r1, _, r3, r4 := newSQLiteRepository(t, "db_orm_repository_test_sqlite_constraints", filtersMap)
+
+	tests := []sqliteFindTest{
+		{name: "ValuesMeetingConstraints_returnsResources",
+			v: url.Values{"name": {"Resource3"}, "random_number": {"lte:10"}, "limit": {"20"},
+				"sort": {"random_number"}},
+			expected: []*Resource{r4, r3}},
+		{name: "RequiredFilterInExpressionCondition_returnsResources",
+			v:        url.Values{"filter": {"name=Resource1 AND (random_number=0 OR random_number=1)"}},
+			expected: []*Resource{r1}},
+		{name: "RequiredFilterInOrExpression_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"name=Resource1 OR name=Resource0"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "RequiredFilterInNotExpression_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"NOT name=Resource0"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ValueOutOfRange_returnsWrongInputParameterError",
+			v:              url.Values{"name": {"Resource1"}, "random_number": {"11"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ValueNotMatching_returnsWrongInputParameterError",
+			v: url.Values{"name": {"Other"}}, expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ExpressionViolation_returnsWrongInputParameterError",
+			v:              url.Values{"name": {"Resource1"}, "filter": {"random_number=1 OR random_number=-1"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ExpressionOverMaxValues_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"random_number=1 OR random_number=2 OR (name=Resource1 AND random_number=3)"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "RepeatedExpressionOverMaxValues_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"name=Resource1", "name=Resource2"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "LimitOverMax_returnsWrongInputParameterError",
+			v: url.Values{"name": {"Resource1"}, "limit": {"21"}}, expectedErrKey: uerr.WrongInputParameterError},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testFind(r))
+	}
+
+	t.Run("ViolationsOfEveryFilter_returnsWrongInputParameterErrorWithAllOfThem", func(t *testing.T) {
+		// ARRANGE
+		v := url.Values{"random_number": {"1", "2", "3"}}
+
+		// ACT
+		_, err := r.Find(context.Background(), v)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		require.Equal(t, `Filter "name" is required.`+"\n"+
+			`Filter "random_number" has 3 values, but it accepts at most 2.`, uerr.GetMessage(err))
+	})
+
+	t.Run("ViolationsInExpression_returnsWrongInputParameterErrorWithAllOfThem", func(t *testing.T) {
+		// ARRANGE
+		v := url.Values{"filter": {"name=Other AND NOT (random_number=1 OR random_number=2 OR random_number=3)"}}
+
+		// ACT
+		_, err := r.Find(context.Background(), v)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		require.Equal(t, `Invalid value for filter "name". It must match "^Resource".`+"\n"+
+			`Filter "random_number" has 3 values, but it accepts at most 2.`, uerr.GetMessage(err))
+	})
+}
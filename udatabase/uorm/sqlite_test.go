@@ -0,0 +1,468 @@
+package uorm
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/sqlite"
+	uormFilters "github.com/carlosarismendi/utils/udatabase/uorm/filters"
+	uormSqlite "github.com/carlosarismendi/utils/udatabase/uorm/sqlite"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func newSQLiteTestDBHolder(t *testing.T, schemaName string) *TestDBHolder {
+	migrationsDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(migrationsDir, "1_create_resources.up.sql"),
+		[]byte("CREATE TABLE resources (id UUID PRIMARY KEY, name TEXT, random_number INTEGER, random_bool BOOLEAN);"),
+		0o600)
+	require.NoError(t, err)
+
+	dbHolder := NewTestDBHolderFromConfig(&udatabase.DBConfig{
+		SchemaName:    schemaName,
+		MigrationsDir: migrationsDir,
+		Dialect:       uormSqlite.Dialect{},
+	})
+	t.Cleanup(func() {
+		sdb, err := dbHolder.GetDBInstance(context.Background()).DB()
+		if err == nil {
+			_ = sdb.Close()
+		}
+	})
+	return dbHolder
+}
+
+func TestDialector(t *testing.T) {
+	t.Run("GormDialect_returnsItsDialector", func(t *testing.T) {
+		// ACT
+		d := dialector(&udatabase.DBConfig{SchemaName: "dialector", Dialect: uormSqlite.Dialect{}})
+
+		// ASSERT
+		require.Equal(t, "sqlite", d.Name())
+	})
+
+	t.Run("PostgresDialectPointer_returnsPostgresDialector", func(t *testing.T) {
+		// ACT
+		d := dialector(&udatabase.DBConfig{SchemaName: "dialector", Dialect: &udatabase.PostgresDialect{}})
+
+		// ASSERT
+		require.Equal(t, "postgres", d.Name())
+	})
+
+	t.Run("DialectWithoutGormDialector_panics", func(t *testing.T) {
+		// ACT
+		open := func() {
+			dialector(&udatabase.DBConfig{SchemaName: "dialector", Dialect: sqlite.Dialect{}})
+		}
+
+		// ASSERT
+		require.Panics(t, open)
+	})
+}
+
+func TestSQLiteDialect(t *testing.T) {
+	dbHolder := newSQLiteTestDBHolder(t, "db_orm_repository_test_sqlite")
+
+	filtersMap := map[string]uormFilters.Filter[*Resource]{
+		"name":          uormFilters.TextField[*Resource]("name"),
+		"random_number": uormFilters.NumField[*Resource]("random_number"),
+		"random_bool":   uormFilters.BoolField[*Resource]("random_bool"),
+		"sort":          uormFilters.Sorter[*Resource]("name", "random_number"),
+	}
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, filtersMap)
+
+	t.Run("FindWithFiltersAndSorters_returnsMatchingResources", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		_, _, r3, r4 := populateDB(context.Background(), t, r)
+		v := url.Values{"name": {"Resource3"}, "sort": {"random_number"}}
+
+		// ACT
+		rp, err := r.Find(context.Background(), v)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []*Resource{r4, r3}, rp.Resources)
+	})
+
+	t.Run("FindWithNumFieldOperators_returnsResourcesInRange", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		r1, r2, r3, _ := populateDB(context.Background(), t, r)
+		v := url.Values{"random_number": {"gt:0", "ne:5"}, "sort": {"name"}}
+
+		// ACT
+		rp, err := r.Find(context.Background(), v)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []*Resource{r1, r2, r3}, rp.Resources)
+	})
+
+	t.Run("FindWithNullAndNegation_returnsMatchingResources", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		r1, r2, r3, r4 := populateDB(context.Background(), t, r)
+		tests := []struct {
+			v        url.Values
+			expected []*Resource
+		}{
+			{v: url.Values{"random_bool": {"null"}}, expected: []*Resource{}},
+			{v: url.Values{"random_bool": {"ne:true"}, "sort": {"random_number"}}, expected: []*Resource{r4, r2}},
+			{v: url.Values{"random_number": {"ne:null", "ne:2"}, "sort": {"name"}}, expected: []*Resource{r1, r4}},
+			{v: url.Values{"name": {"ne:Resource3"}, "sort": {"name"}}, expected: []*Resource{r1, r2}},
+			{v: url.Values{"name": {"ne:null", "Resource3"}, "sort": {"random_number"}},
+				expected: []*Resource{r4, r3}},
+		}
+
+		for _, tt := range tests {
+			// ACT
+			rp, err := r.Find(context.Background(), tt.v)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, rp.Resources)
+		}
+	})
+
+	t.Run("FindWithTotal_countsEveryMatchingResource", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		populateDB(context.Background(), t, r)
+		v := url.Values{"sort": {"name"}, "limit": {"1"}, "offset": {"1"}}
+
+		// ACT
+		rp, err := r.Find(udatabase.WithTotal(context.Background()), v)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Len(t, rp.Resources, 1)
+		require.EqualValues(t, 4, rp.Total)
+		require.True(t, rp.HasMore)
+		require.EqualValues(t, 2, rp.NextOffset)
+	})
+
+	t.Run("FindWithTotalInLastPage_hasNoMore", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		populateDB(context.Background(), t, r)
+		v := url.Values{"limit": {"2"}, "offset": {"2"}}
+
+		// ACT
+		rp, err := r.Find(udatabase.WithTotal(context.Background()), v)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Len(t, rp.Resources, 2)
+		require.EqualValues(t, 4, rp.Total)
+		require.False(t, rp.HasMore)
+		require.Zero(t, rp.NextOffset)
+	})
+
+	t.Run("FindCursor_pagesForwardAndBackward", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		r1, r2, r3, r4 := populateDB(context.Background(), t, r)
+		ctx := context.Background()
+
+		// ACT
+		page1, err := r.FindCursor(ctx, url.Values{"sort": {"random_number"}, "limit": {"3"}})
+		require.NoError(t, err)
+		page2, err := r.FindCursor(ctx, url.Values{"sort": {"random_number"}, "limit": {"3"},
+			"cursor": {page1.NextCursor}})
+		require.NoError(t, err)
+		prevPage, err := r.FindCursor(ctx, url.Values{"sort": {"random_number"}, "limit": {"3"},
+			"cursor": {page2.PrevCursor}})
+		require.NoError(t, err)
+
+		// ASSERT
+		// r2 and r3 have the same random_number, so they are sorted by id.
+		require.Equal(t, []*Resource{r4, r1, r2}, page1.Resources)
+		require.Empty(t, page1.PrevCursor)
+
+		require.Equal(t, []*Resource{r3}, page2.Resources)
+		require.Empty(t, page2.NextCursor)
+
+		require.Equal(t, []*Resource{r4, r1, r2}, prevPage.Resources)
+		require.Empty(t, prevPage.PrevCursor)
+		require.NotEmpty(t, prevPage.NextCursor)
+	})
+
+	t.Run("FindCursorWithOffset_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, err := r.FindCursor(context.Background(), url.Values{"offset": {"1"}})
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("FindByID_returnsResource", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		r1, _, _, _ := populateDB(context.Background(), t, r)
+
+		// ACT
+		var actual Resource
+		err := r.FindByID(context.Background(), r1.ID, &actual)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, r1, &actual)
+	})
+
+	t.Run("CreatingDuplicatedPrimaryKey_returnsResourceAlreadyExistsError", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		r1, _, _, _ := populateDB(context.Background(), t, r)
+
+		// ACT
+		err := r.Create(context.Background(), r1)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.ResourceAlreadyExistsError, uerr.GetKey(err))
+	})
+
+	t.Run("RollingBackTransaction_discardsChanges", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+
+		// ACT
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		populateDB(ctx, t, r)
+		r.Rollback(ctx)
+
+		// ASSERT
+		rp, err := r.Find(context.Background(), url.Values{})
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+}
+
+func TestSQLiteBeginTestTx(t *testing.T) {
+	dbHolder := newSQLiteTestDBHolder(t, "db_orm_repository_test_sqlite_test_tx")
+	dbHolder.Reset()
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, nil)
+
+	r1 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1"}
+	r2 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4602", Name: "Resource2"}
+
+	t.Run("NestedTransactions_mapToSavepoints", func(t *testing.T) {
+		// ARRANGE
+		ctx := dbHolder.BeginTestTx(t)
+
+		// ACT
+		err := func() (rErr error) {
+			ctx, err := udatabase.BeginTx(ctx, r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+			return r.Create(ctx, r1)
+		}()
+		require.NoError(t, err)
+
+		err = func() (rErr error) {
+			ctx, err := udatabase.BeginTx(ctx, r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+			if err = r.Create(ctx, r2); err != nil {
+				return err
+			}
+			return uerr.NewError(uerr.GenericError, "Forced error.")
+		}()
+		require.Error(t, err)
+
+		// ASSERT
+		rp, err := r.Find(ctx, url.Values{})
+		require.NoError(t, err)
+		require.Equal(t, []*Resource{r1}, rp.Resources)
+	})
+
+	t.Run("NestedBeginOutsideTestTransaction_joinsTransaction", func(t *testing.T) {
+		// ARRANGE
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		defer r.Rollback(ctx)
+
+		// ACT
+		nestedCtx, err := r.Begin(ctx)
+
+		// ASSERT
+		require.NoError(t, err)
+		_, isSavepoint := udatabase.SavepointFromContext(nestedCtx)
+		require.False(t, isSavepoint)
+		require.Same(t, r.GetDBInstance(ctx), r.GetDBInstance(nestedCtx))
+	})
+
+	t.Run("TestTransaction_isRolledBackOnCleanup", func(t *testing.T) {
+		// ACT
+		rp, err := r.Find(context.Background(), url.Values{})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+}
+
+func TestSQLiteLoadFixtures(t *testing.T) {
+	dbHolder := newSQLiteTestDBHolder(t, "db_orm_repository_test_sqlite_fixtures")
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, nil)
+
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "resources.yaml")
+	require.NoError(t, os.WriteFile(fixture, []byte(`
+resources:
+  - id: 5ceff18d-9039-44b5-a5d3-3d99653f4601
+    name: Resource1
+    random_number: 1
+    random_bool: true
+`), 0o600))
+
+	t.Run("LoadingFixtures_insertsRows", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+
+		// ACT
+		err := dbHolder.LoadFixtures(fixture)
+
+		// ASSERT
+		require.NoError(t, err)
+		var actual Resource
+		require.NoError(t, r.FindByID(context.Background(), "5ceff18d-9039-44b5-a5d3-3d99653f4601", &actual))
+		require.Equal(t, "Resource1", actual.Name)
+	})
+
+	t.Run("FailingFixture_rollsBackEveryFixture", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+
+		// ACT
+		err := dbHolder.LoadFixtures(fixture, fixture)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.ResourceAlreadyExistsError, uerr.GetKey(err))
+		rp, err := r.Find(context.Background(), url.Values{})
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+
+	t.Run("RowsReferencingLaterTables_areInsertedAfterThem", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		err := dbHolder.GetDBInstance(context.Background()).
+			Exec("CREATE TABLE notes (id TEXT PRIMARY KEY, resource_id UUID NOT NULL REFERENCES resources (id))").Error
+		require.NoError(t, err)
+
+		notesFixture := filepath.Join(dir, "notes.yaml")
+		require.NoError(t, os.WriteFile(notesFixture, []byte(`
+notes:
+  - id: note1
+    resource_id: 5ceff18d-9039-44b5-a5d3-3d99653f4601
+`), 0o600))
+
+		// ACT
+		err = dbHolder.LoadFixtures(notesFixture, fixture)
+
+		// ASSERT
+		require.NoError(t, err)
+		var notes int64
+		require.NoError(t, dbHolder.GetDBInstance(context.Background()).Table("notes").Count(&notes).Error)
+		require.Equal(t, int64(1), notes)
+	})
+}
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []udatabase.QueryEvent
+}
+
+func (o *recordingObserver) BeforeQuery(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (o *recordingObserver) AfterQuery(_ context.Context, event udatabase.QueryEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func TestSQLiteQueryObserver(t *testing.T) {
+	// ARRANGE
+	migrationsDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(migrationsDir, "1_create_resources.up.sql"),
+		[]byte("CREATE TABLE resources (id UUID PRIMARY KEY, name TEXT, random_number INTEGER, random_bool BOOLEAN);"),
+		0o600)
+	require.NoError(t, err)
+
+	observer := &recordingObserver{}
+	dbHolder := NewTestDBHolderFromConfig(&udatabase.DBConfig{
+		SchemaName:    "db_orm_repository_test_sqlite_observer",
+		MigrationsDir: migrationsDir,
+		Dialect:       uormSqlite.Dialect{},
+		QueryObserver: observer,
+	})
+	t.Cleanup(func() {
+		if sdb, err := dbHolder.GetDBInstance(context.Background()).DB(); err == nil {
+			_ = sdb.Close()
+		}
+	})
+	dbHolder.Reset()
+
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, map[string]uormFilters.Filter[*Resource]{
+		"name": uormFilters.TextField[*Resource]("name"),
+	})
+
+	// ACT
+	_, err = r.Find(context.Background(), url.Values{"name": {"Resource1"}})
+
+	// ASSERT
+	require.NoError(t, err)
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	var filtered []udatabase.QueryEvent
+	for _, event := range observer.events {
+		if strings.HasPrefix(event.Query, "SELECT * FROM") {
+			filtered = append(filtered, event)
+		}
+	}
+	require.Len(t, filtered, 1)
+	require.Equal(t, []any{"Resource1"}, filtered[0].Args)
+	require.NoError(t, filtered[0].Err)
+}
+
+func TestSQLiteMetrics(t *testing.T) {
+	// ARRANGE
+	metrics := udatabase.NewMetrics()
+	dbHolder := newSQLiteTestDBHolder(t, "db_orm_repository_test_sqlite_metrics")
+	dbHolder.config.Metrics = metrics
+	dbHolder.Reset()
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, nil)
+	resource := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1"}
+
+	// ACT
+	require.NoError(t, r.Create(context.Background(), resource))
+	require.Error(t, r.Create(context.Background(), resource))
+
+	// ASSERT
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	require.Contains(t, body,
+		`udatabase_operation_duration_seconds_count{operation="Create",repository="uorm.Resource",outcome="ok"} 1`)
+	require.Contains(t, body, `udatabase_operation_duration_seconds_count{operation="Create",repository="uorm.Resource",`+
+		`outcome="ResourceAlreadyExistsError"} 1`)
+}
@@ -0,0 +1,37 @@
+package uorm
+
+import (
+	"context"
+
+	"github.com/carlosarismendi/utils/uerr"
+	"gorm.io/gorm"
+)
+
+const setLocalSearchPath = "SELECT set_config('search_path', ?, true)"
+
+// runWithTenant runs fn with db. If ctx has a tenant and no transaction, fn runs inside
+// a transaction whose search_path is the schema of the tenant, so the pooled connections
+// are never left pointing to it.
+func (d *DBHolder) runWithTenant(ctx context.Context, db *gorm.DB, fn func(db *gorm.DB) error) error {
+	if ctx.Value(ctxk(transactionName)) != nil {
+		return fn(db)
+	}
+
+	searchPath, err := d.config.TenantSearchPath(ctx)
+	if err != nil {
+		return err
+	}
+
+	if searchPath == "" {
+		return fn(db)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(setLocalSearchPath, searchPath).Error
+		if err != nil {
+			return uerr.NewError(uerr.GenericError, "Error setting tenant schema.").WithCause(err)
+		}
+
+		return fn(tx)
+	})
+}
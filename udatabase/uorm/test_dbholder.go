@@ -1,9 +1,12 @@
 package uorm
 
 import (
-	"fmt"
+	"context"
+	"errors"
 
 	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/golang-migrate/migrate/v4"
+	"gorm.io/gorm"
 )
 
 type TestDBHolder struct {
@@ -13,24 +16,110 @@ type TestDBHolder struct {
 func NewTestDBHolder(schemaName string) *TestDBHolder {
 	cfg := udatabase.NewDBConfigFromEnv()
 	cfg.SchemaName = schemaName
+	return NewTestDBHolderFromConfig(cfg)
+}
+
+// NewTestDBHolderFromConfig returns a *TestDBHolder initialized with the provided config.
+// It allows running the tests against other dialects, e.g. an in-memory SQLite database.
+func NewTestDBHolderFromConfig(cfg *udatabase.DBConfig) *TestDBHolder {
 	return &TestDBHolder{
 		DBHolder: NewDBHolder(cfg),
 	}
 }
 
-func (d *TestDBHolder) Reset() {
-	db := d.db
+// NewIsolatedTestDBHolder returns a *TestDBHolder configured by env variables, as NewTestDBHolder,
+// whose database is only used by t. See NewIsolatedTestDBHolderFromConfig.
+func NewIsolatedTestDBHolder(t udatabase.TB, schemaName string) *TestDBHolder {
+	t.Helper()
+	cfg := udatabase.NewDBConfigFromEnv()
+	cfg.SchemaName = schemaName
+	return NewIsolatedTestDBHolderFromConfig(t, cfg)
+}
 
-	err := db.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", d.config.SchemaName)).Error
-	if err != nil {
-		panic(err)
+// NewIsolatedTestDBHolderFromConfig returns a migrated *TestDBHolder whose database is only used
+// by t, so tests can call t.Parallel() instead of sharing a schema that is Reset by each of them.
+// Its connections are closed and the database dropped with t.Cleanup. See udatabase.NewIsolatedDBConfig.
+func NewIsolatedTestDBHolderFromConfig(t udatabase.TB, cfg *udatabase.DBConfig) *TestDBHolder {
+	t.Helper()
+
+	d := NewTestDBHolderFromConfig(udatabase.NewIsolatedDBConfig(t, cfg))
+	t.Cleanup(func() {
+		if sdb, err := d.db.DB(); err == nil {
+			_ = sdb.Close()
+		}
+	})
+
+	err := d.RunMigrations()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("%v", err)
 	}
+	return d
+}
 
-	sdb, err := db.DB()
+func (d *TestDBHolder) Reset() {
+	sdb, err := d.db.DB()
 	if err != nil {
 		panic(err)
 	}
+
+	d.config.DropSchema(sdb)
 	d.config.CreateSchema(sdb)
-	d.config.SetSearchPath(sdb)
 	_ = d.RunMigrations()
 }
+
+// BeginTestTx returns a ctx holding a transaction that is rolled back with t.Cleanup, as a faster
+// alternative to Reset. Repositories run the queries made with ctx in the transaction and the
+// Begin calls of the code under test create savepoints in it, so nothing is persisted. The
+// transaction must not be shared by parallel tests.
+func (d *TestDBHolder) BeginTestTx(t udatabase.TB) context.Context {
+	t.Helper()
+
+	tx := d.db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("%v", tx.Error)
+	}
+	t.Cleanup(func() { _ = tx.Rollback().Error })
+
+	return udatabase.WithTestTransaction(context.WithValue(context.Background(), ctxk(transactionName), tx))
+}
+
+// LoadFixtures loads the fixture files of paths in a transaction, which is rolled back if any of
+// them fails. See udatabase.ReadFixtures for their format.
+func (d *TestDBHolder) LoadFixtures(paths ...string) error {
+	return d.LoadFixturesContext(context.Background(), paths...)
+}
+
+// LoadFixturesContext is like LoadFixtures, but if ctx holds the transaction of BeginTestTx, the
+// fixtures are loaded in a savepoint of it and rolled back with it.
+func (d *TestDBHolder) LoadFixturesContext(ctx context.Context, paths ...string) (rErr error) {
+	r := NewDBRepository[struct{}](d.DBHolder, nil)
+	ctx, err := udatabase.BeginTx(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer udatabase.EndTx(ctx, r, &rErr)
+
+	return udatabase.LoadFixtures(ctx, &fixtureDB{r: r, tx: r.GetDBInstance(ctx)}, d.config.GetDialect(), paths...)
+}
+
+// fixtureDB is the udatabase.FixtureDB of a transaction.
+type fixtureDB struct {
+	r  *DBrepository[struct{}]
+	tx *gorm.DB
+}
+
+func (db *fixtureDB) Exec(_ context.Context, query string, args ...any) error {
+	if err := db.tx.Exec(query, args...).Error; err != nil {
+		return db.r.HandleSaveOrUpdateError(err)
+	}
+	return nil
+}
+
+func (db *fixtureDB) QueryPairs(_ context.Context, query string) ([][2]string, error) {
+	rows, err := db.tx.Raw(query).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return udatabase.ScanPairs(rows)
+}
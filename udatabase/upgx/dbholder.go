@@ -0,0 +1,107 @@
+package upgx
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	// nolint:blank-imports // it is necessary to run the SQL migrations.
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+type DBHolder struct {
+	config *udatabase.DBConfig
+	pool   *pgxpool.Pool
+	sdb    *sql.DB
+}
+
+// Returns a *DBHolder initialized with the provided config.
+// In case the *DBConfig object has zero values, those will
+// be filled with default values. Only the Postgres dialect
+// is supported.
+func NewDBHolder(config *udatabase.DBConfig) *DBHolder {
+	config.SetEmptyValuesToDefaults()
+	if _, ok := config.GetDialect().(udatabase.PostgresDialect); !ok {
+		panic("upgx only supports the Postgres dialect")
+	}
+
+	ctx := context.Background()
+	poolConfig, err := pgxpool.ParseConfig(config.GetConnectionString())
+	if err != nil {
+		panic(err)
+	}
+	if observer := config.GetQueryObserver(); observer != nil {
+		poolConfig.ConnConfig.Tracer = queryTracer{observer: observer}
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		panic(err)
+	}
+
+	err = pool.Ping(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	// Migrations and advisory locks hold a connection for as long as they run, so they
+	// use a *sql.DB with its own connections instead of starving the pool.
+	dbHolder := &DBHolder{
+		config: config,
+		pool:   pool,
+		sdb:    stdlib.OpenDB(*pool.Config().ConnConfig),
+	}
+
+	dbHolder.config.CreateSchema(dbHolder.sdb)
+	config.Metrics.RegisterPool(config.PoolLabels(), dbHolder.poolStats)
+
+	return dbHolder
+}
+
+// RunMigrations runs SQL migrations found in the folder specified by DBConfig.MigrationsDir
+func (d *DBHolder) RunMigrations() error {
+	return udatabase.RunMigrations(d.sdb, d.config)
+}
+
+// WithAdvisoryLock runs fn while holding the Postgres advisory lock identified by key.
+// It waits until the lock is acquired or ctx is done. Keys can be derived from
+// names with udatabase.AdvisoryLockKey.
+func (d *DBHolder) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	return udatabase.WithAdvisoryLock(ctx, d.sdb, key, fn)
+}
+
+// GetDBInstance returns the inner *pgxpool.Pool provided by pgx.
+// More on pgx here: https://github.com/jackc/pgx
+func (d *DBHolder) GetDBInstance() *pgxpool.Pool {
+	return d.pool
+}
+
+// GetSQLDB returns the *sql.DB used to run migrations and advisory locks, for
+// libraries that require database/sql. It does not take connections from the pool.
+func (d *DBHolder) GetSQLDB() *sql.DB {
+	return d.sdb
+}
+
+// Close closes the *sql.DB and every connection of the pool.
+func (d *DBHolder) Close() {
+	_ = d.sdb.Close()
+	d.pool.Close()
+}
+
+// poolStats returns the stats of the pool as sql.DBStats. Waits are the acquisitions that had to
+// wait for a connection to be released or established.
+func (d *DBHolder) poolStats() sql.DBStats {
+	stat := d.pool.Stat()
+	return sql.DBStats{
+		MaxOpenConnections: int(stat.MaxConns()),
+		OpenConnections:    int(stat.TotalConns()),
+		InUse:              int(stat.AcquiredConns()),
+		Idle:               int(stat.IdleConns()),
+		WaitCount:          stat.EmptyAcquireCount(),
+		MaxIdleTimeClosed:  stat.MaxIdleDestroyCount(),
+		MaxLifetimeClosed:  stat.MaxLifetimeDestroyCount(),
+	}
+}
@@ -0,0 +1,474 @@
+package upgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	usqlFilters "github.com/carlosarismendi/utils/udatabase/usql/filters"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jmoiron/sqlx"
+)
+
+type ctxk string
+
+const transactionName string = "dbtx"
+
+// DBrepository is built on top of pgxpool to provide easier transaction management as well as
+// methods for error handling, with access to pgx features like COPY and batch queries. Rows are
+// scanned into T by name, so T must be a struct whose fields match the columns of the queries
+// (snake_case columns match CamelCase fields).
+type DBrepository[T any] struct {
+	db   *DBHolder
+	name string
+
+	filters  map[string]usqlFilters.Filter
+	sorters  map[string]usqlFilters.Sorter
+	maxLimit int64
+}
+
+// NewDBRepository returns a DBrepository.
+// requires a that map will be used in the method SelectContext(context.Context, Querier, string, url.Values)
+// to use the filters and sorters provided in the url.Values{} parameter. In case the url.Values contains a
+// filter that it is not in the filters map, it will return an error.
+func NewDBRepository[T any](dbHolder *DBHolder, filtersMap map[string]usqlFilters.Filter,
+	sorters map[string]usqlFilters.Sorter) *DBrepository[T] {
+	if filtersMap == nil {
+		filtersMap = make(map[string]usqlFilters.Filter)
+	}
+
+	return &DBrepository[T]{
+		db:       dbHolder,
+		name:     udatabase.RepositoryName[T](),
+		filters:  filtersMap,
+		sorters:  sorters,
+		maxLimit: filters.DefaultMaxLimit,
+	}
+}
+
+// WithMaxLimit sets the maximum "limit" of the pages of the repository. It is filters.DefaultMaxLimit
+// by default. It panics if maxLimit is less than filters.DefaultLimit.
+func (r *DBrepository[T]) WithMaxLimit(maxLimit int64) *DBrepository[T] {
+	if maxLimit < filters.DefaultLimit {
+		panic("WithMaxLimit maxLimit must be greater than or equal to filters.DefaultLimit")
+	}
+	r.maxLimit = maxLimit
+	return r
+}
+
+// Describe returns the descriptions of the query parameters of the repository, its filters,
+// sorters, "limit" and "offset", sorted by name, e.g. to generate the API documentation of the
+// endpoint with filters.OpenAPIParameters or filters.ParametersJSONSchema.
+func (r *DBrepository[T]) Describe() []filters.Description {
+	params := make(map[string]any, len(r.filters)+len(r.sorters)+2)
+	for name, f := range r.filters {
+		params[name] = f
+	}
+	for name, s := range r.sorters {
+		params[name] = s
+	}
+	params["limit"] = filters.DescribeLimit(filters.DefaultLimit, r.maxLimit)
+	params["offset"] = filters.DescribeOffset()
+	return filters.DescribeParams(params)
+}
+
+// Querier is implemented by *pgxpool.Pool, *pgxpool.Conn, *pgx.Conn and pgx.Tx.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string,
+		rowSrc pgx.CopyFromSource) (int64, error)
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// Begin opens a new transaction. If ctx already holds a transaction, Begin returns ctx
+// as it is, so the caller joins the transaction, unless ctx holds the transaction of a
+// test (see udatabase.WithTestTransaction), in which case Begin creates a savepoint in
+// it, which is released by Commit and undone by Rollback.
+func (r *DBrepository[T]) Begin(ctx context.Context) (_ context.Context, rErr error) {
+	defer r.observe("Begin", time.Now(), &rErr)
+
+	var tx pgx.Tx
+	var err error
+	if parent := r.GetTransaction(ctx); parent != nil {
+		if !udatabase.IsTestTransaction(ctx) {
+			return ctx, nil
+		}
+		tx, err = parent.Begin(ctx)
+	} else {
+		tx, err = r.db.pool.Begin(ctx)
+	}
+	if err != nil {
+		tErr := uerr.NewError(uerr.GenericError, "Error beginning transaction.").WithCause(err)
+		return nil, tErr
+	}
+
+	ctx = context.WithValue(ctx, ctxk(transactionName), tx)
+	return ctx, nil
+}
+
+// Commit closes and confirms the current transaction, or releases the
+// current savepoint if ctx was returned by a nested Begin.
+func (r *DBrepository[T]) Commit(ctx context.Context) (rErr error) {
+	defer r.observe("Commit", time.Now(), &rErr)
+
+	tx := r.GetTransaction(ctx)
+	if tx == nil {
+		tErr := uerr.NewError(uerr.GenericError, "Missing transaction when doing Commit.")
+		return tErr
+	}
+	return tx.Commit(ctx)
+}
+
+// Rollback cancels the current transaction, or the changes made since the
+// current savepoint if ctx was returned by a nested Begin.
+func (r *DBrepository[T]) Rollback(ctx context.Context) {
+	tx := r.GetTransaction(ctx)
+	if tx == nil {
+		return
+	}
+	_ = tx.Rollback(ctx)
+}
+
+// observe records the duration and the outcome of operation in DBConfig.Metrics.
+func (r *DBrepository[T]) observe(operation string, start time.Time, err *error) {
+	r.db.config.Metrics.ObserveOperation(operation, r.name, start, *err)
+}
+
+// HandleSearchError in case of running SELECT queries, this method provides an easy way
+// of checking if the error returned is a NotFound or other type.
+func (r *DBrepository[T]) HandleSearchError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uerr.NewError(uerr.ResourceNotFoundError, "Resource not found.").WithCause(err)
+	}
+
+	if rErr := udatabase.MapPgError(err); rErr != nil {
+		return rErr
+	}
+
+	return uerr.NewError(uerr.GenericError, "Error searching resource.").WithCause(err)
+}
+
+// HandleSaveOrUpdateError in case of running an INSERT/UPDATE query, this method provides
+// an easy way of checking if the returned error is nil or if it violates a PRIMARY KEY/UNIQUE constraint.
+// Postgres errors are mapped to UError by udatabase.MapPgError.
+func (r *DBrepository[T]) HandleSaveOrUpdateError(tag pgconn.CommandTag, err error) error {
+	if err == nil {
+		if tag.RowsAffected() <= 0 {
+			return uerr.NewError(uerr.ResourceNotFoundError, "Resource(s) not found.")
+		}
+
+		return nil
+	}
+
+	if rErr := udatabase.MapPgError(err); rErr != nil {
+		return rErr
+	}
+
+	return uerr.NewError(uerr.GenericError, "Error saving or updating resource.").WithCause(err)
+}
+
+func (r *DBrepository[T]) GetDBInstance() Querier {
+	return r.db.GetDBInstance()
+}
+
+func (r *DBrepository[T]) GetTransaction(ctx context.Context) pgx.Tx {
+	txFromCtx := ctx.Value(ctxk(transactionName))
+	if txFromCtx == nil {
+		return nil
+	}
+	return txFromCtx.(pgx.Tx)
+}
+
+// GetQuerier returns the transaction in ctx if there is one, otherwise the pool.
+func (r *DBrepository[T]) GetQuerier(ctx context.Context) Querier {
+	if tx := r.GetTransaction(ctx); tx != nil {
+		return tx
+	}
+	return r.db.pool
+}
+
+// querier replaces db with the transaction of ctx when db is the pool and ctx holds the
+// transaction of a test, see udatabase.WithTestTransaction.
+func (r *DBrepository[T]) querier(ctx context.Context, db Querier) Querier {
+	if pool, ok := db.(*pgxpool.Pool); ok && pool == r.db.pool && udatabase.IsTestTransaction(ctx) {
+		return r.GetQuerier(ctx)
+	}
+	return db
+}
+
+// GetContext runs query with the filters in v and scans the first row into a new T.
+// When db is the pool, the query runs in the transaction of ctx if it is the transaction of a test.
+func (r *DBrepository[T]) GetContext(ctx context.Context, db Querier, query string,
+	v url.Values) (_ *T, rErr error) {
+	defer r.observe("GetContext", time.Now(), &rErr)
+
+	v.Del("limit")
+	v.Add("limit", "1")
+	query, args, _, _, err := r.ApplyFilters(query, v)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.querier(ctx, db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, r.HandleSearchError(err)
+	}
+
+	dst, err := pgx.CollectOneRow(rows, pgx.RowToAddrOfStructByNameLax[T])
+	if err != nil {
+		return nil, r.HandleSearchError(err)
+	}
+	return dst, nil
+}
+
+// SelectContext runs query with the filters in v and returns the rows found.
+// When db is the pool, the query runs in the transaction of ctx if it is the transaction of a
+// test. If ctx was created with udatabase.WithTotal, the Total of the page is counted with the
+// same filters.
+func (r *DBrepository[T]) SelectContext(ctx context.Context, db Querier, query string,
+	v url.Values) (rp *udatabase.ResourcePage[*T], err error) {
+	defer r.observe("SelectContext", time.Now(), &err)
+
+	query, countQuery, args, limit, offset, err := r.buildQueries(query, v)
+	if err != nil {
+		return nil, err
+	}
+
+	q := r.querier(ctx, db)
+	rows, err := q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, r.HandleSearchError(err)
+	}
+
+	dst, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByNameLax[T])
+	if err != nil {
+		return nil, r.HandleSearchError(err)
+	}
+
+	total := int64(-1)
+	if udatabase.CountTotal(ctx) {
+		err = q.QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, r.HandleSearchError(err)
+		}
+	}
+
+	rp = &udatabase.ResourcePage[*T]{
+		Limit:  limit,
+		Offset: offset,
+	}
+	rp.SetResources(dst, total)
+
+	return rp, nil
+}
+
+// CopyFrom inserts resources into table with the COPY protocol, using the transaction in ctx
+// if there is one. values returns the values of a resource in the same order as columns.
+// It returns the number of rows inserted.
+//
+// Usage:
+//
+//	n, err := repository.CopyFrom(ctx, "resources", []string{"id", "name"}, resources,
+//		func(r *Resource) []any { return []any{r.ID, r.Name} })
+func (r *DBrepository[T]) CopyFrom(ctx context.Context, table string, columns []string, resources []*T,
+	values func(*T) []any) (int64, error) {
+	src := pgx.CopyFromSlice(len(resources), func(i int) ([]any, error) {
+		return values(resources[i]), nil
+	})
+
+	n, err := r.GetQuerier(ctx).CopyFrom(ctx, pgx.Identifier(strings.Split(table, ".")), columns, src)
+	if err != nil {
+		if rErr := udatabase.MapPgError(err); rErr != nil {
+			return n, rErr
+		}
+		return n, uerr.NewError(uerr.GenericError, "Error copying resources.").WithCause(err)
+	}
+
+	return n, nil
+}
+
+// SendBatch sends the queries queued in b in a single round trip, using the transaction in ctx
+// if there is one, and runs the callbacks registered on the queued queries. It returns the
+// first error found, with Postgres errors mapped to UError by udatabase.MapPgError.
+//
+// Usage:
+//
+//	b := &pgx.Batch{}
+//	b.Queue("UPDATE resources SET name = $1 WHERE id = $2", name, id)
+//	b.Queue("SELECT count(*) FROM resources").QueryRow(func(row pgx.Row) error {
+//		return row.Scan(&count)
+//	})
+//	err := repository.SendBatch(ctx, b)
+func (r *DBrepository[T]) SendBatch(ctx context.Context, b *pgx.Batch) error {
+	err := r.GetQuerier(ctx).SendBatch(ctx, b).Close()
+	if err == nil {
+		return nil
+	}
+
+	if rErr := udatabase.MapPgError(err); rErr != nil {
+		return rErr
+	}
+
+	var uErr *uerr.UError
+	if errors.As(err, &uErr) {
+		return err
+	}
+
+	return uerr.NewError(uerr.GenericError, "Error sending batch.").WithCause(err)
+}
+
+// ApplyFilters appends the conditions, sorting, limit and offset in v to query. The
+// returned query uses Postgres placeholders ($1, $2, ...).
+func (r *DBrepository[T]) ApplyFilters(query string, v url.Values) (queryResult string, args []any,
+	limit, offset int64, err error) {
+	queryResult, _, args, limit, offset, err = r.buildQueries(query, v)
+	return queryResult, args, limit, offset, err
+}
+
+// buildQueries returns query with the filters, sorting, limit and offset of v, and a query
+// that counts the rows matching the filters of v. Both take args.
+func (r *DBrepository[T]) buildQueries(query string, v url.Values) (queryResult, countQuery string, args []any,
+	limit, offset int64, err error) {
+	limitQ, limit, err := r.applyLimit(v)
+	if err != nil {
+		return "", "", nil, 0, 0, err
+	}
+	offsetQ, offset, err := r.applyOffset(v)
+	if err != nil {
+		return "", "", nil, 0, 0, err
+	}
+
+	conds, sorts, args, unknownFilters, err := r.applyFilters(v)
+	if err != nil {
+		return "", "", nil, 0, 0, err
+	}
+
+	err = r.processUnknownFilters(unknownFilters)
+	if err != nil {
+		return "", "", nil, 0, 0, err
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(query) + len(conds) + len(sorts) + len(limitQ) + len(offsetQ))
+	sb.WriteString(query)
+	sb.WriteString(conds)
+	sb.WriteString(sorts)
+	sb.WriteString(limitQ)
+
+	if offset > 0 {
+		sb.WriteString(offsetQ)
+	}
+
+	queryResult = sb.String()
+	countQuery = "SELECT COUNT(*) FROM (" + query + conds + ") AS udatabase_count"
+	if len(args) > 0 {
+		queryResult = sqlx.Rebind(sqlx.DOLLAR, queryResult)
+		countQuery = sqlx.Rebind(sqlx.DOLLAR, countQuery)
+	}
+	return queryResult, countQuery, args, limit, offset, nil
+}
+
+func (r *DBrepository[T]) applyFilters(v url.Values) (conds, sorts string, args []any, unknown []string,
+	err error) {
+	if err = usqlFilters.CheckConstraints(r.filters, v); err != nil {
+		return "", "", nil, nil, err
+	}
+
+	args = make([]any, 0, len(v))
+	var sbConds, sbSorts strings.Builder
+	var cSep, sSep string
+	for key, values := range v {
+		if len(values) == 0 {
+			continue
+		}
+
+		filter, ok := r.filters[key]
+		// If filter not found => try to apply sorter
+		if !ok {
+			sorter, ok := r.sorters[key]
+			if !ok {
+				unknown = append(unknown, key)
+				continue
+			}
+
+			sort, err := sorter.Apply(values)
+			if err != nil {
+				return "", "", nil, nil, err
+			}
+
+			sbSorts.WriteString(sSep)
+			sbSorts.WriteString(sort)
+			sSep = ", "
+			continue
+		}
+
+		cond, fArgs, err := filter.Apply(values)
+		if err != nil {
+			return "", "", nil, nil, err
+		}
+
+		sbConds.WriteString(cSep)
+		sbConds.WriteString(cond)
+		cSep = " AND "
+
+		args = append(args, fArgs...)
+	}
+
+	if cSep != "" {
+		conds = " WHERE " + sbConds.String()
+	}
+
+	if sSep != "" {
+		sorts = " ORDER BY " + sbSorts.String()
+	}
+
+	return conds, sorts, args, unknown, nil
+}
+
+func (r *DBrepository[T]) applyLimit(v url.Values) (limitQ string, limitNum int64, rErr error) {
+	values, ok := v["limit"]
+	if !ok {
+		return filters.DefaultLimitStr, filters.DefaultLimit, nil
+	}
+
+	v.Del("limit")
+	return filters.ApplyLimitWithMax("", values[0], r.maxLimit)
+}
+
+func (r *DBrepository[T]) applyOffset(v url.Values) (offsetQ string, offsetNum int64, rErr error) {
+	values, ok := v["offset"]
+	if !ok {
+		return "", 0, nil
+	}
+
+	v.Del("offset")
+	return filters.ApplyOffset("", values[0])
+}
+
+func (r *DBrepository[T]) processUnknownFilters(unknown []string) error {
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	var msg, sep string
+	for _, value := range unknown {
+		msg += fmt.Sprintf("%s Invalid filter %q", sep, value)
+		sep = "; "
+	}
+
+	return uerr.NewError(uerr.WrongInputParameterError, msg)
+}
@@ -0,0 +1,299 @@
+package upgx
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	usqlFilters "github.com/carlosarismendi/utils/udatabase/usql/filters"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type Resource struct {
+	ID           string
+	Name         string
+	RandomNumber int
+}
+
+func createResourceTable(t testing.TB, r *DBrepository[Resource]) {
+	_, err := r.GetDBInstance().Exec(context.Background(),
+		"CREATE TABLE resources (id UUID PRIMARY KEY, name TEXT, random_number INTEGER);")
+	require.NoError(t, err)
+}
+
+func resourceValues(res *Resource) []any {
+	return []any{res.ID, res.Name, res.RandomNumber}
+}
+
+var resourceColumns = []string{"id", "name", "random_number"}
+
+func TestTransactions(t *testing.T) {
+	dbHolder := NewTestDBHolder("db_pgx_repository_test_transactions")
+	r := NewDBRepository[Resource](dbHolder.DBHolder, nil, nil)
+	query := "SELECT id, name, random_number FROM resources"
+
+	t.Run("savingResourceWithoutError_commitsTransaction", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+
+		resource := &Resource{
+			ID:           "0ea57dec-5e79-40dc-b971-a52561fcc2c7",
+			Name:         "Resource name",
+			RandomNumber: 4,
+		}
+
+		err := func() (rErr error) {
+			ctx, err := udatabase.BeginTx(context.Background(), r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+
+			// ACT
+			return save(ctx, r, resource)
+		}()
+		require.NoError(t, err)
+
+		// ASSERT
+		actual, err := r.GetContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		require.Equal(t, resource, actual)
+	})
+
+	t.Run("rollingBackTransaction_discardsChanges", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, save(ctx, r, &Resource{ID: "0ea57dec-5e79-40dc-b971-a52561fcc2c7"}))
+
+		// ACT
+		r.Rollback(ctx)
+
+		// ASSERT
+		_, err = r.GetContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+		require.Error(t, err)
+		require.Equal(t, uerr.ResourceNotFoundError, uerr.GetKey(err))
+	})
+
+	t.Run("savingDuplicatedPrimaryKey_returnsResourceAlreadyExistsError", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+
+		resource := &Resource{ID: "0ea57dec-5e79-40dc-b971-a52561fcc2c7"}
+		require.NoError(t, save(context.Background(), r, resource))
+
+		// ACT
+		err := save(context.Background(), r, resource)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.ResourceAlreadyExistsError, uerr.GetKey(err))
+	})
+}
+
+func TestBeginTestTx(t *testing.T) {
+	dbHolder := NewTestDBHolder("db_pgx_repository_test_test_tx")
+	dbHolder.Reset()
+	r := NewDBRepository[Resource](dbHolder.DBHolder, nil, nil)
+	createResourceTable(t, r)
+	query := "SELECT id, name, random_number FROM resources"
+
+	r1 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1"}
+	r2 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4602", Name: "Resource2"}
+
+	t.Run("nestedTransactions_mapToSavepoints", func(t *testing.T) {
+		// ARRANGE
+		ctx := dbHolder.BeginTestTx(t)
+
+		// ACT
+		err := func() (rErr error) {
+			ctx, err := udatabase.BeginTx(ctx, r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+			return save(ctx, r, r1)
+		}()
+		require.NoError(t, err)
+
+		err = func() (rErr error) {
+			ctx, err := udatabase.BeginTx(ctx, r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+			if err = save(ctx, r, r2); err != nil {
+				return err
+			}
+			return uerr.NewError(uerr.GenericError, "Forced error.")
+		}()
+		require.Error(t, err)
+
+		// ASSERT
+		rp, err := r.SelectContext(ctx, r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		require.Equal(t, []*Resource{r1}, rp.Resources)
+	})
+
+	t.Run("testTransaction_isRolledBackOnCleanup", func(t *testing.T) {
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+}
+
+func TestSelectContext(t *testing.T) {
+	dbHolder := NewTestDBHolder("db_pgx_repository_test_select_context")
+	dbHolder.Reset()
+
+	filtersMap := map[string]usqlFilters.Filter{
+		"name":          usqlFilters.TextField("name"),
+		"random_number": usqlFilters.NumField("random_number"),
+	}
+	sortersMap := map[string]usqlFilters.Sorter{
+		"sort": usqlFilters.Sort("name", "random_number"),
+	}
+
+	r := NewDBRepository[Resource](dbHolder.DBHolder, filtersMap, sortersMap)
+	createResourceTable(t, r)
+	r1, r2, r3 := populateDB(t, r)
+
+	query := "SELECT id, name, random_number FROM resources"
+
+	t.Run("filteringAndSorting_returnsMatchingResources", func(t *testing.T) {
+		// ACT
+		v := url.Values{"random_number": {"2"}, "sort": {"-name"}}
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, v)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []*Resource{r3, r2}, rp.Resources)
+		require.Equal(t, int64(2), rp.Total)
+	})
+
+	t.Run("limitAndOffset_returnsPage", func(t *testing.T) {
+		// ACT
+		v := url.Values{"sort": {"name"}, "limit": {"1"}, "offset": {"1"}}
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, v)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []*Resource{r2}, rp.Resources)
+		require.Equal(t, int64(1), rp.Limit)
+		require.Equal(t, int64(1), rp.Offset)
+	})
+
+	t.Run("getContext_returnsResource", func(t *testing.T) {
+		// ACT
+		actual, err := r.GetContext(context.Background(), r.GetDBInstance(), query, url.Values{"name": {r1.Name}})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, r1, actual)
+	})
+
+	t.Run("unknownFilter_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{"unknown": {"1"}})
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
+
+func TestCopyFromAndSendBatch(t *testing.T) {
+	dbHolder := NewTestDBHolder("db_pgx_repository_test_copy_from")
+	r := NewDBRepository[Resource](dbHolder.DBHolder, nil, nil)
+	query := "SELECT id, name, random_number FROM resources"
+
+	t.Run("copyFrom_insertsResources", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+
+		resources := []*Resource{
+			{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1", RandomNumber: 1},
+			{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4602", Name: "Resource2", RandomNumber: 2},
+		}
+
+		// ACT
+		n, err := r.CopyFrom(context.Background(), "resources", resourceColumns, resources, resourceValues)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query+" ORDER BY name", url.Values{})
+		require.NoError(t, err)
+		require.Equal(t, resources, rp.Resources)
+	})
+
+	t.Run("copyFromDuplicatedPrimaryKey_returnsResourceAlreadyExistsError", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+
+		resource := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601"}
+
+		// ACT
+		_, err := r.CopyFrom(context.Background(), "resources", resourceColumns, []*Resource{resource, resource},
+			resourceValues)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.ResourceAlreadyExistsError, uerr.GetKey(err))
+	})
+
+	t.Run("sendBatch_runsQueuedQueries", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+
+		b := &pgx.Batch{}
+		b.Queue("INSERT INTO resources (id, name, random_number) VALUES ($1, $2, $3)",
+			"5ceff18d-9039-44b5-a5d3-3d99653f4601", "Resource1", 1)
+		b.Queue("INSERT INTO resources (id, name, random_number) VALUES ($1, $2, $3)",
+			"5ceff18d-9039-44b5-a5d3-3d99653f4602", "Resource2", 2)
+
+		var count int
+		b.Queue("SELECT count(*) FROM resources").QueryRow(func(row pgx.Row) error {
+			return row.Scan(&count)
+		})
+
+		// ACT
+		err := r.SendBatch(context.Background(), b)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+}
+
+func populateDB(t testing.TB, r *DBrepository[Resource]) (r1, r2, r3 *Resource) {
+	r1 = &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1", RandomNumber: 1}
+	r2 = &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4602", Name: "Resource2", RandomNumber: 2}
+	r3 = &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4603", Name: "Resource3", RandomNumber: 2}
+
+	_, err := r.CopyFrom(context.Background(), "resources", resourceColumns, []*Resource{r1, r2, r3},
+		resourceValues)
+	require.NoError(t, err)
+	return r1, r2, r3
+}
+
+func save(ctx context.Context, r *DBrepository[Resource], res *Resource) error {
+	tag, err := r.GetQuerier(ctx).Exec(ctx, "INSERT INTO resources (id, name, random_number) VALUES ($1, $2, $3)",
+		res.ID, res.Name, res.RandomNumber)
+	return r.HandleSaveOrUpdateError(tag, err)
+}
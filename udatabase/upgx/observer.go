@@ -0,0 +1,41 @@
+package upgx
+
+import (
+	"context"
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/jackc/pgx/v5"
+)
+
+const observedQueryName ctxk = "dbobservedquery"
+
+type observedQuery struct {
+	query string
+	args  []any
+	start time.Time
+}
+
+// queryTracer is a pgx.QueryTracer that notifies observer of every query run by the pool.
+type queryTracer struct {
+	observer udatabase.QueryObserver
+}
+
+func (t queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx = t.observer.BeforeQuery(ctx)
+	return context.WithValue(ctx, observedQueryName, observedQuery{query: data.SQL, args: data.Args, start: time.Now()})
+}
+
+func (t queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	q, ok := ctx.Value(observedQueryName).(observedQuery)
+	if !ok {
+		return
+	}
+
+	t.observer.AfterQuery(ctx, udatabase.QueryEvent{
+		Query:    q.query,
+		Args:     q.args,
+		Duration: time.Since(q.start),
+		Err:      data.Err,
+	})
+}
@@ -1,6 +1,10 @@
 package usql
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
+
 	"github.com/carlosarismendi/utils/udatabase"
 
 	// nolint:blank-imports // it is necessary to run the SQL migrations.
@@ -9,45 +13,109 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// boundDrivers are the drivers whose placeholders have been registered in sqlx by bindDriver.
+var boundDrivers sync.Map
+
+// bindDriver registers the placeholders of the driver of dialect in sqlx, which are global,
+// the first time a DBHolder uses it.
+func bindDriver(dialect udatabase.Dialect) {
+	if _, loaded := boundDrivers.LoadOrStore(dialect.DriverName(), struct{}{}); !loaded {
+		sqlx.BindDriver(dialect.DriverName(), dialect.BindType())
+	}
+}
+
 type DBHolder struct {
-	config *udatabase.DBConfig
-	db     *sqlx.DB
+	config   *udatabase.DBConfig
+	db       *sqlx.DB
+	replicas []*sqlx.DB
+	next     atomic.Uint64
 }
 
 // Returns a *DBHolder initialized with the provided config.
 // In case the *DBConfig object has zero values, those will
-// be filled with default values.
+// be filled with default values. The database engine is
+// selected by DBConfig.Dialect, Postgres by default.
 func NewDBHolder(config *udatabase.DBConfig) *DBHolder {
 	config.SetEmptyValuesToDefaults()
 
-	conn := config.GetConnectionString()
-	db, err := sqlx.Connect("postgres", conn)
-	if err != nil {
-		panic(err)
-	}
+	bindDriver(config.GetDialect())
 
 	dbHolder := &DBHolder{
 		config: config,
-		db:     db,
+		db:     connect(config),
 	}
 
 	dbHolder.config.CreateSchema(dbHolder.db.DB)
-	dbHolder.config.SetSearchPath(dbHolder.db.DB)
+
+	for _, replicaConfig := range config.ReplicaConfigs() {
+		dbHolder.replicas = append(dbHolder.replicas, connect(replicaConfig))
+	}
 
 	return dbHolder
 }
 
+// connect opens and pings the database described by config, with the DBConfig.QueryObserver
+// and query logs configured by it, and registers its pool in DBConfig.Metrics.
+func connect(config *udatabase.DBConfig) *sqlx.DB {
+	sdb, err := config.OpenDB()
+	if err != nil {
+		panic(err)
+	}
+
+	db := sqlx.NewDb(sdb, config.GetDialect().DriverName())
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		panic(err)
+	}
+
+	config.Metrics.RegisterPool(config.PoolLabels(), db.Stats)
+	return db
+}
+
 // RunMigrations runs SQL migrations found in the folder specified by DBConfig.MigrationsDir
 func (d *DBHolder) RunMigrations() error {
 	return udatabase.RunMigrations(d.db.DB, d.config)
 }
 
+// ProvisionTenant creates the schema of tenantID if it does not exist and runs
+// the SQL migrations found in DBConfig.MigrationsDir on it.
+func (d *DBHolder) ProvisionTenant(ctx context.Context, tenantID string) error {
+	return udatabase.ProvisionTenant(ctx, d.db.DB, d.config, tenantID)
+}
+
+// MigrateTenants runs the SQL migrations found in DBConfig.MigrationsDir on the schema of every tenant.
+func (d *DBHolder) MigrateTenants(ctx context.Context) error {
+	return udatabase.MigrateTenants(ctx, d.db.DB, d.config)
+}
+
+// WithAdvisoryLock runs fn while holding the Postgres advisory lock identified by key.
+// It waits until the lock is acquired or ctx is done. Keys can be derived from
+// names with udatabase.AdvisoryLockKey.
+func (d *DBHolder) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) error {
+	return udatabase.WithAdvisoryLock(ctx, d.db.DB, key, fn)
+}
+
 // GetDBInstance returns the inner database object *sqlx.DB provided by sqlx.
 // More on sqlx here: https://github.com/jmoiron/sqlx
 func (d *DBHolder) GetDBInstance() *sqlx.DB {
 	return d.db
 }
 
+// GetReadDBInstance returns the *sqlx.DB to run read queries with. It returns the read
+// replicas in round-robin order, or the primary database if there are no replicas, ctx
+// was created with udatabase.WithPrimary or ctx holds a transaction.
+func (d *DBHolder) GetReadDBInstance(ctx context.Context) *sqlx.DB {
+	if len(d.replicas) == 0 || udatabase.UsePrimary(ctx) || ctx.Value(ctxk(transactionName)) != nil {
+		return d.db
+	}
+
+	i := d.next.Add(1) % uint64(len(d.replicas))
+	return d.replicas[i]
+}
+
 func (d *DBHolder) MapperFunc(mf func(string) string) {
 	d.db.MapperFunc(mf)
+	for _, replica := range d.replicas {
+		replica.MapperFunc(mf)
+	}
 }
@@ -0,0 +1,156 @@
+package usql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithAdvisoryLock(t *testing.T) {
+	dbHolder := NewTestDBHolder("db_usql_holder_test_advisory_lock")
+	key := udatabase.AdvisoryLockKey(t.Name())
+
+	holdLock := func(release <-chan struct{}) <-chan struct{} {
+		acquired := make(chan struct{})
+		go func() {
+			_ = dbHolder.WithAdvisoryLock(context.Background(), key, func(context.Context) error {
+				close(acquired)
+				<-release
+				return nil
+			})
+		}()
+		return acquired
+	}
+
+	t.Run("LockHeldByAnotherSession_waitsUntilItIsReleased", func(t *testing.T) {
+		// ARRANGE
+		release := make(chan struct{})
+		<-holdLock(release)
+
+		var released atomic.Bool
+		time.AfterFunc(300*time.Millisecond, func() {
+			released.Store(true)
+			close(release)
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// ACT
+		var ranAfterRelease bool
+		err := dbHolder.WithAdvisoryLock(ctx, key, func(context.Context) error {
+			ranAfterRelease = released.Load()
+			return nil
+		})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.True(t, ranAfterRelease)
+	})
+
+	t.Run("LockHeldByAnotherSession_returnsErrorWhenContextIsDone", func(t *testing.T) {
+		// ARRANGE
+		release := make(chan struct{})
+		defer close(release)
+		<-holdLock(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		// ACT
+		ran := false
+		err := dbHolder.WithAdvisoryLock(ctx, key, func(context.Context) error {
+			ran = true
+			return nil
+		})
+
+		// ASSERT
+		require.Error(t, err)
+		require.False(t, ran)
+	})
+}
+
+func TestGetReadDBInstance(t *testing.T) {
+	cfg := udatabase.NewDBConfigFromEnv()
+	cfg.SchemaName = "db_usql_holder_test_read_replicas"
+	cfg.ReplicaHosts = []string{cfg.Host, net.JoinHostPort(cfg.Host, cfg.Port)}
+	dbHolder := NewDBHolder(cfg)
+	r := NewDBRepository[*Resource](dbHolder, nil, nil)
+
+	t.Run("WithoutTransaction_returnsReplicasInRoundRobin", func(t *testing.T) {
+		// ACT
+		first := dbHolder.GetReadDBInstance(context.Background())
+		second := dbHolder.GetReadDBInstance(context.Background())
+		third := dbHolder.GetReadDBInstance(context.Background())
+
+		// ASSERT
+		require.NotSame(t, dbHolder.GetDBInstance(), first)
+		require.NotSame(t, dbHolder.GetDBInstance(), second)
+		require.NotSame(t, first, second)
+		require.Same(t, first, third)
+	})
+
+	t.Run("WithPrimary_returnsPrimary", func(t *testing.T) {
+		// ACT
+		actual := dbHolder.GetReadDBInstance(udatabase.WithPrimary(context.Background()))
+
+		// ASSERT
+		require.Same(t, dbHolder.GetDBInstance(), actual)
+	})
+
+	t.Run("WithTransaction_returnsPrimary", func(t *testing.T) {
+		// ARRANGE
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		defer r.Rollback(ctx)
+
+		// ACT
+		actual := dbHolder.GetReadDBInstance(ctx)
+
+		// ASSERT
+		require.Same(t, dbHolder.GetDBInstance(), actual)
+	})
+}
+
+func TestNewIsolatedTestDBHolder(t *testing.T) {
+	migrationsDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(migrationsDir, "1_create_resources.up.sql"),
+		[]byte("CREATE TABLE resources (id UUID PRIMARY KEY, name TEXT, random_number INTEGER);"), 0o600)
+	require.NoError(t, err)
+
+	cfg := udatabase.NewDBConfigFromEnv()
+	cfg.SchemaName = "db_usql_holder_test_isolated"
+	cfg.MigrationsDir = migrationsDir
+
+	for i := range 4 {
+		t.Run(fmt.Sprintf("ParallelTest%d_onlySeesItsOwnData", i), func(t *testing.T) {
+			t.Parallel()
+
+			// ARRANGE
+			dbHolder := NewIsolatedTestDBHolderFromConfig(t, cfg)
+			r := NewDBRepository[*Resource](dbHolder.DBHolder, nil, nil)
+			resource := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: fmt.Sprintf("Resource%d", i)}
+
+			ctx, err := r.Begin(context.Background())
+			require.NoError(t, err)
+			require.NoError(t, save(ctx, r, resource))
+			require.NoError(t, r.Commit(ctx))
+
+			// ACT
+			var actual Resource
+			err = findByID(r, resource.ID, &actual)
+
+			// ASSERT
+			require.NoError(t, err)
+			require.Equal(t, resource, &actual)
+		})
+	}
+}
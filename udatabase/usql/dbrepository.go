@@ -5,14 +5,20 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/carlosarismendi/utils/udatabase"
 	"github.com/carlosarismendi/utils/udatabase/filters"
 	usqlFilters "github.com/carlosarismendi/utils/udatabase/usql/filters"
 	"github.com/carlosarismendi/utils/uerr"
 	"github.com/jmoiron/sqlx"
-	"github.com/lib/pq"
+
+	// nolint:blank-imports // it registers the "postgres" driver used by sqlx.Connect.
+	_ "github.com/lib/pq"
 )
 
 type ctxk string
@@ -22,10 +28,12 @@ const transactionName string = "dbtx"
 // DBrepository is built on top of sqlx to provide easier transaction management as
 // well as methods for error handling.
 type DBrepository[T any] struct {
-	db *DBHolder
+	db   *DBHolder
+	name string
 
-	filters map[string]usqlFilters.Filter
-	sorters map[string]usqlFilters.Sorter
+	filters  map[string]usqlFilters.Filter
+	sorters  map[string]usqlFilters.Sorter
+	maxLimit int64
 }
 
 // NewDBRepository returns a DBrepository.
@@ -34,50 +42,136 @@ type DBrepository[T any] struct {
 // that it is not in the filters map, it will return an error.
 func NewDBRepository[T any](dbHolder *DBHolder, filtersMap map[string]usqlFilters.Filter,
 	sorters map[string]usqlFilters.Sorter) *DBrepository[T] {
+	if filtersMap == nil {
+		filtersMap = make(map[string]usqlFilters.Filter)
+	}
+
 	return &DBrepository[T]{
-		db:      dbHolder,
-		filters: filtersMap,
-		sorters: sorters,
+		db:       dbHolder,
+		name:     udatabase.RepositoryName[T](),
+		filters:  filtersMap,
+		sorters:  sorters,
+		maxLimit: filters.DefaultMaxLimit,
+	}
+}
+
+// WithMaxLimit sets the maximum "limit" of the pages of the repository. It is filters.DefaultMaxLimit
+// by default. It panics if maxLimit is less than filters.DefaultLimit.
+func (r *DBrepository[T]) WithMaxLimit(maxLimit int64) *DBrepository[T] {
+	if maxLimit < filters.DefaultLimit {
+		panic("WithMaxLimit maxLimit must be greater than or equal to filters.DefaultLimit")
+	}
+	r.maxLimit = maxLimit
+	return r
+}
+
+// Describe returns the descriptions of the query parameters of the repository, its filters,
+// sorters, "limit" and "offset", sorted by name, e.g. to generate the API documentation of the
+// endpoint with filters.OpenAPIParameters or filters.ParametersJSONSchema. Endpoints paginated
+// by keyset may add filters.DescribeCursor().
+func (r *DBrepository[T]) Describe() []filters.Description {
+	params := make(map[string]any, len(r.filters)+len(r.sorters)+2)
+	for name, f := range r.filters {
+		params[name] = f
 	}
+	for name, s := range r.sorters {
+		params[name] = s
+	}
+	params["limit"] = filters.DescribeLimit(filters.DefaultLimit, r.maxLimit)
+	params["offset"] = filters.DescribeOffset()
+	return filters.DescribeParams(params)
 }
 
-// Begin opens a new transaction.
-// NOTE: Nested transactions not supported.
-func (r *DBrepository[T]) Begin(ctx context.Context) (context.Context, error) {
-	txFromCtx := ctx.Value(transactionName)
-	if txFromCtx != nil {
+// Begin opens a new transaction. If ctx has a tenant (see udatabase.WithTenant),
+// the transaction runs against the schema of the tenant. If ctx already holds a
+// transaction, Begin returns ctx as it is, so the caller joins the transaction, unless
+// ctx holds the transaction of a test (see udatabase.WithTestTransaction), in which
+// case Begin creates a savepoint in it.
+func (r *DBrepository[T]) Begin(ctx context.Context) (_ context.Context, rErr error) {
+	defer r.observe("Begin", time.Now(), &rErr)
+
+	if tx := r.GetTransaction(ctx); tx != nil {
+		if !udatabase.IsTestTransaction(ctx) {
+			return ctx, nil
+		}
+
+		ctx, savepoint := udatabase.WithSavepoint(ctx)
+		_, err := tx.ExecContext(ctx, "SAVEPOINT "+savepoint)
+		if err != nil {
+			tErr := uerr.NewError(uerr.GenericError, "Error creating savepoint.").WithCause(err)
+			return nil, tErr
+		}
 		return ctx, nil
 	}
 
+	searchPath, err := r.db.config.TenantSearchPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := r.db.db.BeginTxx(ctx, nil)
 	if err != nil {
 		tErr := uerr.NewError(uerr.GenericError, "Error beginning transaction.").WithCause(err)
 		return nil, tErr
 	}
 
+	if searchPath != "" {
+		_, err = tx.ExecContext(ctx, setLocalSearchPath, searchPath)
+		if err != nil {
+			_ = tx.Rollback()
+			tErr := uerr.NewError(uerr.GenericError, "Error setting tenant schema.").WithCause(err)
+			return nil, tErr
+		}
+	}
+
 	ctx = context.WithValue(ctx, ctxk(transactionName), tx)
 	return ctx, nil
 }
 
-// Commit closes and confirms the current transaction.
-func (r *DBrepository[T]) Commit(ctx context.Context) error {
+// Commit closes and confirms the current transaction, or releases the
+// current savepoint if ctx was returned by a nested Begin.
+func (r *DBrepository[T]) Commit(ctx context.Context) (rErr error) {
+	defer r.observe("Commit", time.Now(), &rErr)
+
 	tx := r.GetTransaction(ctx)
 	if tx == nil {
 		tErr := uerr.NewError(uerr.GenericError, "Missing transaction when doing Commit.")
 		return tErr
 	}
+
+	if savepoint, ok := udatabase.SavepointFromContext(ctx); ok {
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+			return err
+		}
+		udatabase.ReleaseSavepoint(ctx)
+		return nil
+	}
 	return tx.Commit()
 }
 
-// Rollback cancels the current transaction.
+// Rollback cancels the current transaction, or the changes made since the
+// current savepoint if ctx was returned by a nested Begin. It does nothing
+// if Commit already released the savepoint, so it can be deferred.
 func (r *DBrepository[T]) Rollback(ctx context.Context) {
 	tx := r.GetTransaction(ctx)
 	if tx == nil {
 		return
 	}
+
+	if savepoint, ok := udatabase.SavepointFromContext(ctx); ok {
+		if !udatabase.IsSavepointReleased(ctx) {
+			_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		}
+		return
+	}
 	_ = tx.Rollback()
 }
 
+// observe records the duration and the outcome of operation in DBConfig.Metrics.
+func (r *DBrepository[T]) observe(operation string, start time.Time, err *error) {
+	r.db.config.Metrics.ObserveOperation(operation, r.name, start, *err)
+}
+
 // IsResourceNotFound in case of running SELECT queries using *sqlx.DB/*sqlx.Tx, this method
 // provides an easy way of checking if the error returned is a NotFound or other type.
 func (r *DBrepository[T]) HandleSearchError(err error) error {
@@ -89,11 +183,16 @@ func (r *DBrepository[T]) HandleSearchError(err error) error {
 		return uerr.NewError(uerr.ResourceNotFoundError, "Resource not found.").WithCause(err)
 	}
 
+	if rErr := r.db.config.GetDialect().MapError(err); rErr != nil {
+		return rErr
+	}
+
 	return uerr.NewError(uerr.GenericError, "Error searching resource.").WithCause(err)
 }
 
 // HandleSaveOrUpdateError in case of running an INSERT/UPDATE query, this method provides
 // an easy way of checking if the returned error is nil or if it violates a PRIMARY KEY/UNIQUE constraint.
+// Database errors are mapped to UError by the dialect of the DBConfig (udatabase.MapPgError for Postgres).
 func (r *DBrepository[T]) HandleSaveOrUpdateError(res sql.Result, err error) error {
 	if err == nil {
 		n, rErr := res.RowsAffected()
@@ -108,10 +207,8 @@ func (r *DBrepository[T]) HandleSaveOrUpdateError(res sql.Result, err error) err
 		return nil
 	}
 
-	if pqErr, ok := err.(*pq.Error); ok {
-		if rErr, ok := udatabase.PqErrors[pqErr.Code.Name()]; ok {
-			return rErr.WithCause(err)
-		}
+	if rErr := r.db.config.GetDialect().MapError(err); rErr != nil {
+		return rErr
 	}
 
 	return uerr.NewError(uerr.GenericError, "Error saving or updating resource.").WithCause(err)
@@ -121,6 +218,12 @@ func (r *DBrepository[T]) GetDBInstance() *sqlx.DB {
 	return r.db.GetDBInstance()
 }
 
+// GetReadDBInstance returns a read replica, or the primary database if there are no replicas,
+// ctx was created with udatabase.WithPrimary or ctx holds a transaction.
+func (r *DBrepository[T]) GetReadDBInstance(ctx context.Context) *sqlx.DB {
+	return r.db.GetReadDBInstance(ctx)
+}
+
 func (r *DBrepository[T]) GetTransaction(ctx context.Context) *sqlx.Tx {
 	txFromCtx := ctx.Value(ctxk(transactionName))
 	if txFromCtx == nil {
@@ -135,81 +238,324 @@ type Querier interface {
 	SelectContext(ctx context.Context, dest any, query string, args ...any) error
 }
 
-func (r *DBrepository[T]) GetContext(ctx context.Context, db Querier, dst T, query string, v url.Values) (T, error) {
+// GetContext runs query with the filters in v and scans the first row into dst.
+// When db is the primary database instance, the query runs in the transaction of ctx
+// if it is the transaction of a test, otherwise it is routed to a read replica.
+// If ctx has a tenant, the query runs against the schema of the tenant.
+func (r *DBrepository[T]) GetContext(ctx context.Context, db Querier, dst T, query string,
+	v url.Values) (_ T, rErr error) {
+	defer r.observe("GetContext", time.Now(), &rErr)
+
 	v.Del("limit")
 	v.Add("limit", "1")
 	query, args, _, _, err := r.ApplyFilters(db, query, v)
 	if err != nil {
 		return dst, err
 	}
-	err = db.GetContext(ctx, dst, query, args...)
+	q, err := r.readQuerier(ctx, db)
+	if err != nil {
+		return dst, err
+	}
+	err = q.GetContext(ctx, dst, query, args...)
 	return dst, r.HandleSearchError(err)
 }
 
+// SelectContext runs query with the filters in v and returns the rows found.
+// When db is the primary database instance, the query runs in the transaction of ctx
+// if it is the transaction of a test, otherwise it is routed to a read replica.
+// If ctx has a tenant, the query runs against the schema of the tenant. If ctx was created
+// with udatabase.WithTotal, the Total of the page is counted with the same filters.
 func (r *DBrepository[T]) SelectContext(ctx context.Context, db Querier, query string,
 	v url.Values) (rp *udatabase.ResourcePage[T], err error) {
-	query, args, limit, offset, err := r.ApplyFilters(db, query, v)
+	defer r.observe("SelectContext", time.Now(), &err)
+
+	query, countQuery, args, limit, offset, err := r.buildQueries(db, query, v)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := r.readQuerier(ctx, db)
 	if err != nil {
 		return nil, err
 	}
 
 	var dst []T
-	err = db.SelectContext(ctx, &dst, query, args...)
+	err = q.SelectContext(ctx, &dst, query, args...)
 	if err != nil {
 		return nil, r.HandleSearchError(err)
 	}
 
+	total := int64(-1)
+	if udatabase.CountTotal(ctx) {
+		err = q.GetContext(ctx, &total, countQuery, args...)
+		if err != nil {
+			return nil, r.HandleSearchError(err)
+		}
+	}
+
 	rp = &udatabase.ResourcePage[T]{
-		Total:     int64(len(dst)),
-		Limit:     limit,
-		Offset:    offset,
-		Resources: dst,
+		Limit:  limit,
+		Offset: offset,
 	}
+	rp.SetResources(dst, total)
 
 	return rp, nil
 }
 
+// SelectCursorContext runs query with the filters in v like SelectContext, but it paginates by
+// keyset instead of by offset: the "cursor" value of v is the NextCursor or PrevCursor of the
+// previous page, and rows are sorted by the sort of v followed by the unique key of its sorter
+// (see usqlFilters.KeysetSorter). The columns of the sort must be selected by query with the
+// names mapped to the fields of T, and must not be null.
+func (r *DBrepository[T]) SelectCursorContext(ctx context.Context, db Querier, query string,
+	v url.Values) (cp *udatabase.CursorPage[T], err error) {
+	defer r.observe("SelectCursorContext", time.Now(), &err)
+
+	query, args, keys, cursor, limit, err := r.buildCursorQuery(db, query, v)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := r.readQuerier(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var dst []T
+	err = q.SelectContext(ctx, &dst, query, args...)
+	if err != nil {
+		return nil, r.HandleSearchError(err)
+	}
+
+	cp = &udatabase.CursorPage[T]{Limit: limit}
+	err = cp.SetResources(r.db.config.CursorKey(), dst, cursor, sortValues(keys), r.keyValues(keys))
+	if err != nil {
+		return nil, uerr.NewError(uerr.GenericError, "Error encoding cursor.").WithCause(err)
+	}
+
+	return cp, nil
+}
+
+// readQuerier replaces db with a read replica when db is the primary database instance,
+// or with the transaction of ctx if it is the transaction of a test (see
+// udatabase.WithTestTransaction). If ctx has a tenant, queries run against the schema of
+// the tenant. Transactions and any other Querier are returned as they are.
+func (r *DBrepository[T]) readQuerier(ctx context.Context, db Querier) (Querier, error) {
+	sdb, ok := db.(*sqlx.DB)
+	if !ok {
+		return db, nil
+	}
+
+	if sdb == r.db.db {
+		if tx := r.GetTransaction(ctx); tx != nil && udatabase.IsTestTransaction(ctx) {
+			return tx, nil
+		}
+		sdb = r.db.GetReadDBInstance(ctx)
+	}
+
+	searchPath, err := r.db.config.TenantSearchPath(ctx)
+	if err != nil || searchPath == "" {
+		return sdb, err
+	}
+
+	return &tenantQuerier{db: sdb, searchPath: searchPath}, nil
+}
+
 func (r *DBrepository[T]) ApplyFilters(db Querier, query string, v url.Values) (queryResult string, args []any,
 	limit, offset int64, err error) {
+	queryResult, _, args, limit, offset, err = r.buildQueries(db, query, v)
+	return queryResult, args, limit, offset, err
+}
+
+// buildQueries returns query with the filters, sorting, limit and offset of v, and a query
+// that counts the rows matching the filters of v. Both take args.
+func (r *DBrepository[T]) buildQueries(db Querier, query string, v url.Values) (queryResult, countQuery string,
+	args []any, limit, offset int64, err error) {
 	limitQ, limit, err := r.applyLimit(v)
 	if err != nil {
-		return "", nil, 0, 0, err
+		return "", "", nil, 0, 0, err
 	}
 	offsetQ, offset, err := r.applyOffset(v)
 	if err != nil {
-		return "", nil, 0, 0, err
+		return "", "", nil, 0, 0, err
 	}
 
 	// Apply filters
-	conds, args, unknownFilters, err := r.applyFilters(v)
+	conds, sorts, args, unknownFilters, err := r.applyFilters(v)
 	if err != nil {
-		return "", nil, 0, 0, err
+		return "", "", nil, 0, 0, err
 	}
 
 	err = r.processUnknownFilters(unknownFilters)
 	if err != nil {
-		return "", nil, 0, 0, err
+		return "", "", nil, 0, 0, err
 	}
 
 	var sb strings.Builder
-	sb.Grow(len(query) + len(conds) + len(limitQ) + len(offsetQ))
+	sb.Grow(len(query) + len(conds) + len(sorts) + len(limitQ) + len(offsetQ))
 	sb.WriteString(query)
 	sb.WriteString(conds)
+	sb.WriteString(sorts)
 	sb.WriteString(limitQ)
 
 	if offset > 0 {
 		sb.WriteString(offsetQ)
 	}
 
-	query = sb.String()
+	queryResult = sb.String()
+	countQuery = "SELECT COUNT(*) FROM (" + query + conds + ") AS udatabase_count"
+	if len(args) > 0 {
+		queryResult = db.Rebind(queryResult)
+		countQuery = db.Rebind(countQuery)
+	}
+	return queryResult, countQuery, args, limit, offset, nil
+}
+
+// buildCursorQuery returns query with the filters of v, the rows after or before the cursor of v,
+// sorted by keys and limited to one row more than limit, to know if there are more.
+func (r *DBrepository[T]) buildCursorQuery(db Querier, query string, v url.Values) (queryResult string,
+	args []any, keys []filters.SortKey, cursor *udatabase.Cursor, limit int64, err error) {
+	if _, ok := v["offset"]; ok {
+		err = uerr.NewError(uerr.WrongInputParameterError, `"offset" cannot be used with cursor pagination.`)
+		return "", nil, nil, nil, 0, err
+	}
+
+	_, limit, err = r.applyLimit(v)
+	if err != nil {
+		return "", nil, nil, nil, 0, err
+	}
+
+	keys, err = r.sortKeys(v)
+	if err != nil {
+		return "", nil, nil, nil, 0, err
+	}
+
+	cursor, err = udatabase.DecodeCursor(r.db.config.CursorKey(), v.Get("cursor"), sortValues(keys))
+	if err != nil {
+		return "", nil, nil, nil, 0, err
+	}
+	v.Del("cursor")
+
+	conds, _, args, unknownFilters, err := r.applyFilters(v)
+	if err != nil {
+		return "", nil, nil, nil, 0, err
+	}
+
+	err = r.processUnknownFilters(unknownFilters)
+	if err != nil {
+		return "", nil, nil, nil, 0, err
+	}
+
+	backward := false
+	if cursor != nil {
+		var keyset string
+		var keysetArgs []any
+		keyset, keysetArgs, err = filters.ApplyKeyset(keys, cursor.Values, cursor.Backward)
+		if err != nil {
+			return "", nil, nil, nil, 0, err
+		}
+
+		if conds == "" {
+			conds = " WHERE " + keyset
+		} else {
+			conds += " AND " + keyset
+		}
+		args = append(args, keysetArgs...)
+		backward = cursor.Backward
+	}
+
+	var sb strings.Builder
+	sb.WriteString(query)
+	sb.WriteString(conds)
+	sb.WriteString(" ORDER BY ")
+	sb.WriteString(filters.KeysetOrder(keys, backward))
+	sb.WriteString(" LIMIT ")
+	sb.WriteString(strconv.FormatInt(limit+1, 10))
+
+	queryResult = sb.String()
 	if len(args) > 0 {
-		query = db.Rebind(query)
+		queryResult = db.Rebind(queryResult)
 	}
-	return query, args, limit, offset, nil
+	return queryResult, args, keys, cursor, limit, nil
 }
 
-func (r *DBrepository[T]) applyFilters(v url.Values) (conds string, args []any, unknown []string,
+// sortKeys returns the keys of the sorters in v, which are removed from v, followed by the unique
+// keys of the rest of sorters, or filters.DefaultUniqueKey if there are no keyset sorters.
+func (r *DBrepository[T]) sortKeys(v url.Values) ([]filters.SortKey, error) {
+	names := make([]string, 0, len(r.sorters))
+	for name := range r.sorters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var requested, unique [][]filters.SortKey
+	for _, name := range names {
+		values, ok := v[name]
+		sorter, isKeyset := r.sorters[name].(usqlFilters.KeysetSorter)
+		if !isKeyset {
+			if ok {
+				errMsg := fmt.Sprintf("Sort %q cannot be used with cursor pagination.", name)
+				return nil, uerr.NewError(uerr.WrongInputParameterError, errMsg)
+			}
+			continue
+		}
+
+		keys, err := sorter.Keys(values)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			requested = append(requested, keys)
+			v.Del(name)
+		} else {
+			unique = append(unique, keys)
+		}
+	}
+
+	keys := filters.MergeSortKeys(append(requested, unique...)...)
+	if len(keys) == 0 {
+		keys = []filters.SortKey{{Column: filters.DefaultUniqueKey}}
+	}
+	return keys, nil
+}
+
+// keyValues returns a function that returns the values of the fields of a resource mapped to the
+// columns of keys by the mapper of the DBHolder.
+func (r *DBrepository[T]) keyValues(keys []filters.SortKey) func(resource T) ([]any, error) {
+	mapper := r.db.db.Mapper
+	return func(resource T) ([]any, error) {
+		rv := reflect.Indirect(reflect.ValueOf(resource))
+		if rv.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%s is not a struct", r.name)
+		}
+
+		values := make([]any, len(keys))
+		for i, k := range keys {
+			field := mapper.FieldByName(rv, k.Name())
+			if !field.IsValid() {
+				return nil, fmt.Errorf("sort column %q is not mapped to a field of %s", k.Name(), r.name)
+			}
+			values[i] = field.Interface()
+		}
+		return values, nil
+	}
+}
+
+func sortValues(keys []filters.SortKey) []string {
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = k.String()
+	}
+	return values
+}
+
+func (r *DBrepository[T]) applyFilters(v url.Values) (conds, sorts string, args []any, unknown []string,
 	err error) {
+	if err = usqlFilters.CheckConstraints(r.filters, v); err != nil {
+		return "", "", nil, nil, err
+	}
+
 	args = make([]any, 0, len(v))
 	var sbConds, sbSorts strings.Builder
 	var cSep, sSep string
@@ -229,7 +575,7 @@ func (r *DBrepository[T]) applyFilters(v url.Values) (conds string, args []any,
 
 			sort, err := sorter.Apply(values)
 			if err != nil {
-				return "", nil, nil, err
+				return "", "", nil, nil, err
 			}
 
 			sbSorts.WriteString(sSep)
@@ -240,7 +586,7 @@ func (r *DBrepository[T]) applyFilters(v url.Values) (conds string, args []any,
 
 		cond, fArgs, err := filter.Apply(values)
 		if err != nil {
-			return "", nil, nil, err
+			return "", "", nil, nil, err
 		}
 
 		sbConds.WriteString(cSep)
@@ -250,19 +596,15 @@ func (r *DBrepository[T]) applyFilters(v url.Values) (conds string, args []any,
 		args = append(args, fArgs...)
 	}
 
-	var sb strings.Builder
-	sb.Grow(7 + sbConds.Len() + 10 + sbSorts.Len())
 	if cSep != "" {
-		sb.WriteString(" WHERE ")
-		sb.WriteString(sbConds.String())
+		conds = " WHERE " + sbConds.String()
 	}
 
 	if sSep != "" {
-		sb.WriteString(" ORDER BY ")
-		sb.WriteString(sbSorts.String())
+		sorts = " ORDER BY " + sbSorts.String()
 	}
 
-	return sb.String(), args, unknown, nil
+	return conds, sorts, args, unknown, nil
 }
 
 func (r *DBrepository[T]) applyLimit(v url.Values) (limitQ string, limitNum int64, rErr error) {
@@ -275,7 +617,7 @@ func (r *DBrepository[T]) applyLimit(v url.Values) (limitQ string, limitNum int6
 		return filters.DefaultLimitStr, filters.DefaultLimit, nil
 	}
 
-	return filters.ApplyLimit("", limit)
+	return filters.ApplyLimitWithMax("", limit, r.maxLimit)
 }
 
 func (r *DBrepository[T]) applyOffset(v url.Values) (offsetQ string, offsetNum int64, rErr error) {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/carlosarismendi/testhelper"
@@ -136,6 +138,51 @@ func TestTransactions(t *testing.T) {
 		}()
 		require.NoError(t, err)
 	})
+	t.Run("committingNestedTransactionWithDeferredRollback_keepsOuterTransaction", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		createResourceTable(t, r)
+		ctx := dbHolder.BeginTestTx(t)
+
+		resource := &Resource{
+			ID:           "0ea57dec-5e79-40dc-b971-a52561fcc2c7",
+			Name:         "Resource name",
+			RandomNumber: 4,
+		}
+
+		// ACT
+		err := func() error {
+			outerCtx, err := r.Begin(ctx)
+			if err != nil {
+				return err
+			}
+			defer r.Rollback(outerCtx)
+
+			err = func() error {
+				innerCtx, err := r.Begin(outerCtx)
+				if err != nil {
+					return err
+				}
+				defer r.Rollback(innerCtx)
+
+				if err = save(innerCtx, r, resource); err != nil {
+					return err
+				}
+				return r.Commit(innerCtx)
+			}()
+			if err != nil {
+				return err
+			}
+			return r.Commit(outerCtx)
+		}()
+
+		// ASSERT
+		require.NoError(t, err)
+		rp, err := r.SelectContext(ctx, r.GetDBInstance(),
+			"SELECT id, name, random_number as randomnumber FROM resources", url.Values{})
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, []*Resource{resource}, rp.Resources)
+	})
 }
 
 func TestInsertErrors(t *testing.T) {
@@ -332,6 +379,69 @@ func TestSelectContextWithFilters(t *testing.T) {
 	}
 }
 
+type product struct {
+	ID string
+}
+
+func TestSelectContextWithJSONAndArrayFields(t *testing.T) {
+	// ARRANGE
+	dbHolder := NewTestDBHolder("db_usql_repository_test_json_array")
+	dbHolder.Reset()
+
+	r := NewDBRepository[*product](dbHolder.DBHolder, map[string]usqlFilters.Filter{
+		"attributes": usqlFilters.JSONField("attributes"),
+		"color":      usqlFilters.JSONField("attributes").WithKey("color"),
+		"unit":       usqlFilters.JSONField("attributes").WithKey("size", "unit"),
+		"path":       usqlFilters.JSONField("attributes").WithMode(filters.JSONPathExists),
+		"tag":        usqlFilters.ArrayField("tags"),
+		"tags":       usqlFilters.ArrayField("tags").WithMode(filters.ArrayContains),
+		"sizes":      usqlFilters.ArrayField("sizes").WithMode(filters.ArrayOverlaps).WithElementType("integer"),
+	}, map[string]usqlFilters.Sorter{"sort": usqlFilters.Sort("id")})
+	_, err := r.GetDBInstance().Exec("CREATE TABLE products (id TEXT PRIMARY KEY, attributes JSONB, tags TEXT[], " +
+		"sizes INTEGER[])")
+	require.NoError(t, err)
+	_, err = r.GetDBInstance().Exec(`INSERT INTO products VALUES
+		('p1', '{"color": "red", "size": {"value": 10, "unit": "cm"}}', '{new,sale}', '{1,2}'),
+		('p2', '{"color": "blue", "size": {"value": 3, "unit": "mm"}}', '{sale}', '{3}'),
+		('p3', NULL, NULL, NULL)`)
+	require.NoError(t, err)
+	query := "SELECT id FROM products"
+
+	tests := []struct {
+		name     string
+		v        url.Values
+		expected []string
+	}{
+		{name: "JSONContains", v: url.Values{"attributes": {`{"size": {"unit": "cm"}}`}}, expected: []string{"p1"}},
+		{name: "JSONKeyEquals", v: url.Values{"color": {"red", "blue"}}, expected: []string{"p1", "p2"}},
+		{name: "JSONNestedKeyNotEqual", v: url.Values{"unit": {"ne:cm"}}, expected: []string{"p2"}},
+		{name: "JSONPathExists", v: url.Values{"path": {"$.size.value ? (@ > 5)"}}, expected: []string{"p1"}},
+		{name: "JSONNull", v: url.Values{"attributes": {"null"}}, expected: []string{"p3"}},
+		{name: "ArrayAny", v: url.Values{"tag": {"new"}}, expected: []string{"p1"}},
+		{name: "ArrayContains", v: url.Values{"tags": {"sale", "new"}}, expected: []string{"p1"}},
+		{name: "ArrayNotAny", v: url.Values{"tags": {"ne:new"}}, expected: []string{"p2"}},
+		{name: "ArrayOverlapsIntegers", v: url.Values{"sizes": {"2", "3"}}, expected: []string{"p1", "p2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ARRANGE
+			tt.v.Set("sort", "id")
+
+			// ACT
+			rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, tt.v)
+
+			// ASSERT
+			require.NoError(t, err)
+			ids := make([]string, 0, len(rp.Resources))
+			for _, p := range rp.Resources {
+				ids = append(ids, p.ID)
+			}
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
 func TestSelectContextWithSorters(t *testing.T) {
 	dbHolder := NewTestDBHolder("db_usql_repository_test_select_context")
 	dbHolder.Reset()
@@ -600,6 +710,107 @@ func TestGetContext(t *testing.T) {
 	}
 }
 
+func TestTenants(t *testing.T) {
+	migrationsDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(migrationsDir, "1_create_resources.up.sql"),
+		[]byte("CREATE TABLE resources (id UUID PRIMARY KEY, name TEXT, random_number INTEGER);"), 0o600)
+	require.NoError(t, err)
+
+	cfg := udatabase.NewDBConfigFromEnv()
+	cfg.SchemaName = "db_usql_repository_test_tenants"
+	cfg.MigrationsDir = migrationsDir
+	cfg.TenantSchemaPrefix = "db_usql_repository_test_tenant_"
+	dbHolder := NewDBHolder(cfg)
+	r := NewDBRepository[*Resource](dbHolder, nil, nil)
+
+	ctx := context.Background()
+	for _, tenantID := range []string{"a", "b"} {
+		_, err = r.GetDBInstance().Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s%s CASCADE;", cfg.TenantSchemaPrefix, tenantID))
+		require.NoError(t, err)
+		require.NoError(t, dbHolder.ProvisionTenant(ctx, tenantID))
+	}
+	ctxA := udatabase.WithTenant(ctx, "a")
+	ctxB := udatabase.WithTenant(ctx, "b")
+
+	t.Run("ProvisionedTenants_areListed", func(t *testing.T) {
+		// ACT
+		tenantIDs, err := udatabase.ListTenants(ctx, r.GetDBInstance().DB, cfg)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, tenantIDs)
+	})
+
+	t.Run("SavingResourceInTenantTransaction_isOnlyVisibleToThatTenant", func(t *testing.T) {
+		// ARRANGE
+		resource := &Resource{
+			ID:           "0ea57dec-5e79-40dc-b971-a52561fcc2c7",
+			Name:         "Resource name",
+			RandomNumber: 4,
+		}
+
+		txCtx, err := r.Begin(ctxA)
+		require.NoError(t, err)
+		require.NoError(t, save(txCtx, r, resource))
+		require.NoError(t, r.Commit(txCtx))
+
+		// ACT
+		query := "SELECT id, name, random_number as RandomNumber FROM resources"
+		rpA, errA := r.SelectContext(ctxA, r.GetDBInstance(), query, url.Values{})
+		rpB, errB := r.SelectContext(ctxB, r.GetDBInstance(), query, url.Values{})
+
+		// ASSERT
+		require.NoError(t, errA)
+		require.NoError(t, errB)
+		testhelper.RequireEqual(t, []*Resource{resource}, rpA.Resources)
+		require.Empty(t, rpB.Resources)
+	})
+
+	t.Run("InvalidTenant_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, err := r.Begin(udatabase.WithTenant(ctx, "a; DROP TABLE resources"))
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	// ARRANGE
+	maxNumber := 10.0
+	filtersMap := map[string]usqlFilters.Filter{
+		"name": usqlFilters.TextField("name").WithConstraints(filters.Constraints{Required: true}),
+		"random_number": usqlFilters.NumField("random_number").
+			WithConstraints(filters.Constraints{Max: &maxNumber}),
+	}
+	filtersMap[filters.ExpressionParam] = usqlFilters.Expression(filtersMap)
+	r := NewDBRepository[*Resource](nil, filtersMap,
+		map[string]usqlFilters.Sorter{"sort": usqlFilters.Sort("name")}).WithMaxLimit(20)
+
+	// ACT
+	descriptions := r.Describe()
+
+	// ASSERT
+	names := make([]string, 0, len(descriptions))
+	for _, d := range descriptions {
+		names = append(names, d.Name)
+	}
+	require.Equal(t, []string{"filter", "limit", "name", "offset", "random_number", "sort"}, names)
+	require.Equal(t, filters.ExpressionValue, descriptions[0].Type)
+	require.Equal(t, "10", descriptions[1].Default)
+	require.Equal(t, 20.0, *descriptions[1].Constraints.Max)
+	require.Equal(t, filters.TextValue, descriptions[2].Type)
+	require.True(t, descriptions[2].Constraints.Required)
+	require.Equal(t, filters.IntegerValue, descriptions[4].Type)
+	require.Equal(t, filters.NumOperators, descriptions[4].Operators)
+	require.Equal(t, []string{"name"}, descriptions[5].SortFields)
+
+	params := filters.ParametersJSONSchema(descriptions)
+	require.Equal(t, []string{"name"}, params.Required)
+	require.Equal(t, []string{"name", "-name"}, params.Properties["sort"].Items.Enum)
+}
+
 func populateDB(ctx context.Context, t testing.TB, r *DBrepository[*Resource]) (r1, r2, r3, r4 *Resource) {
 	ctx, err := r.Begin(ctx)
 	require.NoError(t, err)
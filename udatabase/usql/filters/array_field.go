@@ -0,0 +1,63 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	"github.com/carlosarismendi/utils/udatabase/ident"
+)
+
+type ArrayFieldFilter struct {
+	field       string
+	opts        filters.ArrayOptions
+	constraints filters.Constraints
+}
+
+// ArrayField returns a filter of a Postgres array column, see filters.ApplyArrayField.
+func ArrayField(field string) *ArrayFieldFilter {
+	return &ArrayFieldFilter{
+		field: field,
+	}
+}
+
+// WithMode sets how the filter matches the column with the values. It is filters.ArrayAny by default.
+func (f *ArrayFieldFilter) WithMode(mode filters.ArrayMode) *ArrayFieldFilter {
+	f.opts.Mode = mode
+	return f
+}
+
+// WithElementType sets the type of the elements of the column, e.g. integer for integer[]. It is
+// filters.DefaultArrayElementType by default. It panics if elementType is not a valid identifier.
+func (f *ArrayFieldFilter) WithElementType(elementType string) *ArrayFieldFilter {
+	if err := ident.Validate(elementType); err != nil {
+		panic(err)
+	}
+	f.opts.ElementType = elementType
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *ArrayFieldFilter) WithConstraints(constraints filters.Constraints) *ArrayFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *ArrayFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *ArrayFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.ArrayValue,
+		Operators:   filters.EqualityOperators,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *ArrayFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	return filters.ApplyArrayField(f.field, f.opts, values...)
+}
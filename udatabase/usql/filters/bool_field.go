@@ -0,0 +1,45 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
+type BoolFieldFilter struct {
+	field       string
+	constraints filters.Constraints
+}
+
+// BoolField returns a filter of a boolean column, see filters.ApplyBoolField.
+func BoolField(field string) *BoolFieldFilter {
+	return &BoolFieldFilter{
+		field: field,
+	}
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *BoolFieldFilter) WithConstraints(constraints filters.Constraints) *BoolFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *BoolFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.BoolOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *BoolFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.BooleanValue,
+		Operators:   filters.BoolOperators,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *BoolFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	return filters.ApplyBoolField(f.field, values...)
+}
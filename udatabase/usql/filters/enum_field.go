@@ -0,0 +1,52 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
+type EnumFieldFilter struct {
+	field       string
+	allowed     []string
+	constraints filters.Constraints
+}
+
+// EnumField returns a filter of a column whose values must be one of allowed, see
+// filters.ApplyEnumField. It panics if there are no allowed values.
+func EnumField(field string, allowed ...string) *EnumFieldFilter {
+	if len(allowed) == 0 {
+		panic("EnumField requires at least one allowed value")
+	}
+	return &EnumFieldFilter{
+		field:   field,
+		allowed: allowed,
+	}
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *EnumFieldFilter) WithConstraints(constraints filters.Constraints) *EnumFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *EnumFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *EnumFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.EnumValue,
+		Operators:   filters.EqualityOperators,
+		Enum:        f.allowed,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *EnumFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	return filters.ApplyEnumField(f.field, f.allowed, values...)
+}
@@ -0,0 +1,83 @@
+package filters
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
+type ExpressionFilter struct {
+	filters     map[string]Filter
+	constraints filters.Constraints
+}
+
+// Expression returns a filter of filter expressions, see filters.ParseExpression, whose conditions
+// apply the filters of filtersMap, e.g. ?filter=status=open OR assignee=me, after checking their
+// constraints (see ConstrainedFilter) with all of their values in the expressions. Register it in
+// filtersMap as filters.ExpressionParam to accept expressions.
+func Expression(filtersMap map[string]Filter) *ExpressionFilter {
+	return &ExpressionFilter{
+		filters: filtersMap,
+	}
+}
+
+// WithConstraints sets the constraints that the expressions must meet, see filters.Constraints,
+// e.g. MaxValues limits how many times the parameter can be repeated.
+// It panics if constraints are not valid.
+func (f *ExpressionFilter) WithConstraints(constraints filters.Constraints) *ExpressionFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *ExpressionFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, nil, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *ExpressionFilter) Describe() filters.Description {
+	return filters.Description{Type: filters.ExpressionValue, Constraints: f.constraints}
+}
+
+// Apply applies the expressions of values, which must all match.
+func (f *ExpressionFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	if len(values) == 0 {
+		return "", nil, nil
+	}
+
+	exprs, err := filters.ParseExpressions(values)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err = f.checkConstraints(exprs); err != nil {
+		return "", nil, err
+	}
+	return filters.CompileExpression(exprs, f.applyFilter)
+}
+
+// checkConstraints returns a WrongInputParameterError with the violations of the constraints of
+// the filters of the conditions of e by all of their values in e, see filters.ConditionValues.
+func (f *ExpressionFilter) checkConstraints(e filters.Expr) error {
+	values := filters.ConditionValues(e)
+	var violations []string
+	for _, field := range slices.Sorted(maps.Keys(values)) {
+		filter, ok := f.filters[field].(ConstrainedFilter)
+		if _, nested := filter.(*ExpressionFilter); ok && !nested {
+			violations = append(violations, filter.CheckConstraints(field, values[field])...)
+		}
+	}
+	return filters.ConstraintsError(violations)
+}
+
+func (f *ExpressionFilter) applyFilter(field string, values ...string) (string, []interface{}, error) {
+	filter, ok := f.filters[field]
+	if _, nested := filter.(*ExpressionFilter); !ok || nested {
+		return "", nil, filters.InvalidExpressionFilterError(field)
+	}
+	return filter.Apply(values)
+}
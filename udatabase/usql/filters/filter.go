@@ -1,9 +1,62 @@
 package filters
 
+import (
+	"maps"
+	"net/url"
+	"slices"
+
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
 type Sorter interface {
 	Apply(values []string) (string, error)
 }
 
+// KeysetSorter is a Sorter that can sort keyset paginations. Keys returns the columns to sort by
+// for values, ending with a unique column, or only the unique column if values is empty.
+type KeysetSorter interface {
+	Sorter
+	Keys(values []string) ([]filters.SortKey, error)
+}
+
 type Filter interface {
 	Apply(values []string) (string, []interface{}, error)
 }
+
+// Describer is implemented by the filters and sorters that describe their values, so that the
+// documentation of the query parameters of repositories can be generated, see filters.Description.
+type Describer interface {
+	Describe() filters.Description
+}
+
+// ConstrainedFilter is a Filter whose values must meet constraints, see filters.Constraints.
+// CheckConstraints returns the violations of the constraints by the values of the filter named
+// name, which are empty if it is not set.
+type ConstrainedFilter interface {
+	Filter
+	CheckConstraints(name string, values []string) []string
+}
+
+// CheckConstraints returns a WrongInputParameterError with the violations of the constraints of
+// every ConstrainedFilter of filtersMap by the values of v, or nil if there are none. Required
+// filters are also met by the top-level AND conditions of the expressions of the ExpressionFilter
+// of filtersMap, which checks their values, see filters.ConjunctFields.
+func CheckConstraints(filtersMap map[string]Filter, v url.Values) error {
+	var inExpressions map[string]bool
+	if _, ok := filtersMap[filters.ExpressionParam].(*ExpressionFilter); ok {
+		// Expressions that cannot be parsed fail when they are applied.
+		if exprs, err := filters.ParseExpressions(v[filters.ExpressionParam]); err == nil {
+			inExpressions = filters.ConjunctFields(exprs)
+		}
+	}
+
+	var violations []string
+	for _, name := range slices.Sorted(maps.Keys(filtersMap)) {
+		filter, ok := filtersMap[name].(ConstrainedFilter)
+		if !ok || len(v[name]) == 0 && inExpressions[name] {
+			continue
+		}
+		violations = append(violations, filter.CheckConstraints(name, v[name])...)
+	}
+	return filters.ConstraintsError(violations)
+}
@@ -0,0 +1,67 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
+type FloatFieldFilter struct {
+	field       string
+	opts        filters.FloatOptions
+	constraints filters.Constraints
+}
+
+// FloatField returns a filter of a floating point or decimal column, see filters.ApplyFloatField.
+func FloatField(field string) *FloatFieldFilter {
+	return &FloatFieldFilter{
+		field: field,
+	}
+}
+
+// WithOperators sets the operators allowed in the values of the filter, e.g. gte:9.99.
+// They are filters.NumOperators by default.
+func (f *FloatFieldFilter) WithOperators(operators ...filters.Operator) *FloatFieldFilter {
+	f.opts.Operators = operators
+	return f
+}
+
+// WithDecimal passes the values to the query as decimal strings, so that numeric columns compare
+// them without rounding errors. Use it for numeric and decimal columns.
+func (f *FloatFieldFilter) WithDecimal() *FloatFieldFilter {
+	f.opts.Decimal = true
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *FloatFieldFilter) WithConstraints(constraints filters.Constraints) *FloatFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *FloatFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, f.operators(), values)
+}
+
+func (f *FloatFieldFilter) operators() []filters.Operator {
+	if len(f.opts.Operators) == 0 {
+		return filters.NumOperators
+	}
+	return f.opts.Operators
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *FloatFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.NumberValue,
+		Operators:   f.operators(),
+		Constraints: f.constraints,
+	}
+}
+
+func (f *FloatFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	return filters.ApplyFloatField(f.field, f.opts, values...)
+}
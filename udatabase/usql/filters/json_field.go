@@ -0,0 +1,64 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
+type JSONFieldFilter struct {
+	field       string
+	opts        filters.JSONOptions
+	constraints filters.Constraints
+}
+
+// JSONField returns a filter of a Postgres jsonb column, see filters.ApplyJSONField. It matches
+// the columns that contain the values, which are JSON documents, unless it has another mode.
+func JSONField(field string) *JSONFieldFilter {
+	return &JSONFieldFilter{
+		field: field,
+	}
+}
+
+// WithMode sets how the filter matches the column with the values. It is filters.JSONContains by default.
+func (f *JSONFieldFilter) WithMode(mode filters.JSONMode) *JSONFieldFilter {
+	f.opts.Mode = mode
+	return f
+}
+
+// WithKey makes the filter compare the text of the key of the path of object keys with the
+// values, e.g. WithKey("size", "unit") for attributes->'size'->>'unit'. It panics if key is empty.
+func (f *JSONFieldFilter) WithKey(key ...string) *JSONFieldFilter {
+	if len(key) == 0 {
+		panic("WithKey requires at least one key")
+	}
+	f.opts.Mode = filters.JSONKeyEquals
+	f.opts.Key = key
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *JSONFieldFilter) WithConstraints(constraints filters.Constraints) *JSONFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *JSONFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *JSONFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.JSONValue,
+		Operators:   filters.EqualityOperators,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *JSONFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	return filters.ApplyJSONField(f.field, f.opts, values...)
+}
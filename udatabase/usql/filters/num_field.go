@@ -5,15 +5,49 @@ import (
 )
 
 type NumFieldFilter struct {
-	field string
+	field       string
+	operators   []filters.Operator
+	constraints filters.Constraints
 }
 
 func NumField(field string) *NumFieldFilter {
 	return &NumFieldFilter{
-		field: field,
+		field:     field,
+		operators: filters.NumOperators,
+	}
+}
+
+// WithOperators sets the operators allowed in the values of the filter, e.g. gte:10.
+// They are filters.NumOperators by default.
+func (f *NumFieldFilter) WithOperators(operators ...filters.Operator) *NumFieldFilter {
+	f.operators = operators
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *NumFieldFilter) WithConstraints(constraints filters.Constraints) *NumFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *NumFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, f.operators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *NumFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.IntegerValue,
+		Operators:   f.operators,
+		Constraints: f.constraints,
 	}
 }
 
 func (f *NumFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
-	return filters.ApplyNumField(f.field, values...)
+	return filters.ApplyNumFieldWithOperators(f.field, f.operators, values...)
 }
@@ -2,22 +2,52 @@ package filters
 
 import (
 	"github.com/carlosarismendi/utils/udatabase/filters"
+	"github.com/carlosarismendi/utils/udatabase/ident"
 )
 
 type SortFilter struct {
-	allowedFields map[string]bool
+	fields    filters.SortFields
+	uniqueKey string
 }
 
 func Sort(fields ...string) *SortFilter {
-	allowedFields := make(map[string]bool)
+	sortFields := make(filters.SortFields)
 	for _, f := range fields {
-		allowedFields[f] = true
+		sortFields[f] = f
 	}
 	return &SortFilter{
-		allowedFields: allowedFields,
+		fields:    sortFields,
+		uniqueKey: filters.DefaultUniqueKey,
 	}
 }
 
+// WithAlias adds the sort field name, which sorts by the column or SQL expression expr, e.g.
+// WithAlias("user", "u.user_id") or WithAlias("name", "lower(name)"), so that the API does not
+// expose the schema. It panics if expr is not valid for ident.ValidateExpression.
+func (f *SortFilter) WithAlias(name, expr string) *SortFilter {
+	if err := ident.ValidateExpression(expr); err != nil {
+		panic(err)
+	}
+	f.fields[name] = expr
+	return f
+}
+
+// WithUniqueKey sets the unique and not null column that keyset paginations sort by after the
+// fields of the sort. It is filters.DefaultUniqueKey by default.
+func (f *SortFilter) WithUniqueKey(column string) *SortFilter {
+	f.uniqueKey = column
+	return f
+}
+
+// Describe returns the description of the sorter, see Describer.
+func (f *SortFilter) Describe() filters.Description {
+	return filters.DescribeSortFields(f.fields)
+}
+
 func (f *SortFilter) Apply(values []string) (string, error) {
-	return filters.ApplySorter(f.allowedFields, values...)
+	return filters.ApplySortFields(f.fields, values...)
+}
+
+func (f *SortFilter) Keys(values []string) ([]filters.SortKey, error) {
+	return filters.SortFieldKeys(f.fields, f.uniqueKey, values...)
 }
@@ -1,11 +1,11 @@
 package filters
 
-import (
-	"github.com/carlosarismendi/utils/udatabase/filters"
-)
+import "github.com/carlosarismendi/utils/udatabase/filters"
 
 type TextFieldFilter struct {
-	field string
+	field       string
+	opts        filters.TextOptions
+	constraints filters.Constraints
 }
 
 func TextField(field string) *TextFieldFilter {
@@ -14,6 +14,52 @@ func TextField(field string) *TextFieldFilter {
 	}
 }
 
+// WithMode sets how the filter compares the column with the values. It is filters.TextExact by default.
+func (f *TextFieldFilter) WithMode(mode filters.TextMode) *TextFieldFilter {
+	f.opts.Mode = mode
+	return f
+}
+
+// WithIgnoreCase makes the filter case-insensitive.
+func (f *TextFieldFilter) WithIgnoreCase() *TextFieldFilter {
+	f.opts.IgnoreCase = true
+	return f
+}
+
+// WithLanguage sets the text search configuration of filters.TextSearch, e.g. english.
+// It panics if language is not a valid unquoted identifier, see filters.ValidateTextSearchLanguage.
+func (f *TextFieldFilter) WithLanguage(language string) *TextFieldFilter {
+	if err := filters.ValidateTextSearchLanguage(language); err != nil {
+		panic(err)
+	}
+	f.opts.Language = language
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *TextFieldFilter) WithConstraints(constraints filters.Constraints) *TextFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *TextFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.TextOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *TextFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.TextValue,
+		Operators:   filters.TextOperators,
+		Constraints: f.constraints,
+	}
+}
+
 func (f *TextFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
-	return filters.ApplyTextField(f.field, values...)
+	return filters.ApplyTextFieldWithOptions(f.field, f.opts, values...)
 }
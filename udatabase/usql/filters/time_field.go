@@ -0,0 +1,68 @@
+package filters
+
+import (
+	"time"
+
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
+type TimeFieldFilter struct {
+	field       string
+	opts        filters.TimeOptions
+	constraints filters.Constraints
+}
+
+// TimeField returns a filter of a timestamp or date column, see filters.ApplyTimeField.
+func TimeField(field string) *TimeFieldFilter {
+	return &TimeFieldFilter{
+		field: field,
+	}
+}
+
+// WithOperators sets the operators allowed in the values of the filter, e.g. gte:2024-01-01.
+// They are filters.TimeOperators by default.
+func (f *TimeFieldFilter) WithOperators(operators ...filters.Operator) *TimeFieldFilter {
+	f.opts.Operators = operators
+	return f
+}
+
+// WithLocation sets the time zone of the values without offset, such as dates. It is UTC by default.
+func (f *TimeFieldFilter) WithLocation(loc *time.Location) *TimeFieldFilter {
+	f.opts.Location = loc
+	return f
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *TimeFieldFilter) WithConstraints(constraints filters.Constraints) *TimeFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *TimeFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, f.operators(), values)
+}
+
+func (f *TimeFieldFilter) operators() []filters.Operator {
+	if len(f.opts.Operators) == 0 {
+		return filters.TimeOperators
+	}
+	return f.opts.Operators
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *TimeFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.TimeValue,
+		Operators:   f.operators(),
+		Constraints: f.constraints,
+	}
+}
+
+func (f *TimeFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	return filters.ApplyTimeField(f.field, f.opts, values...)
+}
@@ -0,0 +1,45 @@
+package filters
+
+import (
+	"github.com/carlosarismendi/utils/udatabase/filters"
+)
+
+type UUIDFieldFilter struct {
+	field       string
+	constraints filters.Constraints
+}
+
+// UUIDField returns a filter of a UUID column, see filters.ApplyUUIDField.
+func UUIDField(field string) *UUIDFieldFilter {
+	return &UUIDFieldFilter{
+		field: field,
+	}
+}
+
+// WithConstraints sets the constraints that the values of the filter must meet, see filters.Constraints.
+// It panics if constraints are not valid.
+func (f *UUIDFieldFilter) WithConstraints(constraints filters.Constraints) *UUIDFieldFilter {
+	if err := constraints.Validate(); err != nil {
+		panic(err)
+	}
+	f.constraints = constraints
+	return f
+}
+
+// CheckConstraints returns the violations of the constraints of the filter by values, see ConstrainedFilter.
+func (f *UUIDFieldFilter) CheckConstraints(name string, values []string) []string {
+	return f.constraints.Check(name, filters.EqualityOperators, values)
+}
+
+// Describe returns the description of the filter, see Describer.
+func (f *UUIDFieldFilter) Describe() filters.Description {
+	return filters.Description{
+		Type:        filters.UUIDValue,
+		Operators:   filters.EqualityOperators,
+		Constraints: f.constraints,
+	}
+}
+
+func (f *UUIDFieldFilter) Apply(values []string) (queryResult string, args []interface{}, rErr error) {
+	return filters.ApplyUUIDField(f.field, values...)
+}
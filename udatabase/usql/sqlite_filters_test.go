@@ -0,0 +1,316 @@
+package usql
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carlosarismendi/testhelper"
+	"github.com/carlosarismendi/utils/udatabase/filters"
+	usqlFilters "github.com/carlosarismendi/utils/udatabase/usql/filters"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+const sqliteResourcesQuery = "SELECT id, name, random_number as randomnumber FROM resources"
+
+// newSQLiteRepository returns a repository with filtersMap and sortersMap of a new SQLite database
+// populated with the resources of populateDB, which are returned too.
+func newSQLiteRepository(t *testing.T, schemaName string, filtersMap map[string]usqlFilters.Filter,
+	sortersMap map[string]usqlFilters.Sorter) (r *DBrepository[*Resource], r1, r2, r3, r4 *Resource) {
+	dbHolder := newSQLiteTestDBHolder(t, schemaName)
+	dbHolder.Reset()
+	r = NewDBRepository[*Resource](dbHolder.DBHolder, filtersMap, sortersMap)
+	r1, r2, r3, r4 = populateDB(context.Background(), t, r)
+	return r, r1, r2, r3, r4
+}
+
+type sqliteFindTest struct {
+	name           string
+	v              url.Values
+	expected       []*Resource
+	expectedErrKey string
+}
+
+func (ft *sqliteFindTest) testSelectContext(r *DBrepository[*Resource], query string) func(*testing.T) {
+	return func(t *testing.T) {
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, ft.v)
+
+		// ASSERT
+		if ft.expectedErrKey != "" {
+			require.Error(t, err)
+			require.Equal(t, ft.expectedErrKey, uerr.GetKey(err))
+			return
+		}
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, ft.expected, rp.Resources)
+	}
+}
+
+type event struct {
+	ID        string
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func TestSQLiteTimeField(t *testing.T) {
+	// ARRANGE
+	dbHolder := newSQLiteTestDBHolder(t, "db_usql_repository_test_sqlite_time")
+	dbHolder.Reset()
+	_, err := dbHolder.GetDBInstance().Exec("CREATE TABLE events (id TEXT PRIMARY KEY, created_at TIMESTAMP NOT NULL)")
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	e1 := &event{ID: "1", CreatedAt: now.AddDate(0, 0, -10)}
+	e2 := &event{ID: "2", CreatedAt: now.AddDate(0, 0, -3)}
+	e3 := &event{ID: "3", CreatedAt: now.Add(-time.Hour)}
+	for _, e := range []*event{e1, e2, e3} {
+		_, err = dbHolder.GetDBInstance().Exec("INSERT INTO events (id, created_at) VALUES (?, ?)", e.ID, e.CreatedAt)
+		require.NoError(t, err)
+	}
+
+	r := NewDBRepository[*event](dbHolder.DBHolder,
+		map[string]usqlFilters.Filter{"created_at": usqlFilters.TimeField("created_at")},
+		map[string]usqlFilters.Sorter{"sort": usqlFilters.Sort("created_at")})
+	query := "SELECT id, created_at FROM events"
+
+	t.Run("RelativeBound_returnsRecentEvents", func(t *testing.T) {
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query,
+			url.Values{"created_at": {"gte:now-7d"}, "sort": {"created_at"}})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []string{e2.ID, e3.ID}, eventIDs(rp.Resources))
+	})
+
+	t.Run("Between_returnsEventsInRange", func(t *testing.T) {
+		// ARRANGE
+		from := now.AddDate(0, 0, -11).Format(time.RFC3339)
+		to := now.AddDate(0, 0, -2).Format(time.RFC3339)
+
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query,
+			url.Values{"created_at": {"between:" + from + "," + to}, "sort": {"created_at"}})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, []string{e1.ID, e2.ID}, eventIDs(rp.Resources))
+	})
+
+	t.Run("InvalidTime_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		_, err := r.SelectContext(context.Background(), r.GetDBInstance(), query,
+			url.Values{"created_at": {"last week"}})
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}
+
+func eventIDs(events []*event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestSQLiteTextFieldModes(t *testing.T) {
+	r, r1, r2, r3, r4 := newSQLiteRepository(t, "db_usql_repository_test_sqlite_text", map[string]usqlFilters.Filter{
+		"name":          usqlFilters.TextField("name").WithIgnoreCase(),
+		"name_contains": usqlFilters.TextField("name").WithMode(filters.TextContains),
+		"name_prefix":   usqlFilters.TextField("name").WithMode(filters.TextStartsWith),
+	}, map[string]usqlFilters.Sorter{"sort": usqlFilters.Sort("name", "random_number")})
+	sort := []string{"name", "random_number"}
+
+	tests := []sqliteFindTest{
+		{name: "IgnoringCase_returnsEqualResources", v: url.Values{"name": {"RESOURCE1"}, "sort": sort},
+			expected: []*Resource{r1}},
+		{name: "ContainsUnderscore_isNotWildcard", v: url.Values{"name_contains": {"_"}, "sort": sort}},
+		{name: "ContainsPercent_isNotWildcard", v: url.Values{"name_contains": {"%"}, "sort": sort}},
+		{name: "StartsWith_returnsResourcesWithPrefix", v: url.Values{"name_prefix": {"Resource1", "Resource2"},
+			"sort": sort}, expected: []*Resource{r1, r2}},
+		{name: "NotContains_returnsOtherResources", v: url.Values{"name_contains": {"ne:3"}, "sort": sort},
+			expected: []*Resource{r1, r2}},
+		{name: "NotEqualIgnoringCase_returnsOtherResources", v: url.Values{"name": {"ne:RESOURCE1"}, "sort": sort},
+			expected: []*Resource{r2, r4, r3}},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testSelectContext(r, sqliteResourcesQuery))
+	}
+}
+
+func TestSQLiteFieldTypes(t *testing.T) {
+	r, r1, r2, r3, r4 := newSQLiteRepository(t, "db_usql_repository_test_sqlite_types", map[string]usqlFilters.Filter{
+		"id":            usqlFilters.UUIDField("id"),
+		"name":          usqlFilters.EnumField("name", "Resource1", "Resource2", "Resource3"),
+		"random_number": usqlFilters.FloatField("random_number").WithDecimal(),
+	}, map[string]usqlFilters.Sorter{"sort": usqlFilters.Sort("name", "random_number")})
+	sort := []string{"name", "random_number"}
+
+	tests := []sqliteFindTest{
+		{name: "UUIDs_returnsResourcesWithIDs", v: url.Values{"id": {strings.ToUpper(r1.ID), r3.ID}, "sort": sort},
+			expected: []*Resource{r1, r3}},
+		{name: "EnumValues_returnsEqualResources", v: url.Values{"name": {"Resource2", "Resource3"}, "sort": sort},
+			expected: []*Resource{r2, r4, r3}},
+		{name: "DecimalRange_returnsResourcesInRange", v: url.Values{"random_number": {"gte:1.5", "lt:2.5"},
+			"sort": sort}, expected: []*Resource{r2, r3}},
+		{name: "NotAllowedEnumValue_returnsWrongInputParameterError", v: url.Values{"name": {"Resource4"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testSelectContext(r, sqliteResourcesQuery))
+	}
+}
+
+func TestSQLiteFilterExpression(t *testing.T) {
+	filtersMap := map[string]usqlFilters.Filter{
+		"name":          usqlFilters.TextField("name"),
+		"random_number": usqlFilters.NumField("random_number"),
+	}
+	filtersMap[filters.ExpressionParam] = usqlFilters.Expression(filtersMap)
+	r, r1, _, r3, r4 := newSQLiteRepository(t, "db_usql_repository_test_sqlite_expression", filtersMap,
+		map[string]usqlFilters.Sorter{"sort": usqlFilters.Sort("name", "random_number")})
+	sort := []string{"name", "random_number"}
+
+	tests := []sqliteFindTest{
+		{name: "Or_returnsResourcesMatchingAnyCondition",
+			v:        url.Values{"filter": {`name="Resource1" OR random_number=0`}, "sort": sort},
+			expected: []*Resource{r1, r4}},
+		{name: "NestedGroupsAndNot_returnsMatchingResources",
+			v: url.Values{"filter": {"NOT (name=Resource1 OR random_number=gte:2) AND random_number=ne:null"},
+				"sort": sort},
+			expected: []*Resource{r4}},
+		{name: "ExpressionAndFilters_mustAllMatch",
+			v:        url.Values{"filter": {"random_number=1 OR random_number=2"}, "name": {"Resource3"}, "sort": sort},
+			expected: []*Resource{r3}},
+	}
+	for _, expr := range []string{"sort=name OR name=Resource1", "filter=name=x", "name=Resource1 OR (name=Resource2"} {
+		tests = append(tests, sqliteFindTest{name: "Invalid_" + expr + "_returnsWrongInputParameterError",
+			v: url.Values{"filter": {expr}}, expectedErrKey: uerr.WrongInputParameterError})
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testSelectContext(r, sqliteResourcesQuery))
+	}
+}
+
+func TestSQLiteAliases(t *testing.T) {
+	filtersMap := map[string]usqlFilters.Filter{
+		"title": usqlFilters.TextField("lower(r.name)"),
+		"score": usqlFilters.NumField("r.random_number * 10"),
+	}
+	filtersMap[filters.ExpressionParam] = usqlFilters.Expression(filtersMap)
+	r, r1, r2, r3, r4 := newSQLiteRepository(t, "db_usql_repository_test_sqlite_aliases", filtersMap,
+		map[string]usqlFilters.Sorter{
+			"sort": usqlFilters.Sort().WithAlias("title", "lower(r.name)").WithAlias("score", "r.random_number"),
+		})
+	query := "SELECT r.id, r.name, r.random_number as randomnumber FROM resources r"
+
+	tests := []sqliteFindTest{
+		{name: "ExpressionFilter_returnsResourcesMatchingExpression",
+			v: url.Values{"title": {"resource1"}, "sort": {"title"}}, expected: []*Resource{r1}},
+		{name: "ComputedFilterAndAliasSort_returnsSortedResources",
+			v: url.Values{"score": {"gte:10"}, "sort": {"-score", "title"}}, expected: []*Resource{r2, r3, r1}},
+		{name: "ExpressionSort_returnsResourcesSortedByExpression",
+			v: url.Values{"sort": {"title", "score"}}, expected: []*Resource{r1, r2, r4, r3}},
+		{name: "AliasesInFilterExpression_returnsMatchingResources",
+			v: url.Values{"filter": {"title=resource2 OR score=10"}, "sort": {"score"}}, expected: []*Resource{r1, r2}},
+		{name: "SortByColumnOfAlias_returnsWrongInputParameterError",
+			v: url.Values{"sort": {"r.random_number"}}, expectedErrKey: uerr.WrongInputParameterError},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testSelectContext(r, query))
+	}
+}
+
+func TestSQLiteConstraints(t *testing.T) {
+	minNumber, maxNumber := 0.0, 10.0
+	filtersMap := map[string]usqlFilters.Filter{
+		"name": usqlFilters.TextField("name").WithConstraints(filters.Constraints{
+			Required: true,
+			Pattern:  regexp.MustCompile("^Resource"),
+		}),
+		"random_number": usqlFilters.NumField("random_number").WithConstraints(filters.Constraints{
+			MaxValues: 2,
+			Min:       &minNumber,
+			Max:       &maxNumber,
+		}),
+	}
+	filtersMap[filters.ExpressionParam] = usqlFilters.Expression(filtersMap).
+		WithConstraints(filters.Constraints{MaxValues: 1})
+	r, r1, _, _, _ := newSQLiteRepository(t, "db_usql_repository_test_sqlite_constraints", filtersMap,
+		map[string]usqlFilters.Sorter{"sort": usqlFilters.Sort("name")})
+	r = r.WithMaxLimit(20)
+
+	tests := []sqliteFindTest{
+		{name: "ValuesMeetingConstraints_returnsResources",
+			v:        url.Values{"name": {"Resource1"}, "random_number": {"gte:0", "lte:10"}, "limit": {"20"}},
+			expected: []*Resource{r1}},
+		{name: "RequiredFilterInExpressionCondition_returnsResources",
+			v:        url.Values{"filter": {"name=Resource1 AND (random_number=0 OR random_number=1)"}},
+			expected: []*Resource{r1}},
+		{name: "RequiredFilterInOrExpression_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"name=Resource1 OR name=Resource0"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "RequiredFilterInNotExpression_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"NOT name=Resource0"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ValueOutOfRange_returnsWrongInputParameterError",
+			v:              url.Values{"name": {"Resource1"}, "random_number": {"11"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ValueNotMatching_returnsWrongInputParameterError",
+			v: url.Values{"name": {"Other"}}, expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ExpressionViolation_returnsWrongInputParameterError",
+			v:              url.Values{"name": {"Resource1"}, "filter": {"random_number=1 OR random_number=-1"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "ExpressionOverMaxValues_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"random_number=1 OR random_number=2 OR (name=Resource1 AND random_number=3)"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "RepeatedExpressionOverMaxValues_returnsWrongInputParameterError",
+			v:              url.Values{"filter": {"name=Resource1", "name=Resource2"}},
+			expectedErrKey: uerr.WrongInputParameterError},
+		{name: "LimitOverMax_returnsWrongInputParameterError",
+			v: url.Values{"name": {"Resource1"}, "limit": {"21"}}, expectedErrKey: uerr.WrongInputParameterError},
+	}
+
+	for _, ft := range tests {
+		t.Run(ft.name, ft.testSelectContext(r, sqliteResourcesQuery))
+	}
+
+	t.Run("ViolationsOfEveryFilter_returnsWrongInputParameterErrorWithAllOfThem", func(t *testing.T) {
+		// ARRANGE
+		v := url.Values{"random_number": {"1", "2", "3"}}
+
+		// ACT
+		_, err := r.SelectContext(context.Background(), r.GetDBInstance(), sqliteResourcesQuery, v)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		require.Equal(t, `Filter "name" is required.`+"\n"+
+			`Filter "random_number" has 3 values, but it accepts at most 2.`, uerr.GetMessage(err))
+	})
+
+	t.Run("ViolationsInExpression_returnsWrongInputParameterErrorWithAllOfThem", func(t *testing.T) {
+		// ARRANGE
+		v := url.Values{"filter": {"name=Other AND NOT (random_number=1 OR random_number=2 OR random_number=3)"}}
+
+		// ACT
+		_, err := r.SelectContext(context.Background(), r.GetDBInstance(), sqliteResourcesQuery, v)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		require.Equal(t, `Invalid value for filter "name". It must match "^Resource".`+"\n"+
+			`Filter "random_number" has 3 values, but it accepts at most 2.`, uerr.GetMessage(err))
+	})
+}
@@ -0,0 +1,553 @@
+package usql
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/carlosarismendi/testhelper"
+	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/carlosarismendi/utils/udatabase/sqlite"
+	usqlFilters "github.com/carlosarismendi/utils/udatabase/usql/filters"
+	"github.com/carlosarismendi/utils/uerr"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSQLiteMigrations(t *testing.T) string {
+	migrationsDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(migrationsDir, "1_create_resources.up.sql"),
+		[]byte("CREATE TABLE resources (id UUID PRIMARY KEY, name TEXT NOT NULL, random_number INTEGER);"), 0o600)
+	require.NoError(t, err)
+	return migrationsDir
+}
+
+func newSQLiteTestDBHolder(t *testing.T, schemaName string) *TestDBHolder {
+	dbHolder := NewTestDBHolderFromConfig(&udatabase.DBConfig{
+		SchemaName:    schemaName,
+		MigrationsDir: writeSQLiteMigrations(t),
+		Dialect:       sqlite.Dialect{},
+	})
+	t.Cleanup(func() { _ = dbHolder.GetDBInstance().Close() })
+	return dbHolder
+}
+
+func TestSQLiteDialect(t *testing.T) {
+	dbHolder := newSQLiteTestDBHolder(t, "db_usql_repository_test_sqlite")
+
+	filtersMap := map[string]usqlFilters.Filter{
+		"name":          usqlFilters.TextField("name"),
+		"random_number": usqlFilters.NumField("random_number"),
+	}
+	sortersMap := map[string]usqlFilters.Sorter{
+		"sort": usqlFilters.Sort("name", "random_number"),
+	}
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, filtersMap, sortersMap)
+
+	r1 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1", RandomNumber: 1}
+	r2 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4602", Name: "Resource2", RandomNumber: 2}
+	r3 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4603", Name: "Resource3", RandomNumber: 2}
+	query := "SELECT id, name, random_number as randomnumber FROM resources"
+
+	populate := func(t *testing.T) {
+		dbHolder.Reset()
+		err := func() (rErr error) {
+			ctx, err := udatabase.BeginTx(context.Background(), r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+
+			for _, res := range []*Resource{r1, r2, r3} {
+				if err = save(ctx, r, res); err != nil {
+					return err
+				}
+			}
+			return nil
+		}()
+		require.NoError(t, err)
+	}
+
+	t.Run("SelectContextWithFiltersAndSorters_returnsMatchingResources", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		v := url.Values{"random_number": {"2"}, "sort": {"-name"}}
+
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, v)
+
+		// ASSERT
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, []*Resource{r3, r2}, rp.Resources)
+	})
+
+	t.Run("SelectContextWithNumFieldOperators_returnsResourcesInRange", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		v := url.Values{"random_number": {"gte:1", "lt:2"}}
+
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, v)
+
+		// ASSERT
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, []*Resource{r1}, rp.Resources)
+	})
+
+	t.Run("SelectContextWithNullAndNegation_returnsMatchingResources", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		tests := []struct {
+			v        url.Values
+			expected []*Resource
+		}{
+			{v: url.Values{"random_number": {"null"}}, expected: []*Resource{}},
+			{v: url.Values{"random_number": {"ne:null", "ne:2"}}, expected: []*Resource{r1}},
+			{v: url.Values{"name": {"ne:Resource1", "ne:Resource3"}}, expected: []*Resource{r2}},
+		}
+
+		for _, tt := range tests {
+			// ACT
+			rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, tt.v)
+
+			// ASSERT
+			require.NoError(t, err)
+			testhelper.RequireEqual(t, tt.expected, rp.Resources)
+		}
+	})
+
+	t.Run("SelectContextWithTotal_countsEveryMatchingResource", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		v := url.Values{"sort": {"name"}, "limit": {"1"}, "offset": {"1"}}
+
+		// ACT
+		rp, err := r.SelectContext(udatabase.WithTotal(context.Background()), r.GetDBInstance(), query, v)
+
+		// ASSERT
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, []*Resource{r2}, rp.Resources)
+		require.EqualValues(t, 3, rp.Total)
+		require.True(t, rp.HasMore)
+		require.EqualValues(t, 2, rp.NextOffset)
+	})
+
+	t.Run("SelectContextWithoutTotal_setsHasMoreWhenThePageIsFull", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		v := url.Values{"sort": {"name"}, "limit": {"2"}, "offset": {"1"}}
+
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, v)
+
+		// ASSERT
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, []*Resource{r2, r3}, rp.Resources)
+		require.EqualValues(t, 2, rp.Total)
+		require.True(t, rp.HasMore)
+		require.EqualValues(t, 3, rp.NextOffset)
+	})
+
+	t.Run("SelectCursorContext_pagesForwardAndBackward", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		ctx := context.Background()
+
+		// ACT
+		page1, err := r.SelectCursorContext(ctx, r.GetDBInstance(), query, url.Values{"sort": {"-name"}, "limit": {"2"}})
+		require.NoError(t, err)
+		page2, err := r.SelectCursorContext(ctx, r.GetDBInstance(), query,
+			url.Values{"sort": {"-name"}, "limit": {"2"}, "cursor": {page1.NextCursor}})
+		require.NoError(t, err)
+		prevPage, err := r.SelectCursorContext(ctx, r.GetDBInstance(), query,
+			url.Values{"sort": {"-name"}, "limit": {"2"}, "cursor": {page2.PrevCursor}})
+		require.NoError(t, err)
+
+		// ASSERT
+		testhelper.RequireEqual(t, []*Resource{r3, r2}, page1.Resources)
+		require.Empty(t, page1.PrevCursor)
+
+		testhelper.RequireEqual(t, []*Resource{r1}, page2.Resources)
+		require.Empty(t, page2.NextCursor)
+
+		testhelper.RequireEqual(t, []*Resource{r3, r2}, prevPage.Resources)
+		require.Empty(t, prevPage.PrevCursor)
+		require.NotEmpty(t, prevPage.NextCursor)
+	})
+
+	t.Run("SelectCursorContextWithCursorOfAnotherSort_returnsWrongInputParameterError", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		page, err := r.SelectCursorContext(context.Background(), r.GetDBInstance(), query,
+			url.Values{"sort": {"name"}, "limit": {"1"}})
+		require.NoError(t, err)
+
+		// ACT
+		_, err = r.SelectCursorContext(context.Background(), r.GetDBInstance(), query,
+			url.Values{"sort": {"-name"}, "cursor": {page.NextCursor}})
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("GetContext_returnsResource", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+
+		// ACT
+		actual, err := r.GetContext(context.Background(), r.GetDBInstance(), &Resource{}, query,
+			url.Values{"name": {r1.Name}})
+
+		// ASSERT
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, r1, actual)
+	})
+
+	t.Run("SavingDuplicatedPrimaryKey_returnsResourceAlreadyExistsError", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		defer r.Rollback(ctx)
+
+		// ACT
+		err = save(ctx, r, r1)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.ResourceAlreadyExistsError, uerr.GetKey(err))
+	})
+
+	t.Run("SavingMissingRequiredValue_returnsWrongInputParameterError", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		defer r.Rollback(ctx)
+
+		// ACT
+		_, err = r.GetTransaction(ctx).Exec("INSERT INTO resources (id) VALUES (?)", "5ceff18d-9039-44b5-a5d3-3d99653f4604")
+		err = r.HandleSaveOrUpdateError(nil, err)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+
+	t.Run("RollingBackTransaction_discardsChanges", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+		r4 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4604", Name: "Resource4", RandomNumber: 4}
+
+		// ACT
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, save(ctx, r, r4))
+		r.Rollback(ctx)
+
+		// ASSERT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		require.Len(t, rp.Resources, 3)
+	})
+
+	t.Run("Reset_removesData", func(t *testing.T) {
+		// ARRANGE
+		populate(t)
+
+		// ACT
+		dbHolder.Reset()
+
+		// ASSERT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+}
+
+func TestSQLiteIsolatedTestDBHolder(t *testing.T) {
+	cfg := &udatabase.DBConfig{
+		SchemaName:    "db_usql_repository_test_sqlite_isolated",
+		MigrationsDir: writeSQLiteMigrations(t),
+		Dialect:       sqlite.Dialect{},
+	}
+
+	for i := range 4 {
+		t.Run(fmt.Sprintf("ParallelTest%d_onlySeesItsOwnData", i), func(t *testing.T) {
+			t.Parallel()
+
+			// ARRANGE
+			dbHolder := NewIsolatedTestDBHolderFromConfig(t, cfg)
+			r := NewDBRepository[*Resource](dbHolder.DBHolder, nil, nil)
+			resource := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: fmt.Sprintf("Resource%d", i)}
+
+			ctx, err := r.Begin(context.Background())
+			require.NoError(t, err)
+			require.NoError(t, save(ctx, r, resource))
+			require.NoError(t, r.Commit(ctx))
+
+			// ACT
+			rp, err := r.SelectContext(context.Background(), r.GetDBInstance(),
+				"SELECT id, name, random_number as randomnumber FROM resources", url.Values{})
+
+			// ASSERT
+			require.NoError(t, err)
+			testhelper.RequireEqual(t, []*Resource{resource}, rp.Resources)
+		})
+	}
+}
+
+func TestSQLiteBeginTestTx(t *testing.T) {
+	dbHolder := newSQLiteTestDBHolder(t, "db_usql_repository_test_sqlite_test_tx")
+	dbHolder.Reset()
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, nil, nil)
+	query := "SELECT id, name, random_number as randomnumber FROM resources"
+
+	r1 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1"}
+	r2 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4602", Name: "Resource2"}
+
+	t.Run("NestedTransactions_mapToSavepoints", func(t *testing.T) {
+		// ARRANGE
+		ctx := dbHolder.BeginTestTx(t)
+
+		// ACT
+		err := func() (rErr error) {
+			ctx, err := udatabase.BeginTx(ctx, r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+			return save(ctx, r, r1)
+		}()
+		require.NoError(t, err)
+
+		err = func() (rErr error) {
+			ctx, err := udatabase.BeginTx(ctx, r)
+			if err != nil {
+				return err
+			}
+			defer udatabase.EndTx(ctx, r, &rErr)
+			if err = save(ctx, r, r2); err != nil {
+				return err
+			}
+			return uerr.NewError(uerr.GenericError, "Forced error.")
+		}()
+		require.Error(t, err)
+
+		// ASSERT
+		rp, err := r.SelectContext(ctx, r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, []*Resource{r1}, rp.Resources)
+	})
+
+	t.Run("NestedBeginOutsideTestTransaction_joinsTransaction", func(t *testing.T) {
+		// ARRANGE
+		ctx, err := r.Begin(context.Background())
+		require.NoError(t, err)
+		defer r.Rollback(ctx)
+
+		// ACT
+		nestedCtx, err := r.Begin(ctx)
+
+		// ASSERT
+		require.NoError(t, err)
+		_, isSavepoint := udatabase.SavepointFromContext(nestedCtx)
+		require.False(t, isSavepoint)
+		require.Same(t, r.GetTransaction(ctx), r.GetTransaction(nestedCtx))
+	})
+
+	t.Run("TestTransaction_isRolledBackOnCleanup", func(t *testing.T) {
+		// ACT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+}
+
+func TestSQLiteLoadFixtures(t *testing.T) {
+	dbHolder := newSQLiteTestDBHolder(t, "db_usql_repository_test_sqlite_fixtures")
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, nil, nil)
+	query := "SELECT id, name, random_number as randomnumber FROM resources ORDER BY name"
+
+	dir := t.TempDir()
+	yamlFixture := filepath.Join(dir, "resources.yaml")
+	require.NoError(t, os.WriteFile(yamlFixture, []byte(`
+resources:
+  - id: 5ceff18d-9039-44b5-a5d3-3d99653f4601
+    name: Resource1
+    random_number: 1
+`), 0o600))
+	sqlFixture := filepath.Join(dir, "resources.sql")
+	require.NoError(t, os.WriteFile(sqlFixture, []byte(
+		"INSERT INTO resources (id, name, random_number) VALUES ('5ceff18d-9039-44b5-a5d3-3d99653f4602', 'Resource2', 2);"),
+		0o600))
+	invalidFixture := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidFixture, []byte(`{"resources": [{"id": "{{ uuid }}"}]}`), 0o600))
+
+	r1 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4601", Name: "Resource1", RandomNumber: 1}
+	r2 := &Resource{ID: "5ceff18d-9039-44b5-a5d3-3d99653f4602", Name: "Resource2", RandomNumber: 2}
+
+	t.Run("LoadingFixtures_insertsRows", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+
+		// ACT
+		err := dbHolder.LoadFixtures(yamlFixture, sqlFixture)
+
+		// ASSERT
+		require.NoError(t, err)
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		testhelper.RequireEqual(t, []*Resource{r1, r2}, rp.Resources)
+	})
+
+	t.Run("FailingFixture_rollsBackEveryFixture", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+
+		// ACT
+		err := dbHolder.LoadFixtures(yamlFixture, invalidFixture)
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+
+	t.Run("LoadingFixturesInTestTx_rollsBackWithIt", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+
+		// ACT
+		t.Run("Test", func(t *testing.T) {
+			ctx := dbHolder.BeginTestTx(t)
+			require.NoError(t, dbHolder.LoadFixturesContext(ctx, yamlFixture))
+
+			rp, err := r.SelectContext(ctx, r.GetDBInstance(), query, url.Values{})
+			require.NoError(t, err)
+			testhelper.RequireEqual(t, []*Resource{r1}, rp.Resources)
+		})
+
+		// ASSERT
+		rp, err := r.SelectContext(context.Background(), r.GetDBInstance(), query, url.Values{})
+		require.NoError(t, err)
+		require.Empty(t, rp.Resources)
+	})
+
+	t.Run("RowsReferencingLaterTables_areInsertedAfterThem", func(t *testing.T) {
+		// ARRANGE
+		dbHolder.Reset()
+		_, err := dbHolder.GetDBInstance().Exec(
+			"CREATE TABLE notes (id TEXT PRIMARY KEY, resource_id UUID NOT NULL REFERENCES resources (id))")
+		require.NoError(t, err)
+
+		notesFixture := filepath.Join(dir, "notes.yaml")
+		require.NoError(t, os.WriteFile(notesFixture, []byte(`
+notes:
+  - id: note1
+    resource_id: 5ceff18d-9039-44b5-a5d3-3d99653f4601
+`), 0o600))
+
+		// ACT
+		err = dbHolder.LoadFixtures(notesFixture, yamlFixture)
+
+		// ASSERT
+		require.NoError(t, err)
+		var notes int
+		require.NoError(t, dbHolder.GetDBInstance().Get(&notes, "SELECT COUNT(*) FROM notes"))
+		require.Equal(t, 1, notes)
+	})
+}
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []udatabase.QueryEvent
+}
+
+func (o *recordingObserver) BeforeQuery(ctx context.Context) context.Context {
+	return ctx
+}
+
+func (o *recordingObserver) AfterQuery(_ context.Context, event udatabase.QueryEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+}
+
+func TestSQLiteQueryObserver(t *testing.T) {
+	// ARRANGE
+	observer := &recordingObserver{}
+	dbHolder := NewTestDBHolderFromConfig(&udatabase.DBConfig{
+		SchemaName:    "db_usql_repository_test_sqlite_observer",
+		MigrationsDir: writeSQLiteMigrations(t),
+		Dialect:       sqlite.Dialect{},
+		QueryObserver: observer,
+	})
+	t.Cleanup(func() { _ = dbHolder.GetDBInstance().Close() })
+	dbHolder.Reset()
+
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, map[string]usqlFilters.Filter{
+		"name": usqlFilters.TextField("name"),
+	}, nil)
+
+	// ACT
+	_, err := r.SelectContext(context.Background(), r.GetDBInstance(),
+		"SELECT id, name, random_number as randomnumber FROM resources", url.Values{"name": {"Resource1"}})
+
+	// ASSERT
+	require.NoError(t, err)
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	var filtered []udatabase.QueryEvent
+	for _, event := range observer.events {
+		if strings.Contains(event.Query, "FROM resources WHERE") {
+			filtered = append(filtered, event)
+		}
+	}
+	require.Len(t, filtered, 1)
+	require.Equal(t, []any{"Resource1"}, filtered[0].Args)
+	require.NoError(t, filtered[0].Err)
+}
+
+func TestSQLiteMetrics(t *testing.T) {
+	// ARRANGE
+	metrics := udatabase.NewMetrics()
+	dbHolder := NewTestDBHolderFromConfig(&udatabase.DBConfig{
+		SchemaName:    "db_usql_repository_test_sqlite_metrics",
+		MigrationsDir: writeSQLiteMigrations(t),
+		Dialect:       sqlite.Dialect{},
+		Metrics:       metrics,
+	})
+	t.Cleanup(func() { _ = dbHolder.GetDBInstance().Close() })
+	dbHolder.Reset()
+	r := NewDBRepository[*Resource](dbHolder.DBHolder, nil, nil)
+
+	// ACT
+	_, err := r.SelectContext(context.Background(), r.GetDBInstance(),
+		"SELECT id, name, random_number as randomnumber FROM resources", url.Values{})
+	require.NoError(t, err)
+	_, err = r.SelectContext(context.Background(), r.GetDBInstance(), "SELECT * FROM unknown_table", url.Values{})
+	require.Error(t, err)
+
+	// ASSERT
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	require.Contains(t, body,
+		`udatabase_pool_open_connections{host="localhost",schema="db_usql_repository_test_sqlite_metrics"}`)
+	require.Contains(t, body,
+		`udatabase_operation_duration_seconds_count{operation="SelectContext",repository="usql.Resource",outcome="ok"} 1`)
+	require.Contains(t, body,
+		`udatabase_operation_duration_seconds_count{operation="SelectContext",repository="usql.Resource",outcome="Error"} 1`)
+}
@@ -0,0 +1,48 @@
+package usql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const setLocalSearchPath = "SELECT set_config('search_path', $1, true)"
+
+// tenantQuerier runs every query in its own read-only transaction whose search_path
+// is the schema of a tenant, so the pooled connections are never left pointing to it.
+type tenantQuerier struct {
+	db         *sqlx.DB
+	searchPath string
+}
+
+func (q *tenantQuerier) Rebind(query string) string {
+	return q.db.Rebind(query)
+}
+
+func (q *tenantQuerier) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	return q.run(ctx, func(tx *sqlx.Tx) error {
+		return tx.GetContext(ctx, dest, query, args...)
+	})
+}
+
+func (q *tenantQuerier) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	return q.run(ctx, func(tx *sqlx.Tx) error {
+		return tx.SelectContext(ctx, dest, query, args...)
+	})
+}
+
+func (q *tenantQuerier) run(ctx context.Context, fn func(tx *sqlx.Tx) error) error {
+	tx, err := q.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, setLocalSearchPath, q.searchPath)
+	if err != nil {
+		return err
+	}
+
+	return fn(tx)
+}
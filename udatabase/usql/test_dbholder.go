@@ -1,9 +1,12 @@
 package usql
 
 import (
-	"fmt"
+	"context"
+	"errors"
 
 	"github.com/carlosarismendi/utils/udatabase"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/jmoiron/sqlx"
 )
 
 type TestDBHolder struct {
@@ -13,18 +16,113 @@ type TestDBHolder struct {
 func NewTestDBHolder(schemaName string) *TestDBHolder {
 	cfg := udatabase.NewDBConfigFromEnv()
 	cfg.SchemaName = schemaName
+	return NewTestDBHolderFromConfig(cfg)
+}
+
+// NewTestDBHolderFromConfig returns a *TestDBHolder initialized with the provided config.
+// It allows running the tests against other dialects, e.g. an in-memory SQLite database.
+func NewTestDBHolderFromConfig(cfg *udatabase.DBConfig) *TestDBHolder {
 	return &TestDBHolder{
 		DBHolder: NewDBHolder(cfg),
 	}
 }
 
-func (d *TestDBHolder) Reset() {
-	_, err := d.db.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE;", d.config.SchemaName))
-	if err != nil {
-		panic(err)
+// NewIsolatedTestDBHolder returns a *TestDBHolder configured by env variables, as NewTestDBHolder,
+// whose database is only used by t. See NewIsolatedTestDBHolderFromConfig.
+func NewIsolatedTestDBHolder(t udatabase.TB, schemaName string) *TestDBHolder {
+	t.Helper()
+	cfg := udatabase.NewDBConfigFromEnv()
+	cfg.SchemaName = schemaName
+	return NewIsolatedTestDBHolderFromConfig(t, cfg)
+}
+
+// NewIsolatedTestDBHolderFromConfig returns a migrated *TestDBHolder whose database is only used
+// by t, so tests can call t.Parallel() instead of sharing a schema that is Reset by each of them.
+// Its connections are closed and the database dropped with t.Cleanup. See udatabase.NewIsolatedDBConfig.
+func NewIsolatedTestDBHolderFromConfig(t udatabase.TB, cfg *udatabase.DBConfig) *TestDBHolder {
+	t.Helper()
+
+	d := NewTestDBHolderFromConfig(udatabase.NewIsolatedDBConfig(t, cfg))
+	t.Cleanup(func() {
+		for _, replica := range d.replicas {
+			_ = replica.Close()
+		}
+		_ = d.db.Close()
+	})
+
+	err := d.RunMigrations()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("%v", err)
 	}
+	return d
+}
 
+func (d *TestDBHolder) Reset() {
+	d.config.DropSchema(d.db.DB)
 	d.config.CreateSchema(d.db.DB)
-	d.config.SetSearchPath(d.db.DB)
 	_ = d.RunMigrations()
 }
+
+// BeginTestTx returns a ctx holding a transaction that is rolled back with t.Cleanup, as a faster
+// alternative to Reset. Repositories run the queries made with ctx in the transaction and the
+// Begin calls of the code under test create savepoints in it, so nothing is persisted. The
+// transaction must not be shared by parallel tests.
+func (d *TestDBHolder) BeginTestTx(t udatabase.TB) context.Context {
+	t.Helper()
+
+	tx, err := d.db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	t.Cleanup(func() { _ = tx.Rollback() })
+
+	return udatabase.WithTestTransaction(context.WithValue(context.Background(), ctxk(transactionName), tx))
+}
+
+// LoadFixtures loads the fixture files of paths in a transaction, which is rolled back if any of
+// them fails. See udatabase.ReadFixtures for their format.
+func (d *TestDBHolder) LoadFixtures(paths ...string) error {
+	return d.LoadFixturesContext(context.Background(), paths...)
+}
+
+// LoadFixturesContext is like LoadFixtures, but if ctx holds the transaction of BeginTestTx, the
+// fixtures are loaded in a savepoint of it and rolled back with it.
+func (d *TestDBHolder) LoadFixturesContext(ctx context.Context, paths ...string) (rErr error) {
+	r := NewDBRepository[struct{}](d.DBHolder, nil, nil)
+	ctx, err := udatabase.BeginTx(ctx, r)
+	if err != nil {
+		return err
+	}
+	defer udatabase.EndTx(ctx, r, &rErr)
+
+	db := &fixtureDB{r: r, tx: r.GetTransaction(ctx), bindType: d.config.GetDialect().BindType()}
+	return udatabase.LoadFixtures(ctx, db, d.config.GetDialect(), paths...)
+}
+
+// fixtureDB is the udatabase.FixtureDB of a transaction.
+type fixtureDB struct {
+	r        *DBrepository[struct{}]
+	tx       *sqlx.Tx
+	bindType int
+}
+
+func (db *fixtureDB) Exec(ctx context.Context, query string, args ...any) error {
+	if len(args) > 0 {
+		query = sqlx.Rebind(db.bindType, query)
+	}
+
+	res, err := db.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return db.r.HandleSaveOrUpdateError(res, err)
+	}
+	return nil
+}
+
+func (db *fixtureDB) QueryPairs(ctx context.Context, query string) ([][2]string, error) {
+	rows, err := db.tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return udatabase.ScanPairs(rows)
+}
@@ -5,9 +5,10 @@ import (
 )
 
 type UError struct {
-	key     string
-	message string
-	cause   error
+	key      string
+	message  string
+	cause    error
+	metadata map[string]string
 }
 
 // NewError creates a new UError with given key and message.
@@ -19,11 +20,12 @@ func NewError(key, message string) *UError {
 	}
 }
 
-// FromBytes only builds Key and Message build from bytes, ignoring Cause.
+// FromBytes only builds Key, Message and Metadata from bytes, ignoring Cause.
 func FromBytes(b []byte) (*UError, error) {
 	type errDetails struct {
-		Key     string `json:"key"`
-		Message string `json:"message"`
+		Key      string            `json:"key"`
+		Message  string            `json:"message"`
+		Metadata map[string]string `json:"metadata"`
 	}
 
 	type errStruct struct {
@@ -36,8 +38,9 @@ func FromBytes(b []byte) (*UError, error) {
 		return nil, err
 	}
 	uerr := &UError{
-		key:     e.Error.Key,
-		message: e.Error.Message,
+		key:      e.Error.Key,
+		message:  e.Error.Message,
+		metadata: e.Error.Metadata,
 	}
 	return uerr, nil
 }
@@ -53,15 +56,17 @@ func FromBytes(b []byte) (*UError, error) {
 // err.MarshalJSON() => {"error":{"key":"myKey","message":"myMessage","cause":{"error":{"key":"myKey","message":"myMessage"}}}}
 func (c *UError) MarshalJSON() ([]byte, error) {
 	type err struct {
-		Key     string `json:"key"`
-		Message string `json:"message"`
-		Cause   any    `json:"cause,omitempty"`
+		Key      string            `json:"key"`
+		Message  string            `json:"message"`
+		Metadata map[string]string `json:"metadata,omitempty"`
+		Cause    any               `json:"cause,omitempty"`
 	}
 
 	resErr := &err{
-		Key:     c.key,
-		Message: c.message,
-		Cause:   c.cause,
+		Key:      c.key,
+		Message:  c.message,
+		Metadata: c.metadata,
+		Cause:    c.cause,
 	}
 	if c.cause != nil {
 		if _, ok := c.cause.(*UError); !ok {
@@ -103,6 +108,15 @@ func (c *UError) WithCause(err error) *UError {
 	return c
 }
 
+// WithMetadata adds the pair key/value to the metadata of the error.
+func (c *UError) WithMetadata(key, value string) *UError {
+	if c.metadata == nil {
+		c.metadata = make(map[string]string)
+	}
+	c.metadata[key] = value
+	return c
+}
+
 // GetKey returns the key of the error if it is an UError or empty string if it is another error type.
 func GetKey(err error) string {
 	if uErr, ok := err.(*UError); ok {
@@ -120,3 +134,12 @@ func GetMessage(err error) string {
 
 	return err.Error()
 }
+
+// GetMetadata returns the metadata of the error if it is an UError, nil otherwise.
+func GetMetadata(err error) map[string]string {
+	if uErr, ok := err.(*UError); ok {
+		return uErr.metadata
+	}
+
+	return nil
+}
@@ -24,6 +24,22 @@ func TestUError_MarshalJSON(t *testing.T) {
 		)
 	})
 
+	t.Run("createFromBytesErrorWithMetadata", func(t *testing.T) {
+		// ARRANGE
+		originalErr := NewError("testKey", "testMessage").
+			WithMetadata("constraint", "users_email_key")
+
+		// ACT
+		actual, err := json.Marshal(originalErr)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t,
+			`{"error":{"key":"testKey","message":"testMessage","metadata":{"constraint":"users_email_key"}}}`,
+			string(actual),
+		)
+	})
+
 	t.Run("createFromBytesErrorWithFmtErrAsCause", func(t *testing.T) {
 		// ARRANGE
 		originalErr := NewError("testKey", "testMessage").
@@ -59,6 +75,28 @@ func TestUError_FromBytes(t *testing.T) {
 	})
 }
 
+func TestUError_GetMetadata(t *testing.T) {
+	t.Run("getMetadataFromUErrorFromBytes", func(t *testing.T) {
+		// ARRANGE
+		originalErr := NewError("testKey", "testMessage").
+			WithMetadata("table", "users").
+			WithMetadata("column", "email")
+		errBytes, err := originalErr.MarshalJSON()
+		require.NoError(t, err)
+
+		// ACT
+		actualErr, err := FromBytes(errBytes)
+
+		// ASSERT
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"table": "users", "column": "email"}, GetMetadata(actualErr))
+	})
+
+	t.Run("getMetadataFromOtherError", func(t *testing.T) {
+		require.Nil(t, GetMetadata(fmt.Errorf("err")))
+	})
+}
+
 func TestUError_UnmarshalJSON(t *testing.T) {
 	t.Run("createFromBytesErrorWithCause", func(t *testing.T) {
 		// ARRANGE
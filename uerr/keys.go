@@ -9,6 +9,8 @@ const (
 	WrongInputParameterError   = "WrongInputParameterError"
 	UnauthorizedError          = "UnauthorizedError"
 	ForbiddenError             = "ForbiddenError"
+	ConflictError              = "ConflictError"
+	TimeoutError               = "TimeoutError"
 )
 
 var httpCodes = map[string]int{
@@ -18,6 +20,8 @@ var httpCodes = map[string]int{
 	WrongInputParameterError:   http.StatusUnprocessableEntity,
 	UnauthorizedError:          http.StatusUnauthorized,
 	ForbiddenError:             http.StatusForbidden,
+	ConflictError:              http.StatusConflict,
+	TimeoutError:               http.StatusGatewayTimeout,
 }
 
 // HTTPCode maps the error keys to an HTTP status code.
@@ -60,6 +64,16 @@ func IsForbidden(err error) bool {
 	return Is(err, ForbiddenError)
 }
 
+// IsConflict returns true if the error is a ConflictError.
+func IsConflict(err error) bool {
+	return Is(err, ConflictError)
+}
+
+// IsTimeout returns true if the error is a TimeoutError.
+func IsTimeout(err error) bool {
+	return Is(err, TimeoutError)
+}
+
 func Is(err error, key string) bool {
 	return GetKey(err) == key
 }
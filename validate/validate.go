@@ -17,14 +17,35 @@ func Validate(v interface{}) error {
 		return nil
 	}
 
+	return validationError(err.(validator.ValidationErrors), "")
+}
+
+// Var validates value with the validator tag, e.g. "email" or "min=1,max=10", and returns an
+// error like the ones of Validate for the field name. It panics if tag is not a valid tag.
+func Var(name string, value interface{}, tag string) error {
+	err := validate.Var(value, tag)
+	if err == nil {
+		return nil
+	}
+
+	return validationError(err.(validator.ValidationErrors), name)
+}
+
+// validationError returns the error of errs, whose fields are named name when not empty.
+func validationError(errs validator.ValidationErrors, name string) error {
 	var sb strings.Builder
-	for i, err := range err.(validator.ValidationErrors) {
+	for i, err := range errs {
 		if i > 0 {
 			sb.WriteByte('\n')
 		}
 
+		field := name
+		if field == "" {
+			field = err.Field()
+		}
+
 		sb.WriteString("Invalid field ")
-		sb.WriteString(err.Field())
+		sb.WriteString(field)
 		sb.WriteString(": the value must be '")
 
 		tag := err.ActualTag()
@@ -61,3 +61,21 @@ func TestValidate(t *testing.T) {
 		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
 	})
 }
+
+func TestVar(t *testing.T) {
+	t.Run("ValidValue_returnsNoError", func(t *testing.T) {
+		err := Var("email", "user@example.com", "email")
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidValue_returnsWrongInputParameterError", func(t *testing.T) {
+		// ACT
+		err := Var("name", "Juan Francisco", "max=4")
+
+		// ASSERT
+		require.Error(t, err)
+		require.Equal(t, "Invalid field name: the value must be 'max=4'. The value received is 'Juan Francisco'.",
+			uerr.GetMessage(err))
+		require.Equal(t, uerr.WrongInputParameterError, uerr.GetKey(err))
+	})
+}